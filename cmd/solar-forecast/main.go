@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"text/tabwriter"
 	"time"
 
 	"github.com/b0d/solar-forecast/internal/adapters"
@@ -20,18 +21,39 @@ func main() {
 	configPath := flag.String("config", "config/application.properties", "Path to configuration file")
 	stateDir := flag.String("state", "~/.solar-forecast", "Directory for state files")
 	debug := flag.Bool("debug", false, "Enable debug logging")
+	printSchema := flag.Bool("print-schema", false, "Print the JSON Schema of webhook events and exit")
+	showOutbox := flag.Bool("outbox", false, "List queued and dead-lettered notifications and exit")
 	flag.Parse()
 
+	if *printSchema {
+		os.Stdout.Write(adapters.WebhookEventSchema)
+		return
+	}
+
 	// Initialize logger
 	logger := adapters.NewSimpleLogger(*debug)
 
-	logger.Info("Solar Forecast Warning System started", "version", "1.0")
+	logger.Info("Solar Forecast Warning System started", "version", version)
+
+	// Errors before the configuration is loaded use its locale setting, or English
+	locale, _ := domain.LookupLocale(config.LocaleSetting(*configPath))
+
+	// Failed notifications are queued next to the state file
+	outboxFilePath := filepath.Join(expandPath(*stateDir), "outbox.json")
+	outbox := adapters.NewFileOutboxAdapter(outboxFilePath, logger)
+	if *showOutbox {
+		if err := printOutbox(outbox, locale); err != nil {
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Outbox error: %v", err))
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Load configuration
 	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
 		logger.Error("Failed to load configuration", "error", err.Error())
-		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		fmt.Fprintln(os.Stderr, locale.Sprintf("Configuration error: %v", err))
 		os.Exit(1)
 	}
 
@@ -41,40 +63,164 @@ func main() {
 		"production_alert_threshold_kw", cfg.ProductionAlertThresholdKW,
 		"duration_threshold_hours", cfg.DurationThresholdHours,
 		"rated_capacity_kw", cfg.RatedCapacityKW,
+		"smtp_server", fmt.Sprintf("%s:%d", cfg.SMTP.Host, cfg.SMTP.Port),
 	)
 
 	// Expand state directory path
 	stateFilePath := filepath.Join(expandPath(*stateDir), "alert_state.json")
 	logger.Info("State file path", "path", stateFilePath)
 
+	// The locale was validated with the configuration
+	locale, _ = domain.LookupLocale(cfg.Locale)
+	logger.Info("Notification locale", "locale", locale.Tag)
+
+	// Load the built-in templates and any user overrides
+	templates, err := adapters.LoadTemplates(expandPath(cfg.TemplateDir), locale)
+	if err != nil {
+		logger.Error("Failed to load templates", "error", err.Error())
+		fmt.Fprintln(os.Stderr, locale.Sprintf("Template error: %v", err))
+		os.Exit(1)
+	}
+
 	// Initialize adapters
 	weatherProvider := adapters.NewOpenMeteoAdapter(cfg, logger)
-	emailNotifier := adapters.NewGmailAdapter(cfg, logger)
-	pushNotifier := adapters.NewPushoverAdapter(cfg, logger)
+	notifiers, err := newNotifierRegistry(cfg, templates, locale, logger)
+	if err != nil {
+		logger.Error("Failed to set up notification channels", "error", err.Error())
+		fmt.Fprintln(os.Stderr, locale.Sprintf("Configuration error: %v", err))
+		os.Exit(1)
+	}
 	stateRepository := adapters.NewFileStateAdapter(stateFilePath, logger)
 
+	// The forecast is only published when a broker is configured
+	var forecastPublisher domain.ForecastPublisher
+	if cfg.MQTT.BrokerURL != "" {
+		forecastPublisher = adapters.NewMQTTPublisher(cfg, logger)
+	}
+
+	// Ensemble forecasts are only fetched when models are configured
+	var ensembleProvider domain.EnsembleForecastProvider
+	if len(cfg.EnsembleModels) > 0 {
+		ensembleProvider = adapters.NewOpenMeteoEnsembleAdapter(cfg, logger)
+	}
+
 	// Create service
 	service := domain.NewSolarForecastService(
 		cfg,
 		weatherProvider,
-		emailNotifier,
-		pushNotifier,
+		ensembleProvider,
+		notifiers,
+		forecastPublisher,
 		stateRepository,
+		outbox,
 		logger,
 	)
 
 	// Run check with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.RunTimeoutSeconds)*time.Second)
 	defer cancel()
 
 	if err := service.CheckAndAlert(ctx); err != nil {
 		logger.Error("Service error", "error", err.Error())
+		fmt.Fprintln(os.Stderr, locale.Sprintf("Check failed: %v", err))
 		os.Exit(1)
 	}
 
 	logger.Info("Check completed successfully")
 }
 
+// newNotifierRegistry registers every notification channel that is configured
+// and not disabled with channel.<name>.enabled=false
+func newNotifierRegistry(cfg *domain.Config, templates *adapters.Templates, locale *domain.Locale, logger domain.Logger) (*domain.NotifierRegistry, error) {
+	push := func(notifier domain.PushNotifier) domain.Notifier {
+		return domain.NewPushChannel(notifier, locale, logger)
+	}
+	channels := []struct {
+		name       string
+		configured bool
+		notifier   func() domain.Notifier
+	}{
+		{domain.ChannelEmail, true, func() domain.Notifier {
+			return domain.NewEmailChannel(adapters.NewEmailAdapter(cfg, templates, logger))
+		}},
+		{domain.ChannelPushover, cfg.PushoverUserKey != "" && cfg.PushoverAPIToken != "" && cfg.PushoverUserKey != "YOUR_PUSHOVER_USER_KEY", func() domain.Notifier {
+			return push(adapters.NewPushoverAdapter(cfg, templates, logger))
+		}},
+		{domain.ChannelTelegram, cfg.TelegramBotToken != "" && cfg.TelegramChatID != "", func() domain.Notifier {
+			return push(adapters.NewTelegramAdapter(cfg, templates, logger))
+		}},
+		{domain.ChannelNtfy, cfg.Ntfy.Topic != "", func() domain.Notifier {
+			return push(adapters.NewNtfyAdapter(cfg, templates, logger))
+		}},
+		{domain.ChannelGotify, cfg.Gotify.ServerURL != "" && cfg.Gotify.AppToken != "", func() domain.Notifier {
+			return push(adapters.NewGotifyAdapter(cfg, templates, logger))
+		}},
+		{domain.ChannelSlack, cfg.SlackWebhookURL != "", func() domain.Notifier {
+			return push(adapters.NewSlackAdapter(cfg, templates, logger))
+		}},
+		{domain.ChannelDiscord, cfg.DiscordWebhookURL != "", func() domain.Notifier {
+			return push(adapters.NewDiscordAdapter(cfg, templates, logger))
+		}},
+		{domain.ChannelTeams, cfg.TeamsWebhookURL != "", func() domain.Notifier {
+			return push(adapters.NewTeamsAdapter(cfg, templates, logger))
+		}},
+		{domain.ChannelWebhook, cfg.Webhook.URL != "", func() domain.Notifier {
+			return push(adapters.NewWebhookAdapter(cfg, templates, logger))
+		}},
+	}
+
+	registry := domain.NewNotifierRegistry()
+	for _, channel := range channels {
+		settings := cfg.Channel(channel.name)
+		if !channel.configured {
+			continue
+		}
+		if !settings.Enabled {
+			logger.Info("Notification channel disabled", "channel", channel.name)
+			continue
+		}
+		timeout := time.Duration(settings.TimeoutSeconds) * time.Second
+		if err := registry.Register(channel.name, channel.notifier(), timeout); err != nil {
+			return nil, err
+		}
+	}
+	logger.Info("Notification channels", "channels", registry.Names())
+	return registry, nil
+}
+
+// printOutbox lists the notifications waiting for a retry and those given up on
+func printOutbox(outbox domain.NotificationOutbox, locale *domain.Locale) error {
+	ctx := context.Background()
+	pending, err := outbox.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	deadLetters, err := outbox.DeadLetters(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, locale.Sprintf("Pending (%d)", len(pending)))
+	if len(pending) > 0 {
+		fmt.Fprintln(w, locale.T("CREATED\tCHANNEL\tKIND\tEPISODE\tATTEMPTS\tNEXT ATTEMPT\tLAST ERROR"))
+		for _, m := range pending {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", m.CreatedAt.Format("2006-01-02 15:04"), m.Channel, m.Kind,
+				m.EpisodeID, m.Attempts, m.NextAttempt.Format("2006-01-02 15:04"), m.LastError)
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, locale.Sprintf("Dead letters (%d)", len(deadLetters)))
+	if len(deadLetters) > 0 {
+		fmt.Fprintln(w, locale.T("CREATED\tCHANNEL\tKIND\tEPISODE\tATTEMPTS\tLAST ERROR"))
+		for _, m := range deadLetters {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", m.CreatedAt.Format("2006-01-02 15:04"), m.Channel, m.Kind,
+				m.EpisodeID, m.Attempts, m.LastError)
+		}
+	}
+	return w.Flush()
+}
+
 // expandPath expands ~ to home directory
 func expandPath(path string) string {
 	if path == "~" || path == "~/" {
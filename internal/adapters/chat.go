@@ -0,0 +1,72 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// alertFact is one labelled value of an alert shown in chat cards
+type alertFact struct {
+	Label string
+	Value string
+}
+
+// alertFacts returns the key fields of an alert for the chat cards: the low
+// window, consecutive low hours, minimum output and expected recovery
+func alertFacts(analysis *domain.AlertAnalysis, settings TemplateSettings, locale *domain.Locale) []alertFact {
+	data := NewAlertTemplateData(analysis, settings, locale)
+
+	window := locale.T("none")
+	if !analysis.FirstLowProductionHour.IsZero() {
+		window = locale.FormatTime(analysis.FirstLowProductionHour, "Mon 15:04") + "–" + analysis.LastLowProductionHour.Format("15:04")
+	}
+	recovery := data.Recovery
+	if recovery == "" {
+		recovery = locale.T("none in the forecast")
+	}
+	facts := []alertFact{
+		{locale.T("Low window"), window},
+		{locale.T("Consecutive hours"), strconv.Itoa(analysis.ConsecutiveHourCount)},
+		{locale.T("Minimum output"), locale.Sprintf("%.1f kW", domain.NewAlertFingerprint(analysis).MinOutputKW)},
+		{locale.T("Expected Recovery"), recovery},
+	}
+	if data.Basis != "" {
+		facts = append(facts, alertFact{locale.T("Forecast basis"), data.Basis})
+	}
+	return facts
+}
+
+// postWebhook posts a chat webhook payload and reports any non-2xx response
+func postWebhook(ctx context.Context, client *http.Client, service, webhookURL, contentType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", service, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The webhook URL is the secret, so only the cause is reported
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send %s webhook: %w", service, err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s webhook error: %s (status %d)", service, strings.TrimSpace(string(respBody)), resp.StatusCode)
+	}
+	return nil
+}
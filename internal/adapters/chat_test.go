@@ -0,0 +1,52 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// newChatTestAnalysis returns an alert with a low window from 09:00 to 14:00 and recovery at 15:00
+func newChatTestAnalysis() *domain.AlertAnalysis {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	var evidence []domain.SolarProduction
+	for i := 0; i < 6; i++ {
+		evidence = append(evidence, domain.SolarProduction{Hour: start.Add(time.Duration(i) * time.Hour), EstimatedOutputKW: 0.4 + float64(i)*0.1})
+	}
+	return &domain.AlertAnalysis{
+		Severity:               domain.SeverityCritical,
+		FiredRules:             []domain.RuleResult{{Name: "low_production", Severity: domain.SeverityCritical, Evidence: evidence}},
+		ConsecutiveHourCount:   6,
+		FirstLowProductionHour: start,
+		LastLowProductionHour:  start.Add(5 * time.Hour),
+		HasRecovery:            true,
+		RecoveryHour:           start.Add(6 * time.Hour),
+	}
+}
+
+func TestAlertFacts(t *testing.T) {
+	facts := alertFacts(newChatTestAnalysis(), TemplateSettings{}, nil)
+	want := []alertFact{
+		{"Low window", "Mon 09:00–14:00"},
+		{"Consecutive hours", "6"},
+		{"Minimum output", "0.4 kW"},
+		{"Expected Recovery", "Mon Mar 2, 15:00"},
+	}
+	if len(facts) != len(want) {
+		t.Fatalf("facts = %v, want %v", facts, want)
+	}
+	for i := range want {
+		if facts[i] != want[i] {
+			t.Errorf("fact %d = %v, want %v", i, facts[i], want[i])
+		}
+	}
+
+	es, _ := domain.LookupLocale("es")
+	analysis := newChatTestAnalysis()
+	analysis.AlertQuantile, analysis.EnsembleMembers = domain.AlertOnP10, 51
+	facts = alertFacts(analysis, TemplateSettings{}, es)
+	if facts[2] != (alertFact{"Producción mínima", "0,4 kW"}) || facts[4] != (alertFact{"Base de la previsión", "P10 de 51 miembros del conjunto"}) {
+		t.Errorf("localized facts = %v", facts)
+	}
+}
@@ -0,0 +1,132 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// DiscordAdapter implements PushNotifier by posting embeds to a Discord webhook
+type DiscordAdapter struct {
+	pushMessages
+	webhookURL string
+	client     *http.Client
+}
+
+// NewDiscordAdapter creates a new Discord adapter
+func NewDiscordAdapter(config *domain.Config, templates *Templates, logger domain.Logger) *DiscordAdapter {
+	return &DiscordAdapter{
+		pushMessages: newPushMessages(config, templates, logger),
+		webhookURL:   config.DiscordWebhookURL,
+		client:       &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// discordEmbed is a Discord rich embed
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Image       *discordEmbedImage  `json:"image,omitempty"`
+	Timestamp   string              `json:"timestamp"`
+}
+
+// discordEmbedField is one name/value pair of an embed
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// discordEmbedImage references the attached chart
+type discordEmbedImage struct {
+	URL string `json:"url"`
+}
+
+// discordColor is the embed's side bar color for a severity
+func discordColor(severity domain.Severity) int {
+	switch severity {
+	case domain.SeverityInfo:
+		return 0x3498DB // Blue
+	case domain.SeverityCritical:
+		return 0xE74C3C // Red
+	default:
+		return 0xF39C12 // Orange
+	}
+}
+
+// SendNotification posts the title and message as an embed, with the chart attached
+func (d *DiscordAdapter) SendNotification(ctx context.Context, title, message string, imageData []byte, severity domain.Severity) error {
+	return d.send(ctx, d.embed(title, message, severity), imageData)
+}
+
+// SendAlertNotification posts the alert as an embed with its key fields
+func (d *DiscordAdapter) SendAlertNotification(ctx context.Context, title, message string, analysis *domain.AlertAnalysis, imageData []byte) error {
+	embed := d.embed(title, message, analysis.Severity)
+	for _, fact := range alertFacts(analysis, d.settings, d.templates.Locale()) {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: fact.Label, Value: fact.Value, Inline: true})
+	}
+	return d.send(ctx, embed, imageData)
+}
+
+// embed builds the embed for a title and message
+func (d *DiscordAdapter) embed(title, message string, severity domain.Severity) discordEmbed {
+	return discordEmbed{
+		Title:       truncateRunes(title, 256),
+		Description: truncateRunes(message, 4096),
+		Color:       discordColor(severity),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// send posts the embed, as JSON or, with a chart, as multipart with the PNG
+// attached and shown as the embed's image
+func (d *DiscordAdapter) send(ctx context.Context, embed discordEmbed, imageData []byte) error {
+	if d.webhookURL == "" {
+		d.logger.Debug("Discord not configured, skipping notification")
+		return nil // Not an error, just not configured
+	}
+
+	if len(imageData) > 0 {
+		embed.Image = &discordEmbedImage{URL: "attachment://chart.png"}
+	}
+	payload, err := json.Marshal(map[string]any{"embeds": []discordEmbed{embed}})
+	if err != nil {
+		return fmt.Errorf("failed to encode discord message: %w", err)
+	}
+
+	contentType, body := "application/json", payload
+	if len(imageData) > 0 {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		writer.WriteField("payload_json", string(payload))
+		part, err := writer.CreateFormFile("files[0]", "chart.png")
+		if err != nil {
+			return fmt.Errorf("failed to create discord attachment: %w", err)
+		}
+		if _, err := part.Write(imageData); err != nil {
+			return fmt.Errorf("failed to write discord attachment: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return fmt.Errorf("failed to build discord request: %w", err)
+		}
+		contentType, body = writer.FormDataContentType(), buf.Bytes()
+	}
+
+	if err := postWebhook(ctx, d.client, "discord", d.webhookURL, contentType, body); err != nil {
+		d.logger.Error("Failed to send Discord notification", "error", err.Error())
+		return err
+	}
+
+	d.logger.Info("Discord notification sent successfully",
+		"title", embed.Title,
+		"has_image", len(imageData) > 0)
+	return nil
+}
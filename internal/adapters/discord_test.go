@@ -0,0 +1,74 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+func TestDiscordSendAlertNotificationWithChart(t *testing.T) {
+	var payload struct {
+		Embeds []discordEmbed `json:"embeds"`
+	}
+	var chart []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm: %v", err)
+			return
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("payload_json")), &payload); err != nil {
+			t.Errorf("payload_json: %v", err)
+		}
+		if file, header, err := r.FormFile("files[0]"); err == nil && header.Filename == "chart.png" {
+			chart, _ = io.ReadAll(file)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	adapter := NewDiscordAdapter(&domain.Config{DiscordWebhookURL: server.URL}, nil, nopLogger{})
+	err := adapter.SendAlertNotification(context.Background(), "🚨 Solar Production Critical", "Production below 2 kW", newChatTestAnalysis(), []byte("png"))
+	if err != nil {
+		t.Fatalf("SendAlertNotification: %v", err)
+	}
+
+	if string(chart) != "png" {
+		t.Errorf("attachment = %q, want the chart", chart)
+	}
+	if len(payload.Embeds) != 1 {
+		t.Fatalf("embeds = %+v", payload.Embeds)
+	}
+	embed := payload.Embeds[0]
+	if embed.Title != "🚨 Solar Production Critical" || embed.Color != 0xE74C3C || embed.Image == nil || embed.Image.URL != "attachment://chart.png" {
+		t.Errorf("embed = %+v, want the critical color and the attached chart", embed)
+	}
+	if len(embed.Fields) != 4 || embed.Fields[0] != (discordEmbedField{Name: "Low window", Value: "Mon 09:00–14:00", Inline: true}) {
+		t.Errorf("fields = %+v", embed.Fields)
+	}
+}
+
+func TestDiscordSendNotificationWithoutChart(t *testing.T) {
+	var contentType string
+	var payload struct {
+		Embeds []discordEmbed `json:"embeds"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	adapter := NewDiscordAdapter(&domain.Config{DiscordWebhookURL: server.URL}, nil, nopLogger{})
+	if err := adapter.SendNotification(context.Background(), "✅ Solar Production Recovered", "Back above thresholds", nil, domain.SeverityInfo); err != nil {
+		t.Fatalf("SendNotification: %v", err)
+	}
+	if contentType != "application/json" || len(payload.Embeds) != 1 || payload.Embeds[0].Image != nil || payload.Embeds[0].Description != "Back above thresholds" {
+		t.Errorf("content type %q, embeds %+v, want a JSON embed without an image", contentType, payload.Embeds)
+	}
+}
@@ -0,0 +1,857 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// EmailAdapter implements EmailNotifier over SMTP
+type EmailAdapter struct {
+	smtp                   *SMTPClient
+	senderEmail            string
+	recipients             []domain.EmailRecipient
+	templates              *Templates
+	settings               TemplateSettings // Configuration values exposed to the templates
+	logger                 domain.Logger
+	daylightGHIThreshold   float64 // Store GHI threshold for daylight detection
+	nightCompressionFactor float64 // Compression factor for nighttime hours in charts
+	chartDisplayHours      int     // Hours to display in charts
+	inverterACLimitKW      float64 // Inverter AC limit, drawn when hours are clipped
+}
+
+// NewEmailAdapter creates a new email adapter for the configured mail server
+func NewEmailAdapter(config *domain.Config, templates *Templates, logger domain.Logger) *EmailAdapter {
+	return &EmailAdapter{
+		smtp:                   NewSMTPClient(config.SMTP, logger),
+		senderEmail:            config.EmailSender,
+		recipients:             config.Recipients,
+		templates:              templates,
+		settings:               NewTemplateSettings(config),
+		logger:                 logger,
+		daylightGHIThreshold:   config.DaylightGHIThreshold,
+		nightCompressionFactor: config.NightCompressionFactor,
+		chartDisplayHours:      config.ChartDisplayHours,
+		inverterACLimitKW:      config.InverterACLimitKW,
+	}
+}
+
+// SendAlert sends an HTML-formatted alert email with graphs
+func (a *EmailAdapter) SendAlert(ctx context.Context, analysis *domain.AlertAnalysis) error {
+	if !analysis.CriteriaTriggered.AnyTriggered {
+		a.logger.Info("No alert criteria triggered, skipping email")
+		return nil
+	}
+
+	recipients := domain.RecipientsFor(a.recipients, domain.EmailAlert, analysis.Severity)
+	if len(recipients) == 0 {
+		a.logger.Info("No recipient wants alerts of this severity, skipping email", "severity", string(analysis.Severity))
+		return nil
+	}
+
+	chart := a.chartPNG(analysis.AllProductionHours)
+	data := NewAlertTemplateData(analysis, a.settings, a.templates.Locale())
+	data.Chart = a.productionChartHTML(analysis.AllProductionHours, chart != nil)
+	message, err := a.renderMessage("alert", data, chart)
+	if err != nil {
+		return fmt.Errorf("failed to render alert email: %w", err)
+	}
+
+	if err := a.send(ctx, recipients, message); err != nil {
+		a.logger.Error("Failed to send email", "error", err.Error())
+		return fmt.Errorf("failed to send alert email: %w", err)
+	}
+
+	a.logger.Info("Alert email sent successfully", "recipients", len(recipients))
+	return nil
+}
+
+// send delivers one message to all recipients. To and Cc recipients are listed
+// in the headers; Bcc recipients only appear in the SMTP envelope.
+func (a *EmailAdapter) send(ctx context.Context, recipients []domain.EmailRecipient, message emailMessage) error {
+	header := map[domain.RecipientField][]string{}
+	var envelope []string
+	seen := make(map[string]bool)
+	for _, r := range recipients {
+		addr, err := mail.ParseAddress(r.Address)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %s: %w", r.Name, err)
+		}
+		if seen[strings.ToLower(addr.Address)] {
+			continue
+		}
+		seen[strings.ToLower(addr.Address)] = true
+		envelope = append(envelope, addr.Address)
+		if r.Field != domain.RecipientBcc {
+			header[r.Field] = append(header[r.Field], addr.String())
+		}
+	}
+
+	msg, err := a.formatMessage(message, header[domain.RecipientTo], header[domain.RecipientCc])
+	if err != nil {
+		return err
+	}
+	return a.smtp.Send(ctx, a.senderEmail, envelope, msg)
+}
+
+// generateCloudCoverLineChart generates an SVG line chart for cloud cover
+func (a *EmailAdapter) generateCloudCoverLineChart(hours []domain.ForecastHour) string {
+	var html strings.Builder
+	
+	// Sort by hour and limit to next 12 hours
+	sort.Slice(hours, func(i, j int) bool {
+		return hours[i].Hour.Before(hours[j].Hour)
+	})
+
+	if len(hours) == 0 {
+		return ""
+	}
+
+	// Limit to 12 hours
+	if len(hours) > 12 {
+		hours = hours[:12]
+	}
+
+	chartWidth := 800
+	chartHeight := 360
+	padding := 60
+	
+	// Create point data
+	var points []float64
+	for i, hour := range hours {
+		if i >= 12 {
+			break
+		}
+		points = append(points, float64(hour.CloudCover))
+	}
+
+	maxValue := float64(100)
+	minValue := float64(0)
+
+	html.WriteString(`
+            <div class="chart-section">
+                <div class="chart-title">☁️ Cloud Cover Forecast (Next 12 Hours)</div>
+                <svg viewBox="0 0 ` + fmt.Sprintf("%d %d", chartWidth, chartHeight) + `" xmlns="http://www.w3.org/2000/svg">
+                    <defs>
+                        <linearGradient id="cloudGradient" x1="0%" y1="0%" x2="0%" y2="100%">
+                            <stop offset="0%" style="stop-color:#667eea;stop-opacity:0.3" />
+                            <stop offset="100%" style="stop-color:#667eea;stop-opacity:0.05" />
+                        </linearGradient>
+                        <style>
+                            .chart-line { fill: none; stroke: #667eea; stroke-width: 3; stroke-linecap: round; stroke-linejoin: round; }
+                            .chart-area { fill: url(#cloudGradient); }
+                            .chart-grid { stroke: #e0e6ed; stroke-width: 1; }
+                            .chart-label { font-size: 12px; fill: #7f8c8d; }
+                            .chart-value { font-size: 11px; fill: #667eea; font-weight: bold; }
+                            .chart-dot { fill: #667eea; r: 4; }
+                        </style>
+                    </defs>
+                    
+                    <!-- Grid lines -->
+`)
+
+	// Add grid lines
+	for i := 0; i <= 4; i++ {
+		y := float64(padding) + (float64(i) / 4.0) * float64(chartHeight-2*padding)
+		html.WriteString(fmt.Sprintf(`                    <line class="chart-grid" x1="%d" y1="%.0f" x2="%d" y2="%.0f" />
+`, padding, y, chartWidth-padding, y))
+		value := maxValue - (float64(i)/4.0)*(maxValue-minValue)
+		html.WriteString(fmt.Sprintf(`                    <text class="chart-label" x="%d" y="%.0f" text-anchor="end">%.1f kW</text>
+`, padding-10, y+4, value))
+	}
+
+	// Calculate path data
+	pointSpacing := float64(chartWidth-2*padding) / float64(len(points)-1)
+	pathData := fmt.Sprintf("M %d %d", padding, chartHeight-padding)
+	var areaPath strings.Builder
+	areaPath.WriteString(fmt.Sprintf("M %d %d", padding, chartHeight-padding))
+
+	for i, point := range points {
+		x := float64(padding) + float64(i)*pointSpacing
+		y := float64(chartHeight-padding) - ((point-minValue)/(maxValue-minValue))*float64(chartHeight-2*padding)
+		pathData += fmt.Sprintf(" L %.1f %.1f", x, y)
+		areaPath.WriteString(fmt.Sprintf(" L %.1f %.1f", x, y))
+	}
+	
+	areaPath.WriteString(fmt.Sprintf(" L %d %d Z", chartWidth-padding, chartHeight-padding))
+
+	// Add area under curve
+	html.WriteString(fmt.Sprintf(`                    <path class="chart-area" d="%s" />
+`, areaPath.String()))
+
+	// Add line
+	html.WriteString(fmt.Sprintf(`                    <path class="chart-line" d="%s" />
+`, pathData))
+
+	// Add points and labels
+	for i, point := range points {
+		if i%4 == 0 || i == len(points)-1 { // Show every 4th point to avoid clutter
+			x := float64(padding) + float64(i)*pointSpacing
+			y := float64(chartHeight-padding) - ((point-minValue)/(maxValue-minValue))*float64(chartHeight-2*padding)
+			html.WriteString(fmt.Sprintf(`                    <circle class="chart-dot" cx="%.1f" cy="%.1f" />
+`, x, y))
+			html.WriteString(fmt.Sprintf(`                    <text class="chart-value" x="%.1f" y="%.1f" text-anchor="middle">%.0f%%</text>
+`, x, y-12, point))
+		}
+	}
+
+	// Add x-axis labels (time) - every hour with larger font
+	html.WriteString(`                    <!-- X-axis labels -->
+`)
+	for i := 0; i < len(points); i++ { // Show every hour
+		if i < len(hours) {
+			x := float64(padding) + float64(i)*pointSpacing
+			hour := hours[i]
+			// Format: HH:00 (e.g., 14:00)
+			timeStr := hour.Hour.Format("15:00")
+			html.WriteString(fmt.Sprintf(`                    <text x="%.1f" y="%.0f" text-anchor="middle" style="font-size: 11px; fill: #2c3e50; font-weight: bold;">%s</text>
+`, x, float64(chartHeight-padding+30), timeStr))
+		}
+	}
+
+	// Add x-axis line
+	html.WriteString(fmt.Sprintf(`                    <line class="chart-grid" x1="%d" y1="%d" x2="%d" y2="%d" />
+`, padding, chartHeight-padding, chartWidth-padding, chartHeight-padding))
+
+	html.WriteString(`
+                </svg>
+            </div>
+`)
+	return html.String()
+}
+
+// generateGHILineChart generates an SVG line chart for solar irradiance (GHI)
+func (a *EmailAdapter) generateGHILineChart(hours []domain.ForecastHour) string {
+	var html strings.Builder
+	
+	// Sort by hour and limit to next 48
+	sort.Slice(hours, func(i, j int) bool {
+		return hours[i].Hour.Before(hours[j].Hour)
+	})
+
+	if len(hours) == 0 {
+		return ""
+	}
+
+	// Limit to 12 hours
+	if len(hours) > 12 {
+		hours = hours[:12]
+	}
+
+	chartWidth := 800
+	chartHeight := 360
+	padding := 60
+	
+	// Create point data
+	var points []float64
+	var maxGHI float64
+	for i, hour := range hours {
+		if i >= 12 {
+			break
+		}
+		points = append(points, hour.GlobalHorizontalIrradiance)
+		if hour.GlobalHorizontalIrradiance > maxGHI {
+			maxGHI = hour.GlobalHorizontalIrradiance
+		}
+	}
+
+	if maxGHI == 0 {
+		maxGHI = 1000
+	}
+	minValue := float64(0)
+
+	html.WriteString(`
+            <div class="chart-section">
+                <div class="chart-title">☀️ Solar Irradiance - GHI (Next 12 Hours)</div>
+                <svg viewBox="0 0 ` + fmt.Sprintf("%d %d", chartWidth, chartHeight) + `" xmlns="http://www.w3.org/2000/svg">
+                    <defs>
+                        <linearGradient id="ghiGradient" x1="0%" y1="0%" x2="0%" y2="100%">
+                            <stop offset="0%" style="stop-color:#ffc107;stop-opacity:0.3" />
+                            <stop offset="100%" style="stop-color:#ffc107;stop-opacity:0.05" />
+                        </linearGradient>
+                        <style>
+                            .ghi-line { fill: none; stroke: #ffc107; stroke-width: 3; stroke-linecap: round; stroke-linejoin: round; }
+                            .ghi-area { fill: url(#ghiGradient); }
+                            .chart-grid { stroke: #e0e6ed; stroke-width: 1; }
+                            .chart-label { font-size: 12px; fill: #7f8c8d; }
+                            .ghi-value { font-size: 11px; fill: #ffc107; font-weight: bold; }
+                            .ghi-dot { fill: #ffc107; r: 4; }
+                        </style>
+                    </defs>
+                    
+                    <!-- Grid lines -->
+`)
+
+	// Add grid lines
+	for i := 0; i <= 4; i++ {
+		y := float64(padding) + (float64(i) / 4.0) * float64(chartHeight-2*padding)
+		html.WriteString(fmt.Sprintf(`                    <line class="chart-grid" x1="%d" y1="%.0f" x2="%d" y2="%.0f" />
+`, padding, y, chartWidth-padding, y))
+		value := maxGHI - (float64(i)/4.0)*(maxGHI-minValue)
+		html.WriteString(fmt.Sprintf(`                    <text class="chart-label" x="%d" y="%.0f" text-anchor="end">%.0f</text>
+`, padding-10, y+4, value))
+	}
+
+	// Calculate path data
+	pointSpacing := float64(chartWidth-2*padding) / float64(len(points)-1)
+	pathData := fmt.Sprintf("M %d %d", padding, chartHeight-padding)
+	var areaPath strings.Builder
+	areaPath.WriteString(fmt.Sprintf("M %d %d", padding, chartHeight-padding))
+
+	for i, point := range points {
+		x := float64(padding) + float64(i)*pointSpacing
+		y := float64(chartHeight-padding) - ((point-minValue)/(maxGHI-minValue))*float64(chartHeight-2*padding)
+		pathData += fmt.Sprintf(" L %.1f %.1f", x, y)
+		areaPath.WriteString(fmt.Sprintf(" L %.1f %.1f", x, y))
+	}
+	
+	areaPath.WriteString(fmt.Sprintf(" L %d %d Z", chartWidth-padding, chartHeight-padding))
+
+	// Add area under curve
+	html.WriteString(fmt.Sprintf(`                    <path class="ghi-area" d="%s" />
+`, areaPath.String()))
+
+	// Add line
+	html.WriteString(fmt.Sprintf(`                    <path class="ghi-line" d="%s" />
+`, pathData))
+
+	// Add points and labels
+	for i, point := range points {
+		if i%4 == 0 || i == len(points)-1 {
+			x := float64(padding) + float64(i)*pointSpacing
+			y := float64(chartHeight-padding) - ((point-minValue)/(maxGHI-minValue))*float64(chartHeight-2*padding)
+			html.WriteString(fmt.Sprintf(`                    <circle class="ghi-dot" cx="%.1f" cy="%.1f" />
+`, x, y))
+			html.WriteString(fmt.Sprintf(`                    <text class="ghi-value" x="%.1f" y="%.1f" text-anchor="middle">%.0f</text>
+`, x, y-12, point))
+		}
+	}
+
+	// Add x-axis labels (time) - every hour with larger font
+	html.WriteString(`                    <!-- X-axis labels -->
+`)
+	for i := 0; i < len(points); i++ { // Show every hour
+		if i < len(hours) {
+			x := float64(padding) + float64(i)*pointSpacing
+			hour := hours[i]
+			// Format: HH:00 (e.g., 14:00)
+			timeStr := hour.Hour.Format("15:00")
+			html.WriteString(fmt.Sprintf(`                    <text x="%.1f" y="%.0f" text-anchor="middle" style="font-size: 11px; fill: #2c3e50; font-weight: bold;">%s</text>
+`, x, float64(chartHeight-padding+30), timeStr))
+		}
+	}
+
+	// Add x-axis line
+	html.WriteString(fmt.Sprintf(`                    <line class="chart-grid" x1="%d" y1="%d" x2="%d" y2="%d" />
+`, padding, chartHeight-padding, chartWidth-padding, chartHeight-padding))
+
+	html.WriteString(`
+                </svg>
+            </div>
+`)
+	return html.String()
+}
+
+// filterFromNow returns up to 'count' production hours starting from current time
+func filterFromNow(hours []domain.SolarProduction, count int) []domain.SolarProduction {
+	now := time.Now()
+	currentHourStart := now.Truncate(time.Hour) // e.g., 14:23:45 → 14:00:00
+
+	var filtered []domain.SolarProduction
+	for _, h := range hours {
+		hourStart := h.Hour.Truncate(time.Hour)
+		// Include current hour and all future hours
+		if !hourStart.Before(currentHourStart) { // >= comparison
+			filtered = append(filtered, h)
+			if len(filtered) >= count {
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// upcomingDaylightHours returns the daylight hours among the next 'count' hours from now
+func upcomingDaylightHours(hours []domain.SolarProduction, count int, daylightGHIThreshold float64) []domain.SolarProduction {
+	var daylight []domain.SolarProduction
+	for _, h := range filterFromNow(hours, count) {
+		if h.GHI >= daylightGHIThreshold {
+			daylight = append(daylight, h)
+		}
+	}
+	return daylight
+}
+
+// calculateSmartSpacing calculates non-uniform X positions that compress nighttime hours
+func calculateSmartSpacing(production []domain.SolarProduction, totalWidth float64, daylightGHIThreshold float64, nightCompressionFactor float64) []float64 {
+
+	// Calculate total "weighted" hours
+	var totalWeightedHours float64
+	for _, prod := range production {
+		if prod.GHI >= daylightGHIThreshold {
+			totalWeightedHours += 1.0 // Full weight for daylight
+		} else {
+			totalWeightedHours += nightCompressionFactor // Compressed weight for night
+		}
+	}
+
+	// Calculate base spacing
+	baseSpacing := totalWidth / totalWeightedHours
+
+	// Calculate cumulative X positions
+	xPositions := make([]float64, len(production))
+	var cumulativeX float64
+	for i, prod := range production {
+		xPositions[i] = cumulativeX
+		if prod.GHI >= daylightGHIThreshold {
+			cumulativeX += baseSpacing
+		} else {
+			cumulativeX += baseSpacing * nightCompressionFactor
+		}
+	}
+
+	return xPositions
+}
+
+// productionChartHTML shows the production chart as the inline PNG image when it
+// is attached, falling back to the SVG chart otherwise
+func (a *EmailAdapter) productionChartHTML(production []domain.SolarProduction, inlineChart bool) template.HTML {
+	if len(production) == 0 {
+		return ""
+	}
+	locale := a.templates.Locale()
+	if !inlineChart {
+		return template.HTML(a.generateOutputLineChart(production))
+	}
+	return template.HTML(`
+            <div class="chart-section">
+                <div class="chart-title">⚡ ` + template.HTMLEscapeString(locale.T("Solar Production & Cloud Coverage Forecast (Next 48 Hours)")) + `</div>
+                <img src="cid:` + productionChartCID + `" alt="` + template.HTMLEscapeString(locale.T("Solar production and cloud coverage forecast")) + `" style="width: 100%; height: auto; display: block;" />
+            </div>
+`)
+}
+
+// generateOutputLineChart generates a dual-axis SVG chart with production (kW) and cloud coverage (%)
+func (a *EmailAdapter) generateOutputLineChart(production []domain.SolarProduction) string {
+	var html strings.Builder
+	locale := a.templates.Locale()
+
+	// Sort a copy by hour; the analysis is shared with the other channels
+	production = append([]domain.SolarProduction(nil), production...)
+	sort.Slice(production, func(i, j int) bool {
+		return production[i].Hour.Before(production[j].Hour)
+	})
+
+	// Filter to next N hours from current time
+	production = filterFromNow(production, a.chartDisplayHours)
+
+	// Debug: log time range
+	if a.logger != nil && len(production) > 0 {
+		a.logger.Debug("Email chart time range",
+			"now", time.Now().Format("2006-01-02 15:04:05"),
+			"first_hour", production[0].Hour.Format("2006-01-02 15:04:05"),
+			"last_hour", production[len(production)-1].Hour.Format("2006-01-02 15:04:05"),
+			"total_hours", len(production))
+	}
+
+	if len(production) == 0 {
+		return ""
+	}
+
+	chartWidth := 800
+	chartHeight := 360
+	padding := 60
+	rightPadding := 80 // Extra padding for right Y-axis
+
+	// Create production data (hourly kW values) - up to 48 hours
+	var productionPoints []float64
+	var maxProduction float64
+	var cloudPoints []float64
+	var rainPoints []float64
+
+	for _, prod := range production {
+		kw := prod.EstimatedOutputKW
+		if kw < 0 {
+			kw = 0
+		}
+		productionPoints = append(productionPoints, kw)
+		if kw > maxProduction {
+			maxProduction = kw
+		}
+		if prod.Quantiles != nil && prod.Quantiles.P90 > maxProduction {
+			maxProduction = prod.Quantiles.P90
+		}
+		cloudPoints = append(cloudPoints, float64(prod.CloudCover))
+		rainPoints = append(rainPoints, float64(prod.PrecipitationProbability))
+	}
+
+	// Round up maxProduction to nearest 1 kW for cleaner axis
+	maxProduction = float64(int(maxProduction) + 1)
+	if maxProduction < domain.MinChartProductionScale {
+		maxProduction = domain.MinChartProductionScale // Minimum scale for readable chart
+	}
+	minProduction := float64(0)
+
+	// Cloud coverage always 0-100%
+	maxCloud := 100.0
+	minCloud := 0.0
+
+	html.WriteString(`
+            <div class="chart-section">
+                <div class="chart-title">⚡ ` + template.HTMLEscapeString(locale.T("Solar Production & Cloud Coverage Forecast (Next 48 Hours)")) + `</div>
+                <svg viewBox="0 0 ` + fmt.Sprintf("%d %d", chartWidth+rightPadding, chartHeight) + `" xmlns="http://www.w3.org/2000/svg">
+                    <defs>
+                        <linearGradient id="outputGradient" x1="0%" y1="0%" x2="0%" y2="100%">
+                            <stop offset="0%" style="stop-color:#FFD60A;stop-opacity:0.4" />
+                            <stop offset="50%" style="stop-color:#F7931E;stop-opacity:0.2" />
+                            <stop offset="100%" style="stop-color:#FF6B35;stop-opacity:0.05" />
+                        </linearGradient>
+                        <linearGradient id="lineGradient" x1="0%" y1="0%" x2="100%" y2="0%">
+                            <stop offset="0%" style="stop-color:#FF6B35" />
+                            <stop offset="50%" style="stop-color:#F7931E" />
+                            <stop offset="100%" style="stop-color:#FFD60A" />
+                        </linearGradient>
+                        <filter id="shadow">
+                            <feDropShadow dx="0" dy="2" stdDeviation="3" flood-opacity="0.3"/>
+                        </filter>
+                        <style>
+                            .output-line { fill: none; stroke: url(#lineGradient); stroke-width: 4; stroke-linecap: round; stroke-linejoin: round; filter: url(#shadow); }
+                            .cloud-line { fill: none; stroke: #9b59b6; stroke-width: 3; stroke-linecap: round; stroke-linejoin: round; opacity: 0.7; stroke-dasharray: 5,5; }
+                            .rain-line { fill: none; stroke: #3498db; stroke-width: 3; stroke-linecap: round; stroke-linejoin: round; opacity: 0.7; stroke-dasharray: 8,4; }
+                            .output-area { fill: url(#outputGradient); }
+                            .chart-grid { stroke: #e0e6ed; stroke-width: 1; }
+                            .chart-label { font-size: 12px; fill: #7f8c8d; }
+                            .chart-label-right { font-size: 12px; fill: #9b59b6; }
+                            .output-value { font-size: 11px; fill: #FF6B35; font-weight: bold; }
+                            .cloud-value { font-size: 10px; fill: #9b59b6; font-weight: bold; }
+                            .rain-value { font-size: 10px; fill: #3498db; font-weight: bold; }
+                            .output-dot { fill: #FF6B35; r: 4; }
+                            .cloud-dot { fill: #9b59b6; r: 3; }
+                            .rain-dot { fill: #3498db; r: 3; }
+                            .legend { font-size: 13px; font-weight: bold; }
+                        </style>
+                    </defs>
+
+                    <!-- Legend -->
+                    <text class="legend" x="` + fmt.Sprintf("%d", padding) + `" y="25" fill="#FF6B35">● ` + template.HTMLEscapeString(locale.T("Production (kW)")) + `</text>
+                    <text class="legend" x="` + fmt.Sprintf("%d", padding+180) + `" y="25" fill="#9b59b6">● ` + template.HTMLEscapeString(locale.T("Cloud Coverage (%)")) + `</text>
+                    <text class="legend" x="` + fmt.Sprintf("%d", padding+380) + `" y="25" fill="#3498db">● ` + template.HTMLEscapeString(locale.T("Rain Chance (%)")) + `</text>
+
+                    <!-- Grid lines -->
+`)
+
+	// Add grid lines
+	for i := 0; i <= 4; i++ {
+		y := float64(padding) + (float64(i) / 4.0) * float64(chartHeight-2*padding)
+		html.WriteString(fmt.Sprintf(`                    <line class="chart-grid" x1="%d" y1="%.0f" x2="%d" y2="%.0f" />
+`, padding, y, chartWidth-padding, y))
+
+		// Left Y-axis labels (Production kW)
+		valueLeft := maxProduction - (float64(i)/4.0)*(maxProduction-minProduction)
+		html.WriteString(fmt.Sprintf(`                    <text class="chart-label" x="%d" y="%.0f" text-anchor="end">%s</text>
+`, padding-10, y+4, locale.Sprintf("%.1f kW", valueLeft)))
+
+		// Right Y-axis labels (Cloud %)
+		valueRight := maxCloud - (float64(i)/4.0)*(maxCloud-minCloud)
+		html.WriteString(fmt.Sprintf(`                    <text class="chart-label-right" x="%d" y="%.0f" text-anchor="start">%.0f%%</text>
+`, chartWidth-padding+10, y+4, valueRight))
+	}
+
+	// Calculate smart point spacing (compress nighttime hours)
+	totalChartWidth := float64(chartWidth - 2*padding)
+	xPositions := calculateSmartSpacing(production, totalChartWidth, a.daylightGHIThreshold, a.nightCompressionFactor)
+
+	// Build production path
+	productionPath := fmt.Sprintf("M %d %d", padding, chartHeight-padding)
+	var areaPath strings.Builder
+	areaPath.WriteString(fmt.Sprintf("M %d %d", padding, chartHeight-padding))
+
+	for i, point := range productionPoints {
+		x := float64(padding) + xPositions[i]
+		y := float64(chartHeight-padding) - ((point-minProduction)/(maxProduction-minProduction))*float64(chartHeight-2*padding)
+		productionPath += fmt.Sprintf(" L %.1f %.1f", x, y)
+		areaPath.WriteString(fmt.Sprintf(" L %.1f %.1f", x, y))
+	}
+
+	areaPath.WriteString(fmt.Sprintf(" L %.1f %d Z", float64(padding)+xPositions[len(xPositions)-1], chartHeight-padding))
+
+	// Build cloud coverage path (using right Y-axis scale)
+	cloudPath := ""
+	for i, cloud := range cloudPoints {
+		x := float64(padding) + xPositions[i]
+		y := float64(chartHeight-padding) - ((cloud-minCloud)/(maxCloud-minCloud))*float64(chartHeight-2*padding)
+		if i == 0 {
+			cloudPath = fmt.Sprintf("M %.1f %.1f", x, y)
+		} else {
+			cloudPath += fmt.Sprintf(" L %.1f %.1f", x, y)
+		}
+	}
+
+	// Build rain probability path (using right Y-axis scale, same as cloud)
+	rainPath := ""
+	maxRain := 100.0
+	minRain := 0.0
+	for i, rain := range rainPoints {
+		x := float64(padding) + xPositions[i]
+		y := float64(chartHeight-padding) - ((rain-minRain)/(maxRain-minRain))*float64(chartHeight-2*padding)
+		if i == 0 {
+			rainPath = fmt.Sprintf("M %.1f %.1f", x, y)
+		} else {
+			rainPath += fmt.Sprintf(" L %.1f %.1f", x, y)
+		}
+	}
+
+	// Build per-array contribution paths
+	arrayNames := chartArrayNames(production)
+	arrayPaths := make([]string, len(arrayNames))
+	for a := range arrayNames {
+		var path strings.Builder
+		for i, prod := range production {
+			kw := 0.0
+			if a < len(prod.Arrays) {
+				kw = prod.Arrays[a].OutputKW
+			}
+			x := float64(padding) + xPositions[i]
+			y := float64(chartHeight-padding) - ((kw-minProduction)/(maxProduction-minProduction))*float64(chartHeight-2*padding)
+			if i == 0 {
+				path.WriteString(fmt.Sprintf("M %.1f %.1f", x, y))
+			} else {
+				path.WriteString(fmt.Sprintf(" L %.1f %.1f", x, y))
+			}
+		}
+		arrayPaths[a] = path.String()
+	}
+
+	// Add area under production curve
+	html.WriteString(fmt.Sprintf(`                    <path class="output-area" d="%s" />
+`, areaPath.String()))
+
+	// Add ensemble uncertainty band (P10-P90 filled, P50 dashed)
+	if hasQuantiles(production) {
+		bandY := func(prod domain.SolarProduction, q domain.AlertQuantile) float64 {
+			return float64(chartHeight-padding) - ((prod.OutputAt(q)-minProduction)/(maxProduction-minProduction))*float64(chartHeight-2*padding)
+		}
+		var bandPath, medianPath strings.Builder
+		for i, prod := range production {
+			x := float64(padding) + xPositions[i]
+			cmd := "L"
+			if i == 0 {
+				cmd = "M"
+			}
+			bandPath.WriteString(fmt.Sprintf("%s %.1f %.1f ", cmd, x, bandY(prod, domain.AlertOnP90)))
+			medianPath.WriteString(fmt.Sprintf("%s %.1f %.1f ", cmd, x, bandY(prod, domain.AlertOnP50)))
+		}
+		for i := len(production) - 1; i >= 0; i-- {
+			bandPath.WriteString(fmt.Sprintf("L %.1f %.1f ", float64(padding)+xPositions[i], bandY(production[i], domain.AlertOnP10)))
+		}
+		bandPath.WriteString("Z")
+
+		html.WriteString(fmt.Sprintf(`                    <path d="%s" fill="#F7931E" fill-opacity="0.25" stroke="none" />
+                    <path d="%s" fill="none" stroke="#F7931E" stroke-width="2" stroke-dasharray="4,4" opacity="0.8" />
+                    <text class="legend" x="%d" y="25" fill="#F7931E">▒ P10–P90 / - - P50</text>
+`, bandPath.String(), strings.TrimSpace(medianPath.String()), padding+560))
+	}
+
+	// Add production line
+	html.WriteString(fmt.Sprintf(`                    <path class="output-line" d="%s" />
+`, productionPath))
+
+	// Add per-array lines with their legend entries
+	for a, name := range arrayNames {
+		c := arrayLineColor(a)
+		hex := fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+		html.WriteString(fmt.Sprintf(`                    <path d="%s" fill="none" stroke="%s" stroke-width="2" opacity="0.85" />
+`, arrayPaths[a], hex))
+		html.WriteString(fmt.Sprintf(`                    <text class="legend" x="%d" y="45" fill="%s">— %s (kW)</text>
+`, padding+a*160, hex, template.HTMLEscapeString(name)))
+	}
+
+	// Add cloud coverage line
+	html.WriteString(fmt.Sprintf(`                    <path class="cloud-line" d="%s" />
+`, cloudPath))
+
+	// Add rain probability line
+	html.WriteString(fmt.Sprintf(`                    <path class="rain-line" d="%s" />
+`, rainPath))
+
+	// Find minimum production value for highlighting
+	minProductionValue := productionPoints[0]
+	minProductionIndices := []int{0}
+	for i, kw := range productionPoints {
+		if kw < minProductionValue {
+			minProductionValue = kw
+			minProductionIndices = []int{i}
+		} else if kw == minProductionValue && i > 0 {
+			minProductionIndices = append(minProductionIndices, i)
+		}
+	}
+
+	// Add production points and labels (daylight hours only)
+	for i, point := range productionPoints {
+		x := float64(padding) + xPositions[i]
+		y := float64(chartHeight-padding) - ((point-minProduction)/(maxProduction-minProduction))*float64(chartHeight-2*padding)
+
+		// Skip dots and labels for nighttime hours
+		if production[i].GHI < a.daylightGHIThreshold {
+			continue
+		}
+
+		// Check if this is a minimum point
+		isMinimum := false
+		for _, minIdx := range minProductionIndices {
+			if i == minIdx {
+				isMinimum = true
+				break
+			}
+		}
+
+		// Draw dot with highlighting for minimum
+		if isMinimum {
+			html.WriteString(fmt.Sprintf(`                    <circle cx="%.1f" cy="%.1f" r="8" fill="#E74C3C" stroke="#C0392B" stroke-width="2" />
+`, x, y))
+		} else {
+			html.WriteString(fmt.Sprintf(`                    <circle class="output-dot" cx="%.1f" cy="%.1f" r="5" />
+`, x, y))
+		}
+
+		// Draw label for daylight hours
+		html.WriteString(fmt.Sprintf(`                    <text class="output-value" x="%.1f" y="%.1f" text-anchor="middle">%s</text>
+`, x, y-12, locale.Sprintf("%.1f kW", point)))
+	}
+
+	// Mark clipped hours: dashed line at the inverter limit and a ring around each clipped point
+	if a.inverterACLimitKW > 0 && hasClippedHours(production) {
+		limitY := float64(chartHeight-padding) - ((a.inverterACLimitKW-minProduction)/(maxProduction-minProduction))*float64(chartHeight-2*padding)
+		html.WriteString(fmt.Sprintf(`                    <line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#2C3E50" stroke-width="1.5" stroke-dasharray="6,4" opacity="0.8" />
+`, padding, limitY, chartWidth-padding, limitY))
+		html.WriteString(fmt.Sprintf(`                    <text x="%d" y="%.1f" text-anchor="end" style="font-size: 11px; fill: #2C3E50; font-weight: bold;">%s</text>
+`, chartWidth-padding-5, limitY-6, template.HTMLEscapeString(locale.Sprintf("Inverter limit %.1f kW (clipped ◯)", a.inverterACLimitKW))))
+		for i, prod := range production {
+			if !prod.Clipped {
+				continue
+			}
+			x := float64(padding) + xPositions[i]
+			y := float64(chartHeight-padding) - ((productionPoints[i]-minProduction)/(maxProduction-minProduction))*float64(chartHeight-2*padding)
+			html.WriteString(fmt.Sprintf(`                    <circle cx="%.1f" cy="%.1f" r="10" fill="none" stroke="#2C3E50" stroke-width="2" />
+`, x, y))
+		}
+	}
+
+	// Add cloud coverage dots and labels (daylight hours only)
+	for i, cloud := range cloudPoints {
+		// Skip dots and labels for nighttime hours
+		if production[i].GHI < a.daylightGHIThreshold {
+			continue
+		}
+
+		x := float64(padding) + xPositions[i]
+		y := float64(chartHeight-padding) - ((cloud-minCloud)/(maxCloud-minCloud))*float64(chartHeight-2*padding)
+		html.WriteString(fmt.Sprintf(`                    <circle class="cloud-dot" cx="%.1f" cy="%.1f" r="4" />
+`, x, y))
+
+		// Draw label for daylight hours
+		html.WriteString(fmt.Sprintf(`                    <text class="cloud-value" x="%.1f" y="%.1f" text-anchor="middle">%.0f%%</text>
+`, x, y+18, cloud))
+	}
+
+	// Add rain probability dots and labels (daylight hours only)
+	for i, rain := range rainPoints {
+		// Skip dots and labels for nighttime hours
+		if production[i].GHI < a.daylightGHIThreshold {
+			continue
+		}
+
+		x := float64(padding) + xPositions[i]
+		y := float64(chartHeight-padding) - ((rain-minRain)/(maxRain-minRain))*float64(chartHeight-2*padding)
+		html.WriteString(fmt.Sprintf(`                    <circle class="rain-dot" cx="%.1f" cy="%.1f" r="3" />
+`, x, y))
+
+		// Draw label only if rain chance > 0
+		if rain > 0 {
+			html.WriteString(fmt.Sprintf(`                    <text class="rain-value" x="%.1f" y="%.1f" text-anchor="middle">%.0f%%</text>
+`, x, y-12, rain))
+		}
+	}
+
+	// Add day change markers (vertical lines at midnight)
+	html.WriteString(`                    <!-- Day change markers -->
+`)
+	for i := 0; i < len(production); i++ {
+		if i > 0 && production[i].Hour.Day() != production[i-1].Hour.Day() {
+			x := float64(padding) + xPositions[i]
+			html.WriteString(fmt.Sprintf(`                    <line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#E74C3C" stroke-width="2" stroke-dasharray="5,3" opacity="0.6" />
+`, x, padding, x, chartHeight-padding))
+			// Add day label
+			dayLabel := template.HTMLEscapeString(locale.FormatTime(production[i].Hour, "Jan 2"))
+			html.WriteString(fmt.Sprintf(`                    <text x="%.1f" y="%d" text-anchor="middle" style="font-size: 10px; fill: #E74C3C; font-weight: bold;">%s</text>
+`, x, padding-5, dayLabel))
+		}
+	}
+
+	// Add x-axis labels (time) - daylight hours only
+	html.WriteString(`                    <!-- X-axis labels -->
+`)
+	for i := 0; i < len(productionPoints); i++ {
+		if i < len(production) {
+			prod := production[i]
+			// Only show time labels during daylight hours
+			if prod.GHI >= a.daylightGHIThreshold {
+				x := float64(padding) + xPositions[i]
+				// Format time as just hour (e.g., "14" instead of "14:00")
+				timeStr := prod.Hour.Format("15")
+				html.WriteString(fmt.Sprintf(`                    <text x="%.1f" y="%.0f" text-anchor="middle" style="font-size: 11px; fill: #2c3e50; font-weight: bold;">%s</text>
+`, x, float64(chartHeight-padding+30), timeStr))
+			}
+		}
+	}
+
+	// Add x-axis line
+	html.WriteString(fmt.Sprintf(`                    <line class="chart-grid" x1="%d" y1="%d" x2="%d" y2="%d" />
+`, padding, chartHeight-padding, chartWidth-padding, chartHeight-padding))
+
+	html.WriteString(`
+                </svg>
+            </div>
+`)
+	return html.String()
+}
+
+
+// SendRecoveryEmail sends an email indicating conditions have improved and alert is cleared
+func (a *EmailAdapter) SendRecoveryEmail(ctx context.Context, summary *domain.RecoverySummary) error {
+	// Episodes migrated from the per-day state have no severity; those alerts were warnings
+	severity := summary.Episode.Severity
+	if severity.Rank() == 0 {
+		severity = domain.SeverityWarning
+	}
+	recipients := domain.RecipientsFor(a.recipients, domain.EmailRecovery, severity)
+	if len(recipients) == 0 {
+		a.logger.Info("No recipient wants recovery emails for this episode, skipping email", "severity", string(severity))
+		return nil
+	}
+
+	var chart []byte
+	data := NewRecoveryTemplateData(summary, a.settings)
+	if summary.Analysis != nil {
+		chart = a.chartPNG(summary.Analysis.AllProductionHours)
+		data.Chart = a.productionChartHTML(summary.Analysis.AllProductionHours, chart != nil)
+	}
+	message, err := a.renderMessage("recovery", data, chart)
+	if err != nil {
+		return fmt.Errorf("failed to render recovery email: %w", err)
+	}
+
+	if err := a.send(ctx, recipients, message); err != nil {
+		a.logger.Error("Failed to send recovery email", "error", err.Error())
+		return fmt.Errorf("failed to send recovery email: %w", err)
+	}
+
+	a.logger.Info("Recovery email sent successfully", "recipients", len(recipients))
+	return nil
+}
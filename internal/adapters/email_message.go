@@ -0,0 +1,189 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// productionChartCID is the Content-ID the HTML part uses for the production chart
+const productionChartCID = "production-chart@solar-forecast"
+
+// emailMessage is an alert or recovery email before MIME encoding
+type emailMessage struct {
+	subject string
+	text    string // Plain-text alternative
+	html    string
+	images  []inlineImage // PNGs referenced from the HTML by cid:
+}
+
+// inlineImage is a PNG embedded in the HTML part
+type inlineImage struct {
+	contentID string
+	filename  string
+	data      []byte
+}
+
+// inlineImages wraps the rendered production chart, if any, as an inline image
+func inlineImages(chart []byte) []inlineImage {
+	if chart == nil {
+		return nil
+	}
+	return []inlineImage{{contentID: productionChartCID, filename: "production-chart.png", data: chart}}
+}
+
+// chartPNG renders the production chart for embedding, or nil when it cannot be
+// drawn and the HTML should keep its SVG chart
+func (a *EmailAdapter) chartPNG(production []domain.SolarProduction) []byte {
+	if len(production) == 0 {
+		return nil
+	}
+	chart, err := renderChartPNG(production, chartOptions{
+		displayHours:           a.chartDisplayHours,
+		daylightGHIThreshold:   a.daylightGHIThreshold,
+		nightCompressionFactor: a.nightCompressionFactor,
+		inverterACLimitKW:      a.inverterACLimitKW,
+		locale:                 a.templates.Locale(),
+	}, a.logger)
+	if err != nil {
+		a.logger.Warn("Failed to render email chart image, using SVG chart", "error", err.Error())
+		return nil
+	}
+	return chart
+}
+
+// formatMessage creates the complete MIME message: a multipart/alternative with
+// the plain-text summary and the HTML, which is wrapped in multipart/related
+// together with its inline images
+func (a *EmailAdapter) formatMessage(message emailMessage, to, cc []string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + a.senderEmail + "\r\n")
+	if len(to) == 0 && len(cc) == 0 {
+		// Every recipient is Bcc
+		buf.WriteString("To: undisclosed-recipients:;\r\n")
+	}
+	if len(to) > 0 {
+		buf.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	}
+	if len(cc) > 0 {
+		buf.WriteString("Cc: " + strings.Join(cc, ", ") + "\r\n")
+	}
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("UTF-8", message.subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	alternative := multipart.NewWriter(&buf)
+	buf.WriteString("Content-Type: multipart/alternative; boundary=\"" + alternative.Boundary() + "\"\r\n")
+	buf.WriteString("\r\n")
+
+	if err := writeQuotedPrintablePart(alternative, "text/plain; charset=\"UTF-8\"", message.text); err != nil {
+		return nil, err
+	}
+
+	if len(message.images) == 0 {
+		if err := writeQuotedPrintablePart(alternative, "text/html; charset=\"UTF-8\"", message.html); err != nil {
+			return nil, err
+		}
+		return closeMessage(&buf, alternative)
+	}
+
+	var relatedBody bytes.Buffer
+	related := multipart.NewWriter(&relatedBody)
+	if err := writeQuotedPrintablePart(related, "text/html; charset=\"UTF-8\"", message.html); err != nil {
+		return nil, err
+	}
+	for _, image := range message.images {
+		if err := writeImagePart(related, image); err != nil {
+			return nil, err
+		}
+	}
+	if err := related.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close related part: %w", err)
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", "multipart/related; type=\"text/html\"; boundary=\""+related.Boundary()+"\"")
+	part, err := alternative.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create related part: %w", err)
+	}
+	if _, err := part.Write(relatedBody.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to write related part: %w", err)
+	}
+	return closeMessage(&buf, alternative)
+}
+
+// closeMessage writes the closing boundary and returns the finished message
+func closeMessage(buf *bytes.Buffer, w *multipart.Writer) ([]byte, error) {
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close message: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeQuotedPrintablePart adds a UTF-8 text part encoded as quoted-printable
+func writeQuotedPrintablePart(w *multipart.Writer, contentType, body string) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to create %s part: %w", contentType, err)
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return fmt.Errorf("failed to write %s part: %w", contentType, err)
+	}
+	return qp.Close()
+}
+
+// writeImagePart adds an inline PNG, base64 encoded in 76 character lines
+func writeImagePart(w *multipart.Writer, image inlineImage) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", "image/png")
+	header.Set("Content-Transfer-Encoding", "base64")
+	header.Set("Content-ID", "<"+image.contentID+">")
+	header.Set("Content-Disposition", "inline; filename=\""+image.filename+"\"")
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to create image part: %w", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(image.data)
+	for len(encoded) > 76 {
+		if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return fmt.Errorf("failed to write image part: %w", err)
+		}
+		encoded = encoded[76:]
+	}
+	if _, err := part.Write([]byte(encoded + "\r\n")); err != nil {
+		return fmt.Errorf("failed to write image part: %w", err)
+	}
+	return nil
+}
+
+// renderMessage renders the subject ("<kind>_subject"), plain-text body
+// ("<kind>.txt.tmpl") and HTML body ("<kind>.html.tmpl") of an alert or recovery email
+func (a *EmailAdapter) renderMessage(kind string, data any, chart []byte) (emailMessage, error) {
+	subject, err := a.templates.Text(kind+"_subject", data)
+	if err != nil {
+		return emailMessage{}, err
+	}
+	text, err := a.templates.Text(kind+".txt.tmpl", data)
+	if err != nil {
+		return emailMessage{}, err
+	}
+	html, err := a.templates.HTML(kind+".html.tmpl", data)
+	if err != nil {
+		return emailMessage{}, err
+	}
+	return emailMessage{subject: subject, text: text, html: html, images: inlineImages(chart)}, nil
+}
@@ -0,0 +1,269 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// newTemplateAdapter creates an email adapter rendering the built-in templates
+func newTemplateAdapter(t *testing.T) *EmailAdapter {
+	t.Helper()
+	templates, err := LoadTemplates("", nil)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	return &EmailAdapter{
+		templates:            templates,
+		settings:             TemplateSettings{ProductionThresholdKW: 3.5, ChartDisplayHours: 24, DaylightGHIThreshold: 50},
+		logger:               nopLogger{},
+		chartDisplayHours:    24,
+		daylightGHIThreshold: 50,
+	}
+}
+
+func TestRenderRecoveryMessage(t *testing.T) {
+	adapter := newTemplateAdapter(t)
+
+	start := time.Now().Truncate(time.Hour)
+	var hours []domain.SolarProduction
+	for h := 0; h < 24; h++ {
+		hours = append(hours, domain.SolarProduction{Hour: start.Add(time.Duration(h) * time.Hour), EstimatedOutputKW: 3, GHI: 500})
+	}
+	summary := &domain.RecoverySummary{
+		Episode: domain.AlertEpisode{
+			StartedAt:   start.Add(-6 * time.Hour),
+			ExpectedEnd: start.Add(-time.Hour),
+			Initial: domain.AlertFingerprint{
+				FirstLowHour: start.Add(-5 * time.Hour),
+				LastLowHour:  start.Add(-2 * time.Hour),
+			},
+		},
+		Analysis:         &domain.AlertAnalysis{AllProductionHours: hours},
+		ResolvedAt:       start,
+		RestOfTodayKWh:   12.5,
+		TomorrowKWh:      31.2,
+		TomorrowComplete: true,
+	}
+
+	data := NewRecoveryTemplateData(summary, adapter.settings)
+	data.Chart = adapter.productionChartHTML(hours, false)
+	message, err := adapter.renderMessage("recovery", data, nil)
+	if err != nil {
+		t.Fatalf("renderMessage: %v", err)
+	}
+
+	if message.subject != "✅ Solar Production Alert Cleared - Conditions Recovered" {
+		t.Errorf("subject = %q", message.subject)
+	}
+	for _, want := range []string{
+		"Low Period Lasted:</strong> 4 hours (first alert forecast 4 hours)",
+		"12.5 kWh for the rest of today • 31.2 kWh tomorrow",
+		`class="chart-section"`,
+		"<svg",
+	} {
+		if !strings.Contains(message.html, want) {
+			t.Errorf("recovery body missing %q", want)
+		}
+	}
+
+	data.Chart = adapter.productionChartHTML(hours, true)
+	if inline, _ := adapter.templates.HTML("recovery.html.tmpl", data); !strings.Contains(inline, "cid:"+productionChartCID) || strings.Contains(inline, "<svg") {
+		t.Error("recovery body with an inline chart should reference the PNG instead of drawing the SVG")
+	}
+
+	for _, want := range []string{
+		"Low period lasted: 4 hours (first alert forecast 4 hours)",
+		"Expected energy: 12.5 kWh for the rest of today, 31.2 kWh tomorrow",
+	} {
+		if !strings.Contains(message.text, want) {
+			t.Errorf("recovery text missing %q:\n%s", want, message.text)
+		}
+	}
+}
+
+func TestRenderAlertMessage(t *testing.T) {
+	adapter := newTemplateAdapter(t)
+
+	start := time.Now().Truncate(time.Hour).Add(time.Hour)
+	var hours []domain.SolarProduction
+	for h := 0; h < 6; h++ {
+		hours = append(hours, domain.SolarProduction{Hour: start.Add(time.Duration(h) * time.Hour), EstimatedOutputKW: 1.2, CloudCover: 90, GHI: 300})
+	}
+	analysis := &domain.AlertAnalysis{
+		Severity:               domain.SeverityWarning,
+		FiredRules:             []domain.RuleResult{{Name: "low_production", Severity: domain.SeverityWarning, Message: "Low production\nfor 6 hours"}},
+		CriteriaTriggered:      domain.AlertCriteria{LowProductionDurationTriggered: true, AnyTriggered: true},
+		AllProductionHours:     hours,
+		ConsecutiveHourCount:   6,
+		TotalDaylightHours:     10,
+		FirstLowProductionHour: start,
+		LastLowProductionHour:  start.Add(5 * time.Hour),
+		HasRecovery:            true,
+		RecoveryHour:           start.Add(6 * time.Hour),
+		HoursUntilRecovery:     6,
+		AlertQuantile:          domain.AlertOnP10,
+		EnsembleMembers:        51,
+	}
+
+	message, err := adapter.renderMessage("alert", NewAlertTemplateData(analysis, adapter.settings, nil), nil)
+	if err != nil {
+		t.Fatalf("renderMessage: %v", err)
+	}
+	if message.subject != "⚠️ Solar Production Low - Weather Alert" {
+		t.Errorf("subject = %q", message.subject)
+	}
+	for _, want := range []string{
+		"<strong>[WARNING] low_production:</strong> Low production for 6 hours",
+		"Forecast (P10 of 51 ensemble members).",
+		"Production &lt; 3.5 kW",
+		"expected to rise above 3.5 kW",
+		"☁️",
+		"Heavily Overcast",
+	} {
+		if !strings.Contains(message.html, want) {
+			t.Errorf("alert body missing %q", want)
+		}
+	}
+	for _, want := range []string{
+		"[WARNING] low_production: Low production for 6 hours",
+		"Forecast (P10 of 51 ensemble members)",
+		"Production below 3.5 kW: 6 of 10 daylight hours",
+	} {
+		if !strings.Contains(message.text, want) {
+			t.Errorf("alert text missing %q:\n%s", want, message.text)
+		}
+	}
+
+	analysis.Update = &domain.AlertUpdate{Changes: []string{"Low period now ends at 15:00"}}
+	subject, err := adapter.templates.Text("alert_subject", NewAlertTemplateData(analysis, adapter.settings, nil))
+	if err != nil || subject != "🔄 Updated: ⚠️ Solar Production Low - Weather Alert" {
+		t.Errorf("update subject = %q (%v)", subject, err)
+	}
+}
+
+func TestEmailAdapterSendAddressesRecipients(t *testing.T) {
+	cert, _ := standInCertificate(t)
+	server := startSMTPStandIn(t, cert, false, "")
+	adapter := &EmailAdapter{
+		smtp:        NewSMTPClient(domain.SMTPConfig{Host: "127.0.0.1", Port: server.port(), TLSMode: domain.SMTPTLSNone, Auth: domain.SMTPAuthNone}, nopLogger{}),
+		senderEmail: "solar@example.com",
+		logger:      nopLogger{},
+	}
+
+	recipients := []domain.EmailRecipient{
+		{Name: "owner", Address: "Owner <owner@example.com>", Field: domain.RecipientTo},
+		{Name: "installer", Address: "installer@example.com", Field: domain.RecipientCc},
+		{Name: "archive", Address: "archive@example.com", Field: domain.RecipientBcc},
+		{Name: "duplicate", Address: "OWNER@example.com", Field: domain.RecipientBcc},
+	}
+	if err := adapter.send(context.Background(), recipients, emailMessage{subject: "subject", text: "body", html: "<p>body</p>"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	got := server.messages[0]
+	want := []string{"owner@example.com", "installer@example.com", "archive@example.com"}
+	if strings.Join(got.to, " ") != strings.Join(want, " ") {
+		t.Errorf("envelope = %v, want %v", got.to, want)
+	}
+	if !strings.Contains(got.data, `To: "Owner" <owner@example.com>`) || !strings.Contains(got.data, "Cc: <installer@example.com>") {
+		t.Errorf("To/Cc headers missing:\n%s", got.data)
+	}
+	if strings.Contains(got.data, "archive@example.com") {
+		t.Error("Bcc recipient must not appear in the headers")
+	}
+}
+
+func TestFormatMessageMultipart(t *testing.T) {
+	adapter := &EmailAdapter{senderEmail: "solar@example.com", logger: nopLogger{}}
+	png := []byte("\x89PNG fake image data")
+	raw, err := adapter.formatMessage(emailMessage{
+		subject: "⚠️ Solar Production Low",
+		text:    "Production below 2.0 kW",
+		html:    `<img src="cid:` + productionChartCID + `">`,
+		images:  inlineImages(png),
+	}, []string{"<owner@example.com>"}, nil)
+	if err != nil {
+		t.Fatalf("formatMessage: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); subject != "⚠️ Solar Production Low" {
+		t.Errorf("Subject = %q", subject)
+	}
+
+	parts := readMultipart(t, msg.Header.Get("Content-Type"), msg.Body, "multipart/alternative")
+	if len(parts) != 2 {
+		t.Fatalf("alternative has %d parts, want 2", len(parts))
+	}
+	if parts[0].contentType != "text/plain" || parts[0].body != "Production below 2.0 kW" {
+		t.Errorf("first alternative = %s %q, want the plain-text summary", parts[0].contentType, parts[0].body)
+	}
+
+	related := readMultipart(t, parts[1].header.Get("Content-Type"), strings.NewReader(parts[1].body), "multipart/related")
+	if len(related) != 2 || related[0].contentType != "text/html" || related[1].contentType != "image/png" {
+		t.Fatalf("related parts = %+v, want html and png", related)
+	}
+	if !strings.Contains(related[0].body, "cid:"+productionChartCID) {
+		t.Errorf("HTML does not reference the chart: %q", related[0].body)
+	}
+	if got := related[1].header.Get("Content-Id"); got != "<"+productionChartCID+">" {
+		t.Errorf("image Content-ID = %q", got)
+	}
+	if related[1].body != string(png) {
+		t.Errorf("image data = %q, want %q", related[1].body, png)
+	}
+}
+
+type mimePart struct {
+	header      textproto.MIMEHeader
+	contentType string
+	body        string
+}
+
+// readMultipart decodes the parts of a multipart body, undoing transfer encodings
+func readMultipart(t *testing.T, contentType string, body io.Reader, want string) []mimePart {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != want {
+		t.Fatalf("Content-Type = %q (%v), want %s", contentType, err, want)
+	}
+
+	var parts []mimePart
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		// multipart.Reader undoes quoted-printable itself
+		var data io.Reader = part
+		if part.Header.Get("Content-Transfer-Encoding") == "base64" {
+			data = base64.NewDecoder(base64.StdEncoding, part)
+		}
+		content, err := io.ReadAll(data)
+		if err != nil {
+			t.Fatalf("reading part: %v", err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts = append(parts, mimePart{header: part.Header, contentType: mediaType, body: string(content)})
+	}
+}
@@ -0,0 +1,291 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// maxDeadLetters is how many given-up notifications the outbox file keeps
+const maxDeadLetters = 50
+
+// outboxVersion is the version of the outbox file format. Files without a
+// version are version 1, which stored each message's analysis as the domain
+// types encoded it; they are migrated when read.
+const outboxVersion = 2
+
+// FileOutboxAdapter implements NotificationOutbox using a JSON file next to
+// the state file
+type FileOutboxAdapter struct {
+	outboxFile string
+	logger     domain.Logger
+}
+
+// outboxData represents the persistent outbox structure
+type outboxData struct {
+	Version     int                 `json:"version"`
+	Pending     []outboxMessageData `json:"pending,omitempty"`      // Oldest first
+	DeadLetters []outboxMessageData `json:"dead_letters,omitempty"` // Oldest first, without their content
+
+	// Content of the pending messages by notification ID, stored once for all
+	// channels the notification is queued for
+	Notifications map[string]*notificationContent `json:"notifications,omitempty"`
+}
+
+// outboxMessageData is the persisted form of an outbox message
+type outboxMessageData struct {
+	Key          string    `json:"key"`
+	Channel      string    `json:"channel"`
+	Kind         string    `json:"kind"`
+	EpisodeID    string    `json:"episode_id"`
+	Attempts     int       `json:"attempts"`
+	CreatedAt    time.Time `json:"created_at"`
+	NextAttempt  time.Time `json:"next_attempt"`
+	LastError    string    `json:"last_error,omitempty"`
+	Notification string    `json:"notification,omitempty"` // Key of the content in Notifications
+
+	// Content stored in every message by version 1, read once for migration
+	LegacyAnalysis *domain.AlertAnalysis   `json:"analysis,omitempty"`
+	LegacyRecovery *domain.RecoverySummary `json:"recovery,omitempty"`
+}
+
+// NewFileOutboxAdapter creates a new file-based notification outbox
+func NewFileOutboxAdapter(outboxFilePath string, logger domain.Logger) *FileOutboxAdapter {
+	// Ensure directory exists
+	dir := filepath.Dir(outboxFilePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logger.Error("Failed to create outbox directory", "error", err.Error())
+	}
+
+	return &FileOutboxAdapter{
+		outboxFile: outboxFilePath,
+		logger:     logger,
+	}
+}
+
+// Pending returns the messages waiting for delivery, oldest first
+func (f *FileOutboxAdapter) Pending(ctx context.Context) ([]domain.OutboxMessage, error) {
+	outbox, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	return outbox.toOutboxMessages(outbox.Pending), nil
+}
+
+// Enqueue stores a message, replacing a pending message with the same key
+func (f *FileOutboxAdapter) Enqueue(ctx context.Context, message domain.OutboxMessage) error {
+	outbox, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	data := newOutboxMessageData(message)
+	if content := newNotificationContent(message); content != nil {
+		data.Notification = content.id()
+		if data.Notification == "" {
+			data.Notification = message.Key
+		}
+		if outbox.Notifications == nil {
+			outbox.Notifications = make(map[string]*notificationContent)
+		}
+		outbox.Notifications[data.Notification] = content
+	}
+	if i := outbox.indexOf(message.Key); i >= 0 {
+		outbox.Pending[i] = data
+	} else {
+		outbox.Pending = append(outbox.Pending, data)
+	}
+	return f.save(outbox)
+}
+
+// Remove deletes a delivered or superseded message
+func (f *FileOutboxAdapter) Remove(ctx context.Context, key string) error {
+	outbox, err := f.load()
+	if err != nil {
+		return err
+	}
+	i := outbox.indexOf(key)
+	if i < 0 {
+		return nil
+	}
+
+	outbox.Pending = append(outbox.Pending[:i], outbox.Pending[i+1:]...)
+	return f.save(outbox)
+}
+
+// DeadLetter moves a message that used up its attempts to the dead-letter list
+func (f *FileOutboxAdapter) DeadLetter(ctx context.Context, message domain.OutboxMessage) error {
+	outbox, err := f.load()
+	if err != nil {
+		return err
+	}
+	if i := outbox.indexOf(message.Key); i >= 0 {
+		outbox.Pending = append(outbox.Pending[:i], outbox.Pending[i+1:]...)
+	}
+
+	outbox.DeadLetters = append(outbox.DeadLetters, newOutboxMessageData(message))
+	if len(outbox.DeadLetters) > maxDeadLetters {
+		outbox.DeadLetters = outbox.DeadLetters[len(outbox.DeadLetters)-maxDeadLetters:]
+	}
+	return f.save(outbox)
+}
+
+// DeadLetters returns the messages that were given up on, oldest first
+func (f *FileOutboxAdapter) DeadLetters(ctx context.Context) ([]domain.OutboxMessage, error) {
+	outbox, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	return outbox.toOutboxMessages(outbox.DeadLetters), nil
+}
+
+// indexOf returns the position of the pending message with the key, or -1
+func (o *outboxData) indexOf(key string) int {
+	for i, message := range o.Pending {
+		if message.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// load reads the outbox file, returning an empty outbox when it does not exist yet
+func (f *FileOutboxAdapter) load() (*outboxData, error) {
+	outbox := &outboxData{}
+
+	data, err := os.ReadFile(f.outboxFile)
+	if os.IsNotExist(err) {
+		return outbox, nil
+	}
+	if err != nil {
+		f.logger.Error("Failed to read outbox file", "error", err.Error())
+		return nil, fmt.Errorf("failed to read outbox file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, outbox); err != nil {
+		f.logger.Error("Failed to parse outbox file", "error", err.Error())
+		return nil, fmt.Errorf("failed to parse outbox file: %w", err)
+	}
+	if outbox.Version > outboxVersion {
+		return nil, fmt.Errorf("outbox file version %d is newer than this version supports (%d)", outbox.Version, outboxVersion)
+	}
+
+	f.migrateLegacyOutbox(outbox)
+	return outbox, nil
+}
+
+// migrateLegacyOutbox moves the content version 1 stored in every message to
+// the shared notifications, so upgrading keeps the queued retries
+func (f *FileOutboxAdapter) migrateLegacyOutbox(outbox *outboxData) {
+	if outbox.Version >= outboxVersion {
+		return
+	}
+	if len(outbox.Pending) > 0 {
+		f.logger.Info("Migrating notification outbox", "from_version", max(outbox.Version, 1), "pending", len(outbox.Pending))
+	}
+	for i := range outbox.Pending {
+		message := &outbox.Pending[i]
+		content := newNotificationContent(domain.OutboxMessage{Analysis: message.LegacyAnalysis, Recovery: message.LegacyRecovery})
+		if content != nil {
+			// Version 1 did not share content, so each message keeps its own
+			message.Notification = message.Key
+			if outbox.Notifications == nil {
+				outbox.Notifications = make(map[string]*notificationContent)
+			}
+			outbox.Notifications[message.Notification] = content
+		}
+		message.LegacyAnalysis = nil
+		message.LegacyRecovery = nil
+	}
+	for i := range outbox.DeadLetters {
+		outbox.DeadLetters[i].LegacyAnalysis = nil
+		outbox.DeadLetters[i].LegacyRecovery = nil
+	}
+	outbox.Version = outboxVersion
+}
+
+// save writes the outbox to a temporary file and renames it over the old one,
+// so a crash mid-write cannot lose the queued notifications
+func (f *FileOutboxAdapter) save(outbox *outboxData) error {
+	outbox.Version = outboxVersion
+	outbox.pruneNotifications()
+
+	jsonData, err := json.MarshalIndent(outbox, "", "  ")
+	if err != nil {
+		f.logger.Error("Failed to marshal outbox data", "error", err.Error())
+		return fmt.Errorf("failed to marshal outbox data: %w", err)
+	}
+
+	tmpFile := f.outboxFile + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		f.logger.Error("Failed to write outbox file", "error", err.Error())
+		return fmt.Errorf("failed to write outbox file: %w", err)
+	}
+	if err := os.Rename(tmpFile, f.outboxFile); err != nil {
+		f.logger.Error("Failed to replace outbox file", "error", err.Error())
+		return fmt.Errorf("failed to replace outbox file: %w", err)
+	}
+
+	f.logger.Debug("Saved notification outbox", "pending", len(outbox.Pending), "dead_letters", len(outbox.DeadLetters))
+	return nil
+}
+
+// pruneNotifications drops content no pending message refers to any more
+func (o *outboxData) pruneNotifications() {
+	referenced := make(map[string]bool, len(o.Pending))
+	for _, message := range o.Pending {
+		referenced[message.Notification] = true
+	}
+	for id := range o.Notifications {
+		if !referenced[id] {
+			delete(o.Notifications, id)
+		}
+	}
+}
+
+// newOutboxMessageData converts a message without its content, which Enqueue
+// stores separately and dead letters do not keep
+func newOutboxMessageData(m domain.OutboxMessage) outboxMessageData {
+	return outboxMessageData{
+		Key:         m.Key,
+		Channel:     m.Channel,
+		Kind:        string(m.Kind),
+		EpisodeID:   m.EpisodeID,
+		Attempts:    m.Attempts,
+		CreatedAt:   m.CreatedAt,
+		NextAttempt: m.NextAttempt,
+		LastError:   m.LastError,
+	}
+}
+
+// toOutboxMessages converts stored messages, joining them with their content
+func (o *outboxData) toOutboxMessages(data []outboxMessageData) []domain.OutboxMessage {
+	messages := make([]domain.OutboxMessage, 0, len(data))
+	for _, d := range data {
+		message := domain.OutboxMessage{
+			Key:         d.Key,
+			Channel:     d.Channel,
+			Kind:        domain.NotificationKind(d.Kind),
+			EpisodeID:   d.EpisodeID,
+			Attempts:    d.Attempts,
+			CreatedAt:   d.CreatedAt,
+			NextAttempt: d.NextAttempt,
+			LastError:   d.LastError,
+		}
+		if content := o.Notifications[d.Notification]; d.Notification != "" && content != nil {
+			if content.Alert != nil {
+				message.Analysis = content.Alert.toDomain()
+			}
+			if content.Recovery != nil {
+				message.Recovery = content.Recovery.toDomain()
+			}
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
@@ -0,0 +1,368 @@
+package adapters
+
+import (
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// notificationContent is the persisted form of what a queued notification is
+// built from: the analysis of an alert or update, or the summary of a recovery.
+// It holds what the notifiers render, not the whole analysis.
+type notificationContent struct {
+	Alert    *alertContent    `json:"alert,omitempty"`
+	Recovery *recoveryContent `json:"recovery,omitempty"`
+}
+
+// alertContent is the persisted form of an alert analysis. Rule evidence and
+// low hours are stored as the timestamps of their hours in Production.
+type alertContent struct {
+	NotificationID     string         `json:"notification_id,omitempty"`
+	NotifiedAt         time.Time      `json:"notified_at,omitempty"`
+	Severity           string         `json:"severity,omitempty"`
+	Criteria           criteriaData   `json:"criteria"`
+	FiredRules         []ruleContent  `json:"fired_rules,omitempty"`
+	LowHours           []time.Time    `json:"low_hours,omitempty"`
+	ConsecutiveHours   int            `json:"consecutive_hours,omitempty"`
+	FirstLowHour       time.Time      `json:"first_low_hour,omitempty"`
+	LastLowHour        time.Time      `json:"last_low_hour,omitempty"`
+	RecommendedAction  string         `json:"recommended_action,omitempty"`
+	TotalDaylightHours int            `json:"total_daylight_hours,omitempty"`
+	RecoveryHour       *time.Time     `json:"recovery_hour,omitempty"` // Set when recovery is forecast
+	HoursUntilRecovery int            `json:"hours_until_recovery,omitempty"`
+	DailyEnergy        []dayContent   `json:"daily_energy,omitempty"`
+	LowEnergyDays      []dayContent   `json:"low_energy_days,omitempty"`
+	AlertQuantile      string         `json:"alert_quantile,omitempty"`
+	EnsembleMembers    int            `json:"ensemble_members,omitempty"`
+	Update             *updateContent `json:"update,omitempty"`
+	Production         []hourContent  `json:"production,omitempty"`
+}
+
+// criteriaData tells which kinds of rule fired
+type criteriaData struct {
+	LowProductionDuration bool `json:"low_production_duration,omitempty"`
+	LowDailyEnergy        bool `json:"low_daily_energy,omitempty"`
+	Any                   bool `json:"any,omitempty"`
+}
+
+// ruleContent is one fired rule
+type ruleContent struct {
+	Name          string       `json:"name"`
+	Type          string       `json:"type"`
+	Severity      string       `json:"severity,omitempty"`
+	Threshold     float64      `json:"threshold,omitempty"`
+	Message       string       `json:"message,omitempty"`
+	EvidenceHours []time.Time  `json:"evidence_hours,omitempty"`
+	Days          []dayContent `json:"days,omitempty"`
+	RecoveryHour  *time.Time   `json:"recovery_hour,omitempty"` // Set when recovery is forecast
+}
+
+// dayContent is one calendar day's forecast energy
+type dayContent struct {
+	Date      time.Time `json:"date"`
+	EnergyKWh float64   `json:"energy_kwh"`
+	Hours     int       `json:"hours"`
+	Complete  bool      `json:"complete,omitempty"`
+}
+
+// updateContent is what changed since the previous notification
+type updateContent struct {
+	Previous fingerprintData `json:"previous"`
+	Changes  []string        `json:"changes,omitempty"`
+}
+
+// hourContent is one forecast hour
+type hourContent struct {
+	Hour                     time.Time          `json:"hour"`
+	OutputKW                 float64            `json:"output_kw"`
+	OutputPercent            float64            `json:"output_percent,omitempty"`
+	CloudCover               int                `json:"cloud_cover,omitempty"`
+	TemperatureC             float64            `json:"temperature_c,omitempty"`
+	GHI                      float64            `json:"ghi,omitempty"`
+	PrecipitationProbability int                `json:"precipitation_probability,omitempty"`
+	POA                      float64            `json:"poa,omitempty"`
+	SunElevationDeg          float64            `json:"sun_elevation_deg,omitempty"`
+	CellTemperatureC         float64            `json:"cell_temperature_c,omitempty"`
+	WindSpeed                float64            `json:"wind_speed,omitempty"`
+	DCOutputKW               float64            `json:"dc_output_kw,omitempty"`
+	Clipped                  bool               `json:"clipped,omitempty"`
+	ClippedKW                float64            `json:"clipped_kw,omitempty"`
+	Arrays                   []arrayHourContent `json:"arrays,omitempty"`
+	Quantiles                *quantilesContent  `json:"quantiles,omitempty"`
+}
+
+// arrayHourContent is one array's share of an hour's production
+type arrayHourContent struct {
+	Name             string  `json:"name"`
+	OutputKW         float64 `json:"output_kw"`
+	POA              float64 `json:"poa,omitempty"`
+	CellTemperatureC float64 `json:"cell_temperature_c,omitempty"`
+}
+
+// quantilesContent is an hour's ensemble production band
+type quantilesContent struct {
+	P10     float64 `json:"p10"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	Members int     `json:"members"`
+}
+
+// recoveryContent is the persisted form of a recovery summary
+type recoveryContent struct {
+	NotificationID   string        `json:"notification_id,omitempty"`
+	Episode          *episodeData  `json:"episode"`
+	Analysis         *alertContent `json:"analysis,omitempty"` // Current forecast, which no longer triggers any rule
+	ResolvedAt       time.Time     `json:"resolved_at"`
+	RestOfTodayKWh   float64       `json:"rest_of_today_kwh"`
+	TomorrowKWh      float64       `json:"tomorrow_kwh"`
+	TomorrowComplete bool          `json:"tomorrow_complete,omitempty"`
+}
+
+// newNotificationContent returns the content of a message, or nil when it carries none
+func newNotificationContent(m domain.OutboxMessage) *notificationContent {
+	switch {
+	case m.Recovery != nil:
+		return &notificationContent{Recovery: newRecoveryContent(m.Recovery)}
+	case m.Analysis != nil:
+		return &notificationContent{Alert: newAlertContent(m.Analysis)}
+	default:
+		return nil
+	}
+}
+
+// id returns the key the content is stored under: its notification ID, which
+// every channel's copy of the notification shares
+func (c *notificationContent) id() string {
+	if c.Recovery != nil {
+		return c.Recovery.NotificationID
+	}
+	return c.Alert.NotificationID
+}
+
+func newAlertContent(a *domain.AlertAnalysis) *alertContent {
+	content := &alertContent{
+		NotificationID: a.NotificationID,
+		NotifiedAt:     a.NotifiedAt,
+		Severity:       string(a.Severity),
+		Criteria: criteriaData{
+			LowProductionDuration: a.CriteriaTriggered.LowProductionDurationTriggered,
+			LowDailyEnergy:        a.CriteriaTriggered.LowDailyEnergyTriggered,
+			Any:                   a.CriteriaTriggered.AnyTriggered,
+		},
+		LowHours:           webhookHours(a.LowProductionHours),
+		ConsecutiveHours:   a.ConsecutiveHourCount,
+		FirstLowHour:       a.FirstLowProductionHour,
+		LastLowHour:        a.LastLowProductionHour,
+		RecommendedAction:  a.RecommendedAction,
+		TotalDaylightHours: a.TotalDaylightHours,
+		HoursUntilRecovery: a.HoursUntilRecovery,
+		DailyEnergy:        newDayContents(a.DailyEnergy),
+		LowEnergyDays:      newDayContents(a.LowEnergyDays),
+		AlertQuantile:      string(a.AlertQuantile),
+		EnsembleMembers:    a.EnsembleMembers,
+	}
+	if a.HasRecovery {
+		content.RecoveryHour = optionalTime(a.RecoveryHour)
+	}
+	for _, result := range a.FiredRules {
+		rule := ruleContent{
+			Name:          result.Name,
+			Type:          result.Type,
+			Severity:      string(result.Severity),
+			Threshold:     result.Threshold,
+			Message:       result.Message,
+			EvidenceHours: webhookHours(result.Evidence),
+			Days:          newDayContents(result.Days),
+		}
+		if result.HasRecovery {
+			rule.RecoveryHour = optionalTime(result.RecoveryHour)
+		}
+		content.FiredRules = append(content.FiredRules, rule)
+	}
+	if a.Update != nil {
+		content.Update = &updateContent{Previous: newFingerprintData(a.Update.Previous), Changes: a.Update.Changes}
+	}
+	for _, prod := range a.AllProductionHours {
+		content.Production = append(content.Production, newHourContent(prod))
+	}
+	return content
+}
+
+func (c *alertContent) toDomain() *domain.AlertAnalysis {
+	analysis := &domain.AlertAnalysis{
+		NotificationID: c.NotificationID,
+		NotifiedAt:     c.NotifiedAt,
+		Severity:       domain.Severity(c.Severity),
+		CriteriaTriggered: domain.AlertCriteria{
+			LowProductionDurationTriggered: c.Criteria.LowProductionDuration,
+			LowDailyEnergyTriggered:        c.Criteria.LowDailyEnergy,
+			AnyTriggered:                   c.Criteria.Any,
+		},
+		ConsecutiveHourCount:   c.ConsecutiveHours,
+		FirstLowProductionHour: c.FirstLowHour,
+		LastLowProductionHour:  c.LastLowHour,
+		RecommendedAction:      c.RecommendedAction,
+		TotalDaylightHours:     c.TotalDaylightHours,
+		HoursUntilRecovery:     c.HoursUntilRecovery,
+		DailyEnergy:            dayContentsToDomain(c.DailyEnergy),
+		LowEnergyDays:          dayContentsToDomain(c.LowEnergyDays),
+		AlertQuantile:          domain.AlertQuantile(c.AlertQuantile),
+		EnsembleMembers:        c.EnsembleMembers,
+	}
+	if c.RecoveryHour != nil {
+		analysis.HasRecovery = true
+		analysis.RecoveryHour = *c.RecoveryHour
+	}
+
+	byHour := make(map[int64]domain.SolarProduction, len(c.Production))
+	for _, hour := range c.Production {
+		prod := hour.toDomain()
+		analysis.AllProductionHours = append(analysis.AllProductionHours, prod)
+		byHour[prod.Hour.Unix()] = prod
+	}
+	lookup := func(hours []time.Time) []domain.SolarProduction {
+		var production []domain.SolarProduction
+		for _, hour := range hours {
+			prod, ok := byHour[hour.Unix()]
+			if !ok {
+				prod = domain.SolarProduction{Hour: hour}
+			}
+			production = append(production, prod)
+		}
+		return production
+	}
+
+	analysis.LowProductionHours = lookup(c.LowHours)
+	for _, rule := range c.FiredRules {
+		result := domain.RuleResult{
+			Name:      rule.Name,
+			Type:      rule.Type,
+			Fired:     true,
+			Severity:  domain.Severity(rule.Severity),
+			Threshold: rule.Threshold,
+			Message:   rule.Message,
+			Evidence:  lookup(rule.EvidenceHours),
+			Days:      dayContentsToDomain(rule.Days),
+		}
+		if rule.RecoveryHour != nil {
+			result.HasRecovery = true
+			result.RecoveryHour = *rule.RecoveryHour
+		}
+		analysis.FiredRules = append(analysis.FiredRules, result)
+	}
+	if c.Update != nil {
+		analysis.Update = &domain.AlertUpdate{Previous: c.Update.Previous.toDomain(), Changes: c.Update.Changes}
+	}
+	return analysis
+}
+
+func newRecoveryContent(s *domain.RecoverySummary) *recoveryContent {
+	content := &recoveryContent{
+		NotificationID:   s.NotificationID,
+		Episode:          newEpisodeData(s.Episode),
+		ResolvedAt:       s.ResolvedAt,
+		RestOfTodayKWh:   s.RestOfTodayKWh,
+		TomorrowKWh:      s.TomorrowKWh,
+		TomorrowComplete: s.TomorrowComplete,
+	}
+	if s.Analysis != nil {
+		content.Analysis = newAlertContent(s.Analysis)
+	}
+	return content
+}
+
+func (c *recoveryContent) toDomain() *domain.RecoverySummary {
+	summary := &domain.RecoverySummary{
+		NotificationID:   c.NotificationID,
+		ResolvedAt:       c.ResolvedAt,
+		RestOfTodayKWh:   c.RestOfTodayKWh,
+		TomorrowKWh:      c.TomorrowKWh,
+		TomorrowComplete: c.TomorrowComplete,
+		Analysis:         &domain.AlertAnalysis{},
+	}
+	if c.Episode != nil {
+		summary.Episode = c.Episode.toDomain()
+	}
+	if c.Analysis != nil {
+		summary.Analysis = c.Analysis.toDomain()
+	}
+	return summary
+}
+
+func newHourContent(p domain.SolarProduction) hourContent {
+	hour := hourContent{
+		Hour:                     p.Hour,
+		OutputKW:                 p.EstimatedOutputKW,
+		OutputPercent:            p.OutputPercentage,
+		CloudCover:               p.CloudCover,
+		TemperatureC:             p.Temperature,
+		GHI:                      p.GHI,
+		PrecipitationProbability: p.PrecipitationProbability,
+		POA:                      p.POA,
+		SunElevationDeg:          p.SunElevationDeg,
+		CellTemperatureC:         p.CellTemperature,
+		WindSpeed:                p.WindSpeed,
+		DCOutputKW:               p.DCOutputKW,
+		Clipped:                  p.Clipped,
+		ClippedKW:                p.ClippedKW,
+	}
+	for _, array := range p.Arrays {
+		hour.Arrays = append(hour.Arrays, arrayHourContent{
+			Name:             array.Name,
+			OutputKW:         array.OutputKW,
+			POA:              array.POA,
+			CellTemperatureC: array.CellTemperature,
+		})
+	}
+	if q := p.Quantiles; q != nil {
+		hour.Quantiles = &quantilesContent{P10: q.P10, P50: q.P50, P90: q.P90, Members: q.Members}
+	}
+	return hour
+}
+
+func (h hourContent) toDomain() domain.SolarProduction {
+	prod := domain.SolarProduction{
+		Hour:                     h.Hour,
+		EstimatedOutputKW:        h.OutputKW,
+		OutputPercentage:         h.OutputPercent,
+		CloudCover:               h.CloudCover,
+		Temperature:              h.TemperatureC,
+		GHI:                      h.GHI,
+		PrecipitationProbability: h.PrecipitationProbability,
+		POA:                      h.POA,
+		SunElevationDeg:          h.SunElevationDeg,
+		CellTemperature:          h.CellTemperatureC,
+		WindSpeed:                h.WindSpeed,
+		DCOutputKW:               h.DCOutputKW,
+		Clipped:                  h.Clipped,
+		ClippedKW:                h.ClippedKW,
+	}
+	for _, array := range h.Arrays {
+		prod.Arrays = append(prod.Arrays, domain.ArrayProduction{
+			Name:            array.Name,
+			OutputKW:        array.OutputKW,
+			POA:             array.POA,
+			CellTemperature: array.CellTemperatureC,
+		})
+	}
+	if q := h.Quantiles; q != nil {
+		prod.Quantiles = &domain.ProductionQuantiles{P10: q.P10, P50: q.P50, P90: q.P90, Members: q.Members}
+	}
+	return prod
+}
+
+func newDayContents(days []domain.DailyEnergy) []dayContent {
+	var contents []dayContent
+	for _, day := range days {
+		contents = append(contents, dayContent{Date: day.Date, EnergyKWh: day.EnergyKWh, Hours: day.Hours, Complete: day.Complete})
+	}
+	return contents
+}
+
+func dayContentsToDomain(contents []dayContent) []domain.DailyEnergy {
+	var days []domain.DailyEnergy
+	for _, day := range contents {
+		days = append(days, domain.DailyEnergy{Date: day.Date, EnergyKWh: day.EnergyKWh, Hours: day.Hours, Complete: day.Complete})
+	}
+	return days
+}
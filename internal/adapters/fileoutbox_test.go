@@ -0,0 +1,272 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+func TestFileOutboxQueueAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	outbox := NewFileOutboxAdapter(path, nopLogger{})
+
+	if pending, err := outbox.Pending(ctx); err != nil || len(pending) != 0 {
+		t.Fatalf("Pending on empty outbox = %v, %v; want none", pending, err)
+	}
+
+	created := time.Date(2025, 6, 21, 9, 0, 0, 0, time.UTC)
+	hour := domain.SolarProduction{Hour: created, EstimatedOutputKW: 1.2, Quantiles: &domain.ProductionQuantiles{P10: 0.8, P90: 1.6}}
+	alert := domain.OutboxMessage{
+		Key:         domain.OutboxKey("ep-1", domain.NotificationAlert, domain.ChannelPushover),
+		Channel:     domain.ChannelPushover,
+		Kind:        domain.NotificationAlert,
+		EpisodeID:   "ep-1",
+		Attempts:    1,
+		CreatedAt:   created,
+		NextAttempt: created.Add(15 * time.Minute),
+		LastError:   "503 Service Unavailable",
+		Analysis: &domain.AlertAnalysis{
+			Severity: domain.SeverityWarning,
+			FiredRules: []domain.RuleResult{{
+				Name: "low_production_duration", Fired: true, Message: "6 hours below 2.0 kW", Evidence: []domain.SolarProduction{hour},
+			}},
+			AllProductionHours: []domain.SolarProduction{hour},
+		},
+	}
+	update := alert
+	update.Key = domain.OutboxKey("ep-1", domain.NotificationUpdate, domain.ChannelPushover)
+	update.Kind = domain.NotificationUpdate
+	for _, message := range []domain.OutboxMessage{alert, update} {
+		if err := outbox.Enqueue(ctx, message); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	// A retry replaces the message with the same key rather than adding one
+	alert.Attempts = 2
+	if err := outbox.Enqueue(ctx, alert); err != nil {
+		t.Fatalf("Enqueue retry: %v", err)
+	}
+
+	// A new adapter reads what the previous run left
+	outbox = NewFileOutboxAdapter(path, nopLogger{})
+	pending, err := outbox.Pending(ctx)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("Pending = %+v, %v; want the alert and the update", pending, err)
+	}
+	got := pending[0]
+	if got.Key != alert.Key || got.Attempts != 2 || !got.NextAttempt.Equal(alert.NextAttempt) || got.LastError != alert.LastError {
+		t.Errorf("pending alert = %+v", got)
+	}
+	if got.Analysis == nil || got.Analysis.Severity != domain.SeverityWarning || len(got.Analysis.FiredRules) != 1 ||
+		got.Analysis.AllProductionHours[0].Quantiles.P90 != 1.6 {
+		t.Errorf("pending alert analysis = %+v, want the stored analysis", got.Analysis)
+	}
+	if evidence := got.Analysis.FiredRules[0].Evidence; len(evidence) != 1 || evidence[0].EstimatedOutputKW != 1.2 {
+		t.Errorf("rule evidence = %+v, want the stored hour", evidence)
+	}
+
+	if err := outbox.DeadLetter(ctx, pending[1]); err != nil {
+		t.Fatalf("DeadLetter: %v", err)
+	}
+	if err := outbox.Remove(ctx, alert.Key); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if pending, _ := outbox.Pending(ctx); len(pending) != 0 {
+		t.Errorf("Pending after dead-letter and remove = %+v, want none", pending)
+	}
+	deadLetters, err := outbox.DeadLetters(ctx)
+	if err != nil || len(deadLetters) != 1 || deadLetters[0].Kind != domain.NotificationUpdate {
+		t.Errorf("DeadLetters = %+v, %v; want the update", deadLetters, err)
+	}
+}
+
+func TestFileOutboxKeepsRecentDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	outbox := NewFileOutboxAdapter(filepath.Join(t.TempDir(), "outbox.json"), nopLogger{})
+
+	for i := 0; i < maxDeadLetters+5; i++ {
+		message := domain.OutboxMessage{Key: "ep-1/update/slack", Attempts: i}
+		if err := outbox.DeadLetter(ctx, message); err != nil {
+			t.Fatalf("DeadLetter: %v", err)
+		}
+	}
+	deadLetters, _ := outbox.DeadLetters(ctx)
+	if len(deadLetters) != maxDeadLetters || deadLetters[0].Attempts != 5 {
+		t.Errorf("kept %d dead letters starting at attempt %d, want the last %d", len(deadLetters), deadLetters[0].Attempts, maxDeadLetters)
+	}
+}
+
+func TestFileOutboxStoresContentOnce(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	outbox := NewFileOutboxAdapter(path, nopLogger{})
+
+	analysis := &domain.AlertAnalysis{Severity: domain.SeverityWarning, NotificationID: "ep-1/alert"}
+	for _, channel := range []string{domain.ChannelPushover, domain.ChannelSlack} {
+		message := domain.OutboxMessage{
+			Key:       domain.OutboxKey("ep-1", domain.NotificationAlert, channel),
+			Channel:   channel,
+			Kind:      domain.NotificationAlert,
+			EpisodeID: "ep-1",
+			Analysis:  analysis,
+		}
+		if err := outbox.Enqueue(ctx, message); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	stored := readOutboxFile(t, path)
+	if stored.Version != outboxVersion || len(stored.Pending) != 2 || len(stored.Notifications) != 1 {
+		t.Fatalf("outbox file = %+v, want both channels sharing one notification", stored)
+	}
+
+	// Dead letters keep no content, and content nothing refers to is dropped
+	pending, _ := outbox.Pending(ctx)
+	for _, message := range pending {
+		if err := outbox.DeadLetter(ctx, message); err != nil {
+			t.Fatalf("DeadLetter: %v", err)
+		}
+	}
+	if stored := readOutboxFile(t, path); len(stored.Notifications) != 0 || stored.DeadLetters[0].Notification != "" {
+		t.Errorf("outbox file = %+v, want no content left", stored)
+	}
+}
+
+func TestFileOutboxMigratesVersion1(t *testing.T) {
+	// Written by version 1, which stored the domain types with their Go field names
+	legacy := `{
+  "pending": [
+    {
+      "key": "ep-1/alert/pushover",
+      "channel": "pushover",
+      "kind": "alert",
+      "episode_id": "ep-1",
+      "attempts": 2,
+      "created_at": "2025-06-21T09:00:00Z",
+      "next_attempt": "2025-06-21T09:30:00Z",
+      "last_error": "503 Service Unavailable",
+      "analysis": {
+        "CriteriaTriggered": {"LowProductionDurationTriggered": true, "LowDailyEnergyTriggered": false, "AnyTriggered": true},
+        "FiredRules": [{
+          "Name": "low_production_duration", "Type": "duration", "Fired": true, "Severity": "warning", "Threshold": 2,
+          "Message": "6 hours below 2.0 kW",
+          "Evidence": [{"Hour": "2025-06-21T10:00:00Z", "EstimatedOutputKW": 1.2, "GHI": 150}],
+          "Days": null, "HasRecovery": true, "RecoveryHour": "2025-06-21T16:00:00Z"
+        }],
+        "Severity": "warning",
+        "AllProductionHours": [
+          {"Hour": "2025-06-21T10:00:00Z", "EstimatedOutputKW": 1.2, "GHI": 150, "CloudCover": 90,
+           "Quantiles": {"P10": 0.8, "P50": 1.2, "P90": 1.6, "Members": 51}}
+        ],
+        "ConsecutiveHourCount": 6,
+        "HasRecovery": true,
+        "RecoveryHour": "2025-06-21T16:00:00Z",
+        "AlertQuantile": "p10",
+        "EnsembleMembers": 51,
+        "NotificationID": "ep-1/alert",
+        "NotifiedAt": "2025-06-21T09:00:00Z"
+      }
+    },
+    {
+      "key": "ep-0/recovery/slack",
+      "channel": "slack",
+      "kind": "recovery",
+      "episode_id": "ep-0",
+      "attempts": 1,
+      "created_at": "2025-06-21T08:00:00Z",
+      "next_attempt": "2025-06-21T08:15:00Z",
+      "recovery": {
+        "Episode": {"ID": "ep-0", "StartedAt": "2025-06-20T07:00:00Z", "Severity": "critical", "FiredRules": ["low_production_duration"]},
+        "Analysis": {"AllProductionHours": [{"Hour": "2025-06-21T10:00:00Z", "EstimatedOutputKW": 4.1}]},
+        "ResolvedAt": "2025-06-21T08:00:00Z",
+        "RestOfTodayKWh": 12.5,
+        "TomorrowKWh": 31.2,
+        "TomorrowComplete": true,
+        "NotificationID": "ep-0/recovery"
+      }
+    }
+  ],
+  "dead_letters": [
+    {"key": "ep-0/update/teams", "channel": "teams", "kind": "update", "episode_id": "ep-0", "attempts": 6,
+     "created_at": "2025-06-20T12:00:00Z", "next_attempt": "2025-06-20T18:00:00Z", "analysis": {"Severity": "warning"}}
+  ]
+}`
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	outbox := NewFileOutboxAdapter(path, nopLogger{})
+	pending, err := outbox.Pending(ctx)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("Pending = %+v, %v; want both legacy messages", pending, err)
+	}
+
+	alert := pending[0]
+	if alert.Attempts != 2 || alert.Analysis == nil {
+		t.Fatalf("legacy alert = %+v", alert)
+	}
+	analysis := alert.Analysis
+	if analysis.Severity != domain.SeverityWarning || analysis.NotificationID != "ep-1/alert" || !analysis.HasRecovery ||
+		analysis.AlertQuantile != domain.AlertOnP10 || !analysis.CriteriaTriggered.LowProductionDurationTriggered {
+		t.Errorf("legacy analysis = %+v", analysis)
+	}
+	if len(analysis.FiredRules) != 1 || analysis.FiredRules[0].Threshold != 2 || len(analysis.FiredRules[0].Evidence) != 1 ||
+		analysis.FiredRules[0].Evidence[0].EstimatedOutputKW != 1.2 {
+		t.Errorf("legacy fired rules = %+v", analysis.FiredRules)
+	}
+	if hours := analysis.AllProductionHours; len(hours) != 1 || hours[0].CloudCover != 90 || hours[0].Quantiles == nil || hours[0].Quantiles.P90 != 1.6 {
+		t.Errorf("legacy production = %+v", hours)
+	}
+
+	recovery := pending[1].Recovery
+	if recovery == nil || recovery.Episode.ID != "ep-0" || recovery.Episode.Severity != domain.SeverityCritical ||
+		recovery.TomorrowKWh != 31.2 || recovery.NotificationID != "ep-0/recovery" || len(recovery.Analysis.AllProductionHours) != 1 {
+		t.Errorf("legacy recovery = %+v", recovery)
+	}
+
+	// The next write stores the current version
+	if err := outbox.Remove(ctx, alert.Key); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	stored := readOutboxFile(t, path)
+	if stored.Version != outboxVersion || len(stored.Pending) != 1 || stored.Pending[0].LegacyRecovery != nil ||
+		stored.Notifications[stored.Pending[0].Notification] == nil || stored.DeadLetters[0].LegacyAnalysis != nil {
+		t.Errorf("outbox file after migration = %+v", stored)
+	}
+	if pending, _ := outbox.Pending(ctx); len(pending) != 1 || pending[0].Recovery == nil || pending[0].Recovery.RestOfTodayKWh != 12.5 {
+		t.Errorf("Pending after migration = %+v, want the recovery", pending)
+	}
+}
+
+func TestFileOutboxRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileOutboxAdapter(path, nopLogger{}).Pending(context.Background()); err == nil {
+		t.Error("expected an error for an outbox file from a newer version")
+	}
+}
+
+// readOutboxFile decodes the outbox file as stored
+func readOutboxFile(t *testing.T, path string) outboxData {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outbox outboxData
+	if err := json.Unmarshal(data, &outbox); err != nil {
+		t.Fatal(err)
+	}
+	return outbox
+}
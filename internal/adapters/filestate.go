@@ -11,6 +11,9 @@ import (
 	"github.com/b0d/solar-forecast/internal/domain"
 )
 
+// maxEpisodeHistory is how many resolved episodes the state file keeps
+const maxEpisodeHistory = 20
+
 // FileStateAdapter implements AlertStateRepository using file-based storage
 type FileStateAdapter struct {
 	stateFile string
@@ -19,10 +22,43 @@ type FileStateAdapter struct {
 
 // stateData represents the persistent state structure
 type stateData struct {
-	LastAlertDate     string `json:"last_alert_date"` // ISO 8601 format
-	AlertSent         bool   `json:"alert_sent"`
-	AlertRecovered    bool   `json:"alert_recovered"`
-	RecoveryEmailSent bool   `json:"recovery_email_sent"`
+	ActiveEpisode *episodeData   `json:"active_episode,omitempty"`
+	Episodes      []*episodeData `json:"episodes,omitempty"` // Resolved episodes, oldest first
+
+	// Per-day fields written by earlier versions, read once for migration
+	LegacyLastAlertDate     string `json:"last_alert_date,omitempty"`
+	LegacyAlertSent         bool   `json:"alert_sent,omitempty"`
+	LegacyRecoveryEmailSent bool   `json:"recovery_email_sent,omitempty"`
+}
+
+// episodeData is the persisted form of an alert episode
+type episodeData struct {
+	ID            string             `json:"id"`
+	StartedAt     time.Time          `json:"started_at"`
+	ExpectedEnd   time.Time          `json:"expected_end,omitempty"`
+	FiredRules    []string           `json:"fired_rules,omitempty"`
+	Severity      string             `json:"severity,omitempty"`
+	Initial       fingerprintData    `json:"initial"`
+	Latest        fingerprintData    `json:"latest"`
+	Notifications []notificationData `json:"notifications,omitempty"`
+	ResolvedAt    *time.Time         `json:"resolved_at,omitempty"`
+}
+
+// fingerprintData is the persisted form of an alert fingerprint
+type fingerprintData struct {
+	FirstLowHour time.Time `json:"first_low_hour"`
+	LastLowHour  time.Time `json:"last_low_hour"`
+	LowHourCount int       `json:"low_hour_count"`
+	MinOutputKW  float64   `json:"min_output_kw"`
+	Severity     string    `json:"severity"`
+	Rules        []string  `json:"rules"`
+}
+
+// notificationData is the persisted form of an episode notification
+type notificationData struct {
+	Kind     string    `json:"kind"`
+	Channels []string  `json:"channels"`
+	SentAt   time.Time `json:"sent_at"`
 }
 
 // NewFileStateAdapter creates a new file-based state adapter
@@ -39,12 +75,79 @@ func NewFileStateAdapter(stateFilePath string, logger domain.Logger) *FileStateA
 	}
 }
 
-// GetLastAlertDate retrieves the last alert date from file
-func (f *FileStateAdapter) GetLastAlertDate(ctx context.Context) (domain.AlertState, error) {
-	state := domain.AlertState{
-		AlertSent: false,
+// ActiveEpisode returns the open alert episode, or nil when there is none
+func (f *FileStateAdapter) ActiveEpisode(ctx context.Context) (*domain.AlertEpisode, error) {
+	state, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	if state.ActiveEpisode == nil {
+		f.logger.Debug("No active alert episode")
+		return nil, nil
 	}
 
+	episode := state.ActiveEpisode.toDomain()
+	f.logger.Debug("Retrieved active alert episode",
+		"episode", episode.ID,
+		"started_at", episode.StartedAt.Format(time.RFC3339),
+		"notifications", len(episode.Notifications))
+	return &episode, nil
+}
+
+// OpenEpisode stores a new episode as the active one
+func (f *FileStateAdapter) OpenEpisode(ctx context.Context, episode domain.AlertEpisode) error {
+	state, err := f.load()
+	if err != nil {
+		return err
+	}
+	if state.ActiveEpisode != nil {
+		return fmt.Errorf("cannot open episode %s: episode %s is still active", episode.ID, state.ActiveEpisode.ID)
+	}
+
+	state.ActiveEpisode = newEpisodeData(episode)
+	return f.save(state)
+}
+
+// UpdateEpisode replaces the active episode with the same ID
+func (f *FileStateAdapter) UpdateEpisode(ctx context.Context, episode domain.AlertEpisode) error {
+	state, err := f.load()
+	if err != nil {
+		return err
+	}
+	if state.ActiveEpisode == nil || state.ActiveEpisode.ID != episode.ID {
+		return fmt.Errorf("cannot update episode %s: it is not the active episode", episode.ID)
+	}
+
+	state.ActiveEpisode = newEpisodeData(episode)
+	return f.save(state)
+}
+
+// CloseEpisode resolves the active episode and moves it to the history
+func (f *FileStateAdapter) CloseEpisode(ctx context.Context, id string, resolvedAt time.Time) error {
+	state, err := f.load()
+	if err != nil {
+		return err
+	}
+	if state.ActiveEpisode == nil || state.ActiveEpisode.ID != id {
+		return fmt.Errorf("cannot close episode %s: it is not the active episode", id)
+	}
+
+	closed := state.ActiveEpisode
+	closed.ResolvedAt = &resolvedAt
+	state.ActiveEpisode = nil
+	state.Episodes = append(state.Episodes, closed)
+	if len(state.Episodes) > maxEpisodeHistory {
+		state.Episodes = state.Episodes[len(state.Episodes)-maxEpisodeHistory:]
+	}
+
+	f.logger.Debug("Closed alert episode", "episode", id, "resolved_at", resolvedAt.Format(time.RFC3339))
+	return f.save(state)
+}
+
+// load reads the state file, returning empty state when it does not exist yet
+func (f *FileStateAdapter) load() (*stateData, error) {
+	state := &stateData{}
+
 	// Check if file exists
 	if _, err := os.Stat(f.stateFile); os.IsNotExist(err) {
 		f.logger.Debug("State file does not exist, returning empty state")
@@ -55,173 +158,138 @@ func (f *FileStateAdapter) GetLastAlertDate(ctx context.Context) (domain.AlertSt
 	data, err := os.ReadFile(f.stateFile)
 	if err != nil {
 		f.logger.Error("Failed to read state file", "error", err.Error())
-		return state, fmt.Errorf("failed to read state file: %w", err)
+		return nil, fmt.Errorf("failed to read state file: %w", err)
 	}
 
 	// Parse JSON
-	var stored stateData
-	if err := json.Unmarshal(data, &stored); err != nil {
+	if err := json.Unmarshal(data, state); err != nil {
 		f.logger.Error("Failed to parse state file", "error", err.Error())
-		return state, fmt.Errorf("failed to parse state file: %w", err)
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
 	}
 
-	// Parse date with local timezone
-	if stored.LastAlertDate != "" {
-		lastDate, err := time.ParseInLocation("2006-01-02", stored.LastAlertDate, time.Local)
-		if err != nil {
-			f.logger.Error("Failed to parse last alert date", "error", err.Error(), "date", stored.LastAlertDate)
-			return state, fmt.Errorf("failed to parse last alert date: %w", err)
-		}
-		state.LastAlertDate = lastDate
-	}
-
-	state.AlertSent = stored.AlertSent
-	state.AlertRecovered = stored.AlertRecovered
-	state.RecoveryEmailSent = stored.RecoveryEmailSent
-
-	f.logger.Debug("Retrieved alert state", "last_alert_date", stored.LastAlertDate, "alert_sent", stored.AlertSent, "recovery_email_sent", stored.RecoveryEmailSent)
+	f.migrateLegacyState(state)
 	return state, nil
 }
 
-// SaveAlertDate saves the current alert sent date to file
-func (f *FileStateAdapter) SaveAlertDate(ctx context.Context, state domain.AlertState) error {
-	data := stateData{
-		LastAlertDate:     state.LastAlertDate.Format("2006-01-02"),
-		AlertSent:         state.AlertSent,
-		AlertRecovered:    state.AlertRecovered,
-		RecoveryEmailSent: state.RecoveryEmailSent,
+// migrateLegacyState turns today's alert recorded by the per-day state format
+// into an open episode, so upgrading does not repeat the alert or lose its
+// recovery email. The old format reset every day, so an alert from an earlier
+// day is dropped as it would have been.
+func (f *FileStateAdapter) migrateLegacyState(state *stateData) {
+	if state.LegacyLastAlertDate == "" {
+		return
 	}
+	today := time.Now().Format("2006-01-02")
+	if state.LegacyLastAlertDate != today {
+		f.logger.Info("Dropping legacy alert state from an earlier day", "last_alert_date", state.LegacyLastAlertDate)
+	} else if state.ActiveEpisode == nil && state.LegacyAlertSent && !state.LegacyRecoveryEmailSent {
+		if date, err := time.ParseInLocation("2006-01-02", state.LegacyLastAlertDate, time.Local); err == nil {
+			f.logger.Info("Migrating legacy alert state to an episode", "last_alert_date", state.LegacyLastAlertDate)
+			state.ActiveEpisode = &episodeData{
+				ID:        "ep-" + date.Format("20060102") + "-legacy",
+				StartedAt: date,
+				// Earlier versions sent every alert by email and push
+				Notifications: []notificationData{{
+					Kind:     string(domain.NotificationAlert),
+					Channels: []string{domain.ChannelEmail, domain.ChannelPush},
+					SentAt:   date,
+				}},
+			}
+		}
+	}
+	state.LegacyLastAlertDate = ""
+	state.LegacyAlertSent = false
+	state.LegacyRecoveryEmailSent = false
+}
 
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+// save writes the state to a temporary file and renames it over the old one,
+// so a crash or full disk mid-write cannot lose the open episode
+func (f *FileStateAdapter) save(state *stateData) error {
+	jsonData, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		f.logger.Error("Failed to marshal state data", "error", err.Error())
 		return fmt.Errorf("failed to marshal state data: %w", err)
 	}
 
-	if err := os.WriteFile(f.stateFile, jsonData, 0644); err != nil {
+	tmpFile := f.stateFile + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
 		f.logger.Error("Failed to write state file", "error", err.Error())
 		return fmt.Errorf("failed to write state file: %w", err)
 	}
+	if err := os.Rename(tmpFile, f.stateFile); err != nil {
+		f.logger.Error("Failed to replace state file", "error", err.Error())
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
 
-	f.logger.Debug("Saved alert state", "last_alert_date", data.LastAlertDate, "alert_sent", data.AlertSent)
+	f.logger.Debug("Saved alert state", "active_episode", state.ActiveEpisode != nil, "history", len(state.Episodes))
 	return nil
 }
 
-// ResetIfNewDay checks if it's a new calendar day and resets alert state if needed
-func (f *FileStateAdapter) ResetIfNewDay(ctx context.Context) (bool, error) {
-	state, err := f.GetLastAlertDate(ctx)
-	if err != nil {
-		return false, err
-	}
-
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-
-	// If last alert was on a different date, reset
-	if !state.LastAlertDate.IsZero() {
-		lastAlertDate := time.Date(state.LastAlertDate.Year(), state.LastAlertDate.Month(), state.LastAlertDate.Day(), 0, 0, 0, 0, state.LastAlertDate.Location())
-		if lastAlertDate.Before(today) {
-			f.logger.Info("New day detected, resetting alert state", "today", today.Format("2006-01-02"), "last_alert", lastAlertDate.Format("2006-01-02"))
-			// Clear the alert state for new day
-			resetState := domain.AlertState{
-				LastAlertDate:     now,
-				AlertSent:         false,
-				AlertRecovered:    false,
-				RecoveryEmailSent: false,
-			}
-			if err := f.SaveAlertDate(ctx, resetState); err != nil {
-				f.logger.Error("Failed to reset alert state", "error", err.Error())
-				return false, err
-			}
-			return true, nil
-		}
+func newEpisodeData(e domain.AlertEpisode) *episodeData {
+	data := &episodeData{
+		ID:          e.ID,
+		StartedAt:   e.StartedAt,
+		ExpectedEnd: e.ExpectedEnd,
+		FiredRules:  e.FiredRules,
+		Severity:    string(e.Severity),
+		Initial:     newFingerprintData(e.Initial),
+		Latest:      newFingerprintData(e.Latest),
 	}
-
-	return false, nil
-}
-
-// ShouldSendAlert checks if alert should be sent based on state and time
-func (f *FileStateAdapter) ShouldSendAlert(ctx context.Context) (bool, error) {
-	state, err := f.GetLastAlertDate(ctx)
-	if err != nil {
-		f.logger.Error("Failed to get alert state", "error", err.Error())
-		return false, err
+	for _, n := range e.Notifications {
+		data.Notifications = append(data.Notifications, notificationData{
+			Kind:     string(n.Kind),
+			Channels: n.Channels,
+			SentAt:   n.SentAt,
+		})
 	}
-
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-
-	// If no alert sent yet, or last alert was on a different day, allow sending
-	if !state.AlertSent {
-		return true, nil
+	if !e.ResolvedAt.IsZero() {
+		resolvedAt := e.ResolvedAt
+		data.ResolvedAt = &resolvedAt
 	}
+	return data
+}
 
-	if state.LastAlertDate.IsZero() {
-		return true, nil
+func (d *episodeData) toDomain() domain.AlertEpisode {
+	episode := domain.AlertEpisode{
+		ID:          d.ID,
+		StartedAt:   d.StartedAt,
+		ExpectedEnd: d.ExpectedEnd,
+		FiredRules:  d.FiredRules,
+		Severity:    domain.Severity(d.Severity),
+		Initial:     d.Initial.toDomain(),
+		Latest:      d.Latest.toDomain(),
 	}
-
-	lastAlertDate := time.Date(state.LastAlertDate.Year(), state.LastAlertDate.Month(), state.LastAlertDate.Day(), 0, 0, 0, 0, state.LastAlertDate.Location())
-	if lastAlertDate.Before(today) {
-		f.logger.Info("New day, allowing alert to be sent")
-		return true, nil
+	for _, n := range d.Notifications {
+		episode.Notifications = append(episode.Notifications, domain.EpisodeNotification{
+			Kind:     domain.NotificationKind(n.Kind),
+			Channels: n.Channels,
+			SentAt:   n.SentAt,
+		})
 	}
-
-	f.logger.Debug("Alert already sent today, skipping")
-	return false, nil
-}
-
-// MarkAlertSent marks that alert was sent today
-func (f *FileStateAdapter) MarkAlertSent(ctx context.Context) error {
-	// Get current state to preserve recovery fields
-	state, err := f.GetLastAlertDate(ctx)
-	if err != nil {
-		f.logger.Error("Failed to get current state", "error", err.Error())
-		// If error getting state, just save minimal state
-		state = domain.AlertState{}
+	if d.ResolvedAt != nil {
+		episode.ResolvedAt = *d.ResolvedAt
 	}
-
-	// Update only the alert fields, preserving recovery fields
-	state.LastAlertDate = time.Now()
-	state.AlertSent = true
-
-	return f.SaveAlertDate(ctx, state)
+	return episode
 }
 
-// ShouldSendRecoveryEmail checks if recovery email should be sent
-func (f *FileStateAdapter) ShouldSendRecoveryEmail(ctx context.Context) (bool, error) {
-	state, err := f.GetLastAlertDate(ctx)
-	if err != nil {
-		f.logger.Error("Failed to get alert state", "error", err.Error())
-		return false, err
-	}
-
-	// Only send recovery email if alert was previously sent and recovery email hasn't been sent yet
-	shouldSend := state.AlertSent && !state.RecoveryEmailSent
-
-	f.logger.Debug("Recovery email eligibility check",
-		"alert_sent", state.AlertSent,
-		"recovery_email_sent", state.RecoveryEmailSent,
-		"should_send", shouldSend)
-
-	if !shouldSend {
-		if !state.AlertSent {
-			f.logger.Debug("Recovery email not needed - no alert was triggered")
-		} else if state.RecoveryEmailSent {
-			f.logger.Debug("Recovery email already sent today")
-		}
-		return false, nil
+func newFingerprintData(fp domain.AlertFingerprint) fingerprintData {
+	return fingerprintData{
+		FirstLowHour: fp.FirstLowHour,
+		LastLowHour:  fp.LastLowHour,
+		LowHourCount: fp.LowHourCount,
+		MinOutputKW:  fp.MinOutputKW,
+		Severity:     string(fp.Severity),
+		Rules:        fp.Rules,
 	}
-
-	return true, nil
 }
 
-// MarkRecoveryEmailSent marks that recovery email has been sent
-func (f *FileStateAdapter) MarkRecoveryEmailSent(ctx context.Context) error {
-	state, err := f.GetLastAlertDate(ctx)
-	if err != nil {
-		return err
+func (d fingerprintData) toDomain() domain.AlertFingerprint {
+	return domain.AlertFingerprint{
+		FirstLowHour: d.FirstLowHour,
+		LastLowHour:  d.LastLowHour,
+		LowHourCount: d.LowHourCount,
+		MinOutputKW:  d.MinOutputKW,
+		Severity:     domain.Severity(d.Severity),
+		Rules:        d.Rules,
 	}
-
-	state.RecoveryEmailSent = true
-	return f.SaveAlertDate(ctx, state)
 }
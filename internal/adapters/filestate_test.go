@@ -0,0 +1,127 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+func TestFileStateEpisodeRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewFileStateAdapter(filepath.Join(t.TempDir(), "alert_state.json"), nopLogger{})
+
+	if episode, err := adapter.ActiveEpisode(ctx); err != nil || episode != nil {
+		t.Fatalf("ActiveEpisode on empty state = %v, %v; want nil, nil", episode, err)
+	}
+
+	start := time.Date(2025, 6, 21, 22, 0, 0, 0, time.UTC)
+	episode := domain.AlertEpisode{
+		ID:          "ep-1",
+		StartedAt:   start,
+		ExpectedEnd: start.Add(14 * time.Hour),
+		FiredRules:  []string{"low_production_duration"},
+		Severity:    domain.SeverityWarning,
+		Initial:     domain.AlertFingerprint{LowHourCount: 6, MinOutputKW: 1.2, Rules: []string{"low_production_duration"}},
+	}
+	episode.Latest = episode.Initial
+	episode.RecordNotification(domain.NotificationAlert, []string{domain.ChannelEmail, domain.ChannelPush}, start)
+
+	if err := adapter.OpenEpisode(ctx, episode); err != nil {
+		t.Fatalf("OpenEpisode: %v", err)
+	}
+	if err := adapter.OpenEpisode(ctx, domain.AlertEpisode{ID: "ep-2"}); err == nil {
+		t.Error("opening a second episode while one is active should fail")
+	}
+
+	// The episode survives midnight; recovery is recorded against it
+	episode.RecordNotification(domain.NotificationRecovery, []string{domain.ChannelEmail}, start.Add(15*time.Hour))
+	if err := adapter.UpdateEpisode(ctx, episode); err != nil {
+		t.Fatalf("UpdateEpisode: %v", err)
+	}
+
+	loaded, err := adapter.ActiveEpisode(ctx)
+	if err != nil || loaded == nil {
+		t.Fatalf("ActiveEpisode = %v, %v", loaded, err)
+	}
+	if loaded.ID != "ep-1" || !loaded.StartedAt.Equal(start) || loaded.Latest.LowHourCount != 6 || len(loaded.Notifications) != 2 {
+		t.Errorf("loaded episode = %+v", loaded)
+	}
+	if got := loaded.Notifications[0].Channels; len(got) != 2 || got[1] != domain.ChannelPush {
+		t.Errorf("alert channels = %v, want [email push]", got)
+	}
+
+	if err := adapter.CloseEpisode(ctx, "ep-1", start.Add(15*time.Hour)); err != nil {
+		t.Fatalf("CloseEpisode: %v", err)
+	}
+	if episode, _ := adapter.ActiveEpisode(ctx); episode != nil {
+		t.Errorf("ActiveEpisode after close = %+v, want nil", episode)
+	}
+}
+
+func TestFileStateMigratesLegacyAlert(t *testing.T) {
+	tests := []struct {
+		name        string
+		date        string
+		wantEpisode bool
+	}{
+		{"alert sent today", time.Now().Format("2006-01-02"), true},
+		{"alert from an earlier day", time.Now().AddDate(0, 0, -3).Format("2006-01-02"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "alert_state.json")
+			legacy := `{"last_alert_date": "` + tt.date + `", "alert_sent": true, "alert_recovered": false, "recovery_email_sent": false}`
+			if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			adapter := NewFileStateAdapter(path, nopLogger{})
+			episode, err := adapter.ActiveEpisode(context.Background())
+			if err != nil {
+				t.Fatalf("ActiveEpisode: %v", err)
+			}
+			if !tt.wantEpisode {
+				if episode != nil {
+					t.Errorf("stale legacy alert became episode %+v, want none", episode)
+				}
+				return
+			}
+			if episode == nil || episode.NotificationCount(domain.NotificationAlert, time.Time{}) != 1 {
+				t.Fatalf("legacy alert should become an open episode with its alert, got %+v", episode)
+			}
+			if !episode.Latest.IsZero() {
+				t.Errorf("migrated episode fingerprint = %+v, want none until the next run records one", episode.Latest)
+			}
+		})
+	}
+}
+
+func TestFileStateSaveLeavesStateIntactOnFailedWrite(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "alert_state.json")
+	adapter := NewFileStateAdapter(path, nopLogger{})
+	start := time.Now().Truncate(time.Hour)
+	if err := adapter.OpenEpisode(ctx, domain.AlertEpisode{ID: "ep-1", StartedAt: start}); err != nil {
+		t.Fatalf("OpenEpisode: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	// A write that cannot complete leaves the previous state in place
+	if err := os.Mkdir(path+".tmp", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.UpdateEpisode(ctx, domain.AlertEpisode{ID: "ep-1", StartedAt: start, Severity: domain.SeverityCritical}); err == nil {
+		t.Fatal("UpdateEpisode succeeded although the state could not be written")
+	}
+	episode, err := adapter.ActiveEpisode(ctx)
+	if err != nil || episode == nil || episode.ID != "ep-1" {
+		t.Errorf("ActiveEpisode = %+v, %v; want the episode from before the failed write", episode, err)
+	}
+}
@@ -0,0 +1,114 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// GotifyAdapter implements PushNotifier using a Gotify server
+type GotifyAdapter struct {
+	pushMessages
+	serverURL string
+	appToken  string
+	clickURL  string // Opened when the notification is tapped
+	client    *http.Client
+}
+
+// NewGotifyAdapter creates a new Gotify adapter
+func NewGotifyAdapter(config *domain.Config, templates *Templates, logger domain.Logger) *GotifyAdapter {
+	return &GotifyAdapter{
+		pushMessages: newPushMessages(config, templates, logger),
+		serverURL:    strings.TrimRight(config.Gotify.ServerURL, "/"),
+		appToken:     config.Gotify.AppToken,
+		clickURL:     config.Gotify.ClickURL,
+		client:       &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// gotifyPriority maps an alert severity to a Gotify priority (0 to 10). The
+// Android app plays a sound from 4 and pops up from 8: info is 4, warning is 7
+// and critical is 10.
+func gotifyPriority(severity domain.Severity) int {
+	switch severity {
+	case domain.SeverityInfo:
+		return 4
+	case domain.SeverityCritical:
+		return 10
+	default:
+		return 7
+	}
+}
+
+// gotifyMessage is the body of POST /message
+type gotifyMessage struct {
+	Title    string         `json:"title"`
+	Message  string         `json:"message"`
+	Priority int            `json:"priority"`
+	Extras   map[string]any `json:"extras"`
+}
+
+// SendNotification sends the message to the Gotify application. Gotify has no
+// attachments, so the chart image is not sent.
+func (g *GotifyAdapter) SendNotification(ctx context.Context, title, message string, imageData []byte, severity domain.Severity) error {
+	if g.serverURL == "" || g.appToken == "" {
+		g.logger.Debug("Gotify not configured, skipping notification")
+		return nil // Not an error, just not configured
+	}
+
+	// Extras tell the clients how to show the message; solarforecast::alert lets
+	// plugins and scripts read the severity without parsing the text
+	extras := map[string]any{
+		"client::display":      map[string]any{"contentType": "text/plain"},
+		"solarforecast::alert": map[string]any{"severity": severity},
+	}
+	if g.clickURL != "" {
+		extras["client::notification"] = map[string]any{"click": map[string]any{"url": g.clickURL}}
+	}
+	priority := gotifyPriority(severity)
+	body, err := json.Marshal(gotifyMessage{Title: title, Message: message, Priority: priority, Extras: extras})
+	if err != nil {
+		return fmt.Errorf("failed to encode gotify message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.serverURL+"/message", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create gotify request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", g.appToken)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		g.logger.Error("Failed to send Gotify notification", "error", err.Error())
+		return fmt.Errorf("failed to send gotify notification: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		// Gotify reports errors as {"error":"Unauthorized","errorCode":401,"errorDescription":"..."}
+		var apiErr struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"errorDescription"`
+		}
+		description := strings.TrimSpace(string(respBody))
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
+			description = strings.TrimSuffix(apiErr.Error+": "+apiErr.ErrorDescription, ": ")
+		}
+		g.logger.Error("Gotify server returned error", "status", resp.StatusCode, "error", description)
+		return fmt.Errorf("gotify error: %s (status %d)", description, resp.StatusCode)
+	}
+
+	g.logger.Info("Gotify notification sent successfully",
+		"title", title,
+		"priority", priority)
+	return nil
+}
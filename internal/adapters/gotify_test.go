@@ -0,0 +1,75 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+func TestGotifySendMessage(t *testing.T) {
+	var gotPath, gotKey string
+	var got struct {
+		Title    string                     `json:"title"`
+		Message  string                     `json:"message"`
+		Priority int                        `json:"priority"`
+		Extras   map[string]json.RawMessage `json:"extras"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("X-Gotify-Key")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		io.WriteString(w, `{"id":1}`)
+	}))
+	defer server.Close()
+
+	adapter := NewGotifyAdapter(&domain.Config{Gotify: domain.GotifyConfig{
+		ServerURL: server.URL + "/",
+		AppToken:  "AbCd",
+		ClickURL:  "https://example.com/solar",
+	}}, nil, nopLogger{})
+
+	if err := adapter.SendNotification(context.Background(), "Title", "Message", []byte("png"), domain.SeverityWarning); err != nil {
+		t.Fatalf("SendNotification: %v", err)
+	}
+	if gotPath != "/message" || gotKey != "AbCd" {
+		t.Errorf("path = %q, key = %q", gotPath, gotKey)
+	}
+	if got.Title != "Title" || got.Message != "Message" || got.Priority != 7 {
+		t.Errorf("message = %+v", got)
+	}
+	if string(got.Extras["client::notification"]) != `{"click":{"url":"https://example.com/solar"}}` ||
+		string(got.Extras["solarforecast::alert"]) != `{"severity":"warning"}` {
+		t.Errorf("extras = %s, %s", got.Extras["client::notification"], got.Extras["solarforecast::alert"])
+	}
+}
+
+func TestGotifyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, `{"error":"Unauthorized","errorCode":401,"errorDescription":"you need to provide a valid access token"}`)
+	}))
+	defer server.Close()
+
+	adapter := NewGotifyAdapter(&domain.Config{Gotify: domain.GotifyConfig{ServerURL: server.URL, AppToken: "wrong"}}, nil, nopLogger{})
+	err := adapter.SendNotification(context.Background(), "Title", "Message", nil, domain.SeverityCritical)
+	if err == nil || !strings.Contains(err.Error(), "valid access token") {
+		t.Errorf("err = %v, want the server's error description", err)
+	}
+}
+
+func TestGotifyPriority(t *testing.T) {
+	want := map[domain.Severity]int{domain.SeverityInfo: 4, domain.SeverityWarning: 7, domain.SeverityCritical: 10}
+	for severity, priority := range want {
+		if got := gotifyPriority(severity); got != priority {
+			t.Errorf("gotifyPriority(%s) = %d, want %d", severity, got, priority)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package adapters
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"time"
+)
+
+// mqttTimeout bounds a session when the context has no deadline
+const mqttTimeout = 30 * time.Second
+
+// MQTT 3.1.1 control packet types, in the high nibble of the fixed header
+const (
+	mqttConnect    byte = 0x10
+	mqttConnack    byte = 0x20
+	mqttPublish    byte = 0x30
+	mqttPuback     byte = 0x40
+	mqttDisconnect byte = 0xE0
+)
+
+// mqttConnackErrors are the CONNACK return codes refusing a connection
+var mqttConnackErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "client identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// mqttOptions describes how to connect to a broker
+type mqttOptions struct {
+	BrokerURL string // tcp://, mqtt://, ssl://, tls:// or mqtts://
+	ClientID  string
+	Username  string
+	Password  string
+	CAFile    string
+}
+
+// mqttClient is a minimal MQTT 3.1.1 client that publishes with QoS 0 or 1
+// over a clean session, enough to push sensor state to a broker
+type mqttClient struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	packetID uint16
+}
+
+// dialMQTT connects and logs in to the broker
+func dialMQTT(ctx context.Context, options mqttOptions) (*mqttClient, error) {
+	broker, err := url.Parse(options.BrokerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQTT broker URL: %w", err)
+	}
+	var useTLS bool
+	port := "1883"
+	switch broker.Scheme {
+	case "tcp", "mqtt":
+	case "ssl", "tls", "mqtts":
+		useTLS, port = true, "8883"
+	default:
+		return nil, fmt.Errorf("unsupported MQTT broker scheme %q", broker.Scheme)
+	}
+	if broker.Port() != "" {
+		port = broker.Port()
+	}
+	addr := net.JoinHostPort(broker.Hostname(), port)
+
+	dialer := &net.Dialer{Timeout: mqttTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(mqttTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	if useTLS {
+		tlsConfig, err := mqttTLSConfig(broker.Hostname(), options.CAFile)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("TLS handshake with %s failed: %w", addr, err)
+		}
+		conn = tlsConn
+	}
+
+	client := &mqttClient{conn: conn, reader: bufio.NewReader(conn)}
+	if err := client.connect(options); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("MQTT login to %s failed: %w", addr, err)
+	}
+	return client, nil
+}
+
+// mqttTLSConfig trusts the CA file's certificates instead of the system roots when set
+func mqttTLSConfig(host, caFile string) (*tls.Config, error) {
+	config := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	if caFile == "" {
+		return config, nil
+	}
+
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MQTT CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in MQTT CA file %s", caFile)
+	}
+	config.RootCAs = pool
+	return config, nil
+}
+
+// connect sends CONNECT and waits for the broker's CONNACK
+func (c *mqttClient) connect(options mqttOptions) error {
+	var flags byte = 0x02 // Clean session
+	payload := mqttString(options.ClientID)
+	if options.Username != "" {
+		flags |= 0x80
+		payload = append(payload, mqttString(options.Username)...)
+		if options.Password != "" {
+			flags |= 0x40
+			payload = append(payload, mqttString(options.Password)...)
+		}
+	}
+
+	body := append(mqttString("MQTT"), 4, flags, 0, 60) // Protocol level 4, 60 s keep alive
+	body = append(body, payload...)
+	if err := c.write(mqttConnect, body); err != nil {
+		return err
+	}
+
+	packetType, ack, err := readMQTTPacket(c.reader)
+	if err != nil {
+		return fmt.Errorf("failed to read CONNACK: %w", err)
+	}
+	if packetType&0xF0 != mqttConnack || len(ack) != 2 {
+		return fmt.Errorf("unexpected packet 0x%02x instead of CONNACK", packetType)
+	}
+	if code := ack[1]; code != 0 {
+		if reason, ok := mqttConnackErrors[code]; ok {
+			return errors.New(reason)
+		}
+		return fmt.Errorf("connection refused (code %d)", code)
+	}
+	return nil
+}
+
+// Publish sends a message. With QoS 1 it waits for the broker's PUBACK.
+func (c *mqttClient) Publish(topic string, payload []byte, qos byte, retain bool) error {
+	header := mqttPublish | qos<<1
+	if retain {
+		header |= 0x01
+	}
+	body := mqttString(topic)
+	var id uint16
+	if qos > 0 {
+		c.packetID++
+		if c.packetID == 0 {
+			c.packetID = 1
+		}
+		id = c.packetID
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	body = append(body, payload...)
+	if err := c.write(header, body); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topic, err)
+	}
+	if qos == 0 {
+		return nil
+	}
+
+	for {
+		packetType, ack, err := readMQTTPacket(c.reader)
+		if err != nil {
+			return fmt.Errorf("no PUBACK for %s: %w", topic, err)
+		}
+		if packetType&0xF0 == mqttPuback && len(ack) == 2 && binary.BigEndian.Uint16(ack) == id {
+			return nil
+		}
+	}
+}
+
+// Disconnect ends the session cleanly and closes the connection
+func (c *mqttClient) Disconnect() error {
+	err := c.write(mqttDisconnect, nil)
+	if closeErr := c.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// write sends one control packet
+func (c *mqttClient) write(header byte, body []byte) error {
+	packet := append([]byte{header}, mqttRemainingLength(len(body))...)
+	_, err := c.conn.Write(append(packet, body...))
+	return err
+}
+
+// readMQTTPacket reads one control packet, returning its first header byte and body
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7F) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, errors.New("malformed MQTT remaining length")
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+// mqttRemainingLength encodes a packet body length as MQTT's variable length integer
+func mqttRemainingLength(n int) []byte {
+	var encoded []byte
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		encoded = append(encoded, b)
+		if n == 0 {
+			return encoded
+		}
+	}
+}
+
+// mqttString encodes a length-prefixed UTF-8 string
+func mqttString(s string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(s))), s...)
+}
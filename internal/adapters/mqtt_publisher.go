@@ -0,0 +1,253 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// MQTTPublisher implements ForecastPublisher by publishing the forecast as MQTT
+// sensor state, with Home Assistant discovery messages that create the sensors
+type MQTTPublisher struct {
+	config domain.MQTTConfig
+	logger domain.Logger
+}
+
+// NewMQTTPublisher creates a new MQTT publisher
+func NewMQTTPublisher(config *domain.Config, logger domain.Logger) *MQTTPublisher {
+	return &MQTTPublisher{
+		config: config.MQTT,
+		logger: logger,
+	}
+}
+
+// mqttState is the JSON published to the state topic
+type mqttState struct {
+	CurrentKW        float64              `json:"current_kw"`
+	TodayKWh         float64              `json:"today_kwh"`
+	TomorrowKWh      float64              `json:"tomorrow_kwh"`
+	TomorrowComplete bool                 `json:"tomorrow_complete"`
+	AlertActive      string               `json:"alert_active"` // ON or OFF
+	AlertSeverity    *domain.Severity     `json:"alert_severity"`
+	NextRecovery     *time.Time           `json:"next_recovery"`
+	AlertQuantile    domain.AlertQuantile `json:"alert_quantile"`
+	UpdatedAt        time.Time            `json:"updated_at"`
+}
+
+// mqttForecastHour is one hour of the forecast attributes
+type mqttForecastHour struct {
+	Hour       time.Time `json:"hour"`
+	KW         float64   `json:"kw"`
+	CloudCover int       `json:"cloud_cover"`
+	P10        *float64  `json:"p10,omitempty"`
+	P90        *float64  `json:"p90,omitempty"`
+}
+
+// mqttEntity is a Home Assistant entity created by discovery
+type mqttEntity struct {
+	component string // sensor or binary_sensor
+	objectID  string
+	config    map[string]any
+}
+
+// PublishForecast publishes the discovery config, the state and the hourly
+// forecast attributes in one broker session
+func (p *MQTTPublisher) PublishForecast(ctx context.Context, snapshot *domain.ForecastSnapshot) error {
+	if p.config.BrokerURL == "" {
+		p.logger.Debug("MQTT not configured, skipping forecast publishing")
+		return nil // Not an error, just not configured
+	}
+
+	messages, err := p.messages(snapshot)
+	if err != nil {
+		return err
+	}
+
+	client, err := dialMQTT(ctx, mqttOptions{
+		BrokerURL: p.config.BrokerURL,
+		ClientID:  p.config.ClientID,
+		Username:  p.config.Username,
+		Password:  p.config.Password,
+		CAFile:    p.config.CAFile,
+	})
+	if err != nil {
+		p.logger.Error("Failed to connect to MQTT broker", "error", err.Error())
+		return err
+	}
+	defer client.Disconnect()
+
+	for _, msg := range messages {
+		if err := client.Publish(msg.topic, msg.payload, byte(p.config.QoS), msg.retain); err != nil {
+			p.logger.Error("Failed to publish MQTT message", "topic", msg.topic, "error", err.Error())
+			return err
+		}
+	}
+
+	p.logger.Info("Forecast published to MQTT", "topic_prefix", p.config.TopicPrefix, "messages", len(messages))
+	return nil
+}
+
+// mqttMessage is one message to publish
+type mqttMessage struct {
+	topic   string
+	payload []byte
+	retain  bool
+}
+
+// messages builds the discovery configs, followed by the state and forecast
+// attributes, so Home Assistant has subscribed before the state arrives.
+// Discovery configs are always retained so the sensors survive a Home Assistant restart.
+func (p *MQTTPublisher) messages(snapshot *domain.ForecastSnapshot) ([]mqttMessage, error) {
+	var messages []mqttMessage
+	if p.config.DiscoveryPrefix != "" {
+		nodeID := mqttNodeID(p.config.TopicPrefix)
+		for _, entity := range p.entities(nodeID) {
+			payload, err := json.Marshal(entity.config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode MQTT discovery config: %w", err)
+			}
+			topic := fmt.Sprintf("%s/%s/%s/%s/config", p.config.DiscoveryPrefix, entity.component, nodeID, entity.objectID)
+			messages = append(messages, mqttMessage{topic: topic, payload: payload, retain: true})
+		}
+	}
+
+	state, err := json.Marshal(newMQTTState(snapshot))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode MQTT state: %w", err)
+	}
+	forecast, err := json.Marshal(map[string]any{"forecast": newMQTTForecast(snapshot, p.config.ForecastHours)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode MQTT forecast: %w", err)
+	}
+	return append(messages,
+		mqttMessage{topic: p.stateTopic(), payload: state, retain: p.config.Retain},
+		mqttMessage{topic: p.forecastTopic(), payload: forecast, retain: p.config.Retain},
+	), nil
+}
+
+// stateTopic carries the sensor values
+func (p *MQTTPublisher) stateTopic() string {
+	return p.config.TopicPrefix + "/state"
+}
+
+// forecastTopic carries the hourly forecast, exposed as attributes of the power sensor
+func (p *MQTTPublisher) forecastTopic() string {
+	return p.config.TopicPrefix + "/forecast"
+}
+
+// entities returns the Home Assistant entities, all grouped under one device
+func (p *MQTTPublisher) entities(nodeID string) []mqttEntity {
+	device := map[string]any{
+		"identifiers":  []string{nodeID},
+		"name":         "Solar Forecast",
+		"manufacturer": "solar-forecast",
+		"model":        "PV production forecast",
+	}
+	entity := func(component, objectID, name string, config map[string]any) mqttEntity {
+		config["name"] = name
+		config["unique_id"] = nodeID + "_" + objectID
+		config["state_topic"] = p.stateTopic()
+		config["device"] = device
+		return mqttEntity{component: component, objectID: objectID, config: config}
+	}
+
+	// The energy sensors are forecasts that are revised each run, not meter readings,
+	// so they carry no state_class and stay out of long-term energy statistics
+	return []mqttEntity{
+		entity("sensor", "current_power", "Current power", map[string]any{
+			"device_class":          "power",
+			"unit_of_measurement":   "kW",
+			"state_class":           "measurement",
+			"value_template":        "{{ value_json.current_kw }}",
+			"json_attributes_topic": p.forecastTopic(),
+		}),
+		entity("sensor", "energy_today", "Energy today", map[string]any{
+			"device_class":        "energy",
+			"unit_of_measurement": "kWh",
+			"value_template":      "{{ value_json.today_kwh }}",
+		}),
+		entity("sensor", "energy_tomorrow", "Energy tomorrow", map[string]any{
+			"device_class":        "energy",
+			"unit_of_measurement": "kWh",
+			"value_template":      "{{ value_json.tomorrow_kwh }}",
+		}),
+		entity("binary_sensor", "alert", "Low production alert", map[string]any{
+			"device_class":             "problem",
+			"value_template":           "{{ value_json.alert_active }}",
+			"payload_on":               "ON",
+			"payload_off":              "OFF",
+			"json_attributes_topic":    p.stateTopic(),
+			"json_attributes_template": "{{ {'severity': value_json.alert_severity} | tojson }}",
+		}),
+		// Without a recovery the template renders the literal None, which the MQTT
+		// sensor reports as unknown rather than failing to parse a timestamp
+		entity("sensor", "next_recovery", "Next recovery", map[string]any{
+			"device_class":   "timestamp",
+			"value_template": "{{ value_json.next_recovery if value_json.next_recovery else None }}",
+		}),
+	}
+}
+
+// newMQTTState converts a snapshot to the state payload
+func newMQTTState(snapshot *domain.ForecastSnapshot) mqttState {
+	state := mqttState{
+		CurrentKW:        roundKW(snapshot.CurrentKW),
+		TodayKWh:         roundKW(snapshot.TodayKWh),
+		TomorrowKWh:      roundKW(snapshot.TomorrowKWh),
+		TomorrowComplete: snapshot.TomorrowComplete,
+		AlertActive:      "OFF",
+		NextRecovery:     optionalTime(snapshot.NextRecovery),
+		AlertQuantile:    snapshot.AlertQuantile,
+		UpdatedAt:        snapshot.GeneratedAt,
+	}
+	if state.AlertQuantile == "" {
+		state.AlertQuantile = domain.AlertOnDeterministic
+	}
+	if snapshot.AlertActive {
+		severity := snapshot.AlertSeverity
+		state.AlertActive = "ON"
+		state.AlertSeverity = &severity
+	}
+	return state
+}
+
+// newMQTTForecast converts up to hours forecast hours to the attribute list.
+// Home Assistant warns about attributes over 16 KB, so the list is kept short.
+func newMQTTForecast(snapshot *domain.ForecastSnapshot, hours int) []mqttForecastHour {
+	production := snapshot.Production
+	if hours > 0 && len(production) > hours {
+		production = production[:hours]
+	}
+	forecast := make([]mqttForecastHour, 0, len(production))
+	for _, prod := range production {
+		hour := mqttForecastHour{
+			Hour:       prod.Hour,
+			KW:         roundKW(prod.OutputAt(snapshot.AlertQuantile)),
+			CloudCover: prod.CloudCover,
+		}
+		if q := prod.Quantiles; q != nil {
+			p10, p90 := roundKW(q.P10), roundKW(q.P90)
+			hour.P10, hour.P90 = &p10, &p90
+		}
+		forecast = append(forecast, hour)
+	}
+	return forecast
+}
+
+// roundKW rounds power or energy to the 10 W or 10 Wh the sensors show
+func roundKW(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// mqttNodeID turns the topic prefix into a discovery node ID, which may only
+// contain letters, digits, underscores and hyphens
+func mqttNodeID(prefix string) string {
+	return mqttNodeIDInvalid.ReplaceAllString(prefix, "_")
+}
+
+var mqttNodeIDInvalid = regexp.MustCompile(`[^A-Za-z0-9_-]`)
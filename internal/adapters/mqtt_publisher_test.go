@@ -0,0 +1,147 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+func newMQTTTestSnapshot() *domain.ForecastSnapshot {
+	now := time.Date(2026, 3, 2, 10, 20, 0, 0, time.UTC)
+	snapshot := &domain.ForecastSnapshot{
+		GeneratedAt:   now,
+		CurrentKW:     1.234,
+		TodayKWh:      9.876,
+		TomorrowKWh:   20,
+		AlertActive:   true,
+		AlertSeverity: domain.SeverityWarning,
+		NextRecovery:  now.Add(5 * time.Hour).Truncate(time.Hour),
+	}
+	for i := 0; i < 4; i++ {
+		snapshot.Production = append(snapshot.Production, domain.SolarProduction{
+			Hour:              now.Truncate(time.Hour).Add(time.Duration(i) * time.Hour),
+			EstimatedOutputKW: 1.5,
+			CloudCover:        80,
+		})
+	}
+	snapshot.Production[0].Quantiles = &domain.ProductionQuantiles{P10: 0.5, P50: 1.5, P90: 2.5, Members: 51}
+	return snapshot
+}
+
+func TestMQTTPublishForecast(t *testing.T) {
+	broker := newTestBroker(t, nil)
+	config := &domain.Config{MQTT: domain.MQTTConfig{
+		BrokerURL:       broker.URL("tcp"),
+		ClientID:        "solar-forecast",
+		TopicPrefix:     "home/solar",
+		DiscoveryPrefix: "homeassistant",
+		Retain:          false,
+		QoS:             1,
+		ForecastHours:   3,
+	}}
+
+	if err := NewMQTTPublisher(config, nopLogger{}).PublishForecast(context.Background(), newMQTTTestSnapshot()); err != nil {
+		t.Fatalf("PublishForecast: %v", err)
+	}
+	broker.Close()
+
+	messages := make(map[string]testBrokerMessage)
+	var order []string
+	for _, msg := range broker.Messages() {
+		messages[msg.Topic] = msg
+		order = append(order, msg.Topic)
+	}
+	if len(order) != 7 || order[5] != "home/solar/state" || order[6] != "home/solar/forecast" {
+		t.Fatalf("topics = %v, want 5 discovery configs then state and forecast", order)
+	}
+
+	power, ok := messages["homeassistant/sensor/home_solar/current_power/config"]
+	if !ok || !power.Retain || power.QoS != 1 {
+		t.Fatalf("power discovery = %+v, want a retained QoS 1 config", power)
+	}
+	var discovery map[string]any
+	if err := json.Unmarshal([]byte(power.Payload), &discovery); err != nil {
+		t.Fatalf("decode discovery: %v", err)
+	}
+	if discovery["unique_id"] != "home_solar_current_power" || discovery["state_topic"] != "home/solar/state" ||
+		discovery["json_attributes_topic"] != "home/solar/forecast" || discovery["unit_of_measurement"] != "kW" {
+		t.Errorf("power discovery = %v", discovery)
+	}
+	var energy map[string]any
+	if err := json.Unmarshal([]byte(messages["homeassistant/sensor/home_solar/energy_today/config"].Payload), &energy); err != nil {
+		t.Fatalf("decode energy discovery: %v", err)
+	}
+	if _, ok := energy["state_class"]; energy["device_class"] != "energy" || ok {
+		t.Errorf("energy discovery = %v, want an energy forecast without a state_class", energy)
+	}
+	if _, ok := messages["homeassistant/binary_sensor/home_solar/alert/config"]; !ok {
+		t.Error("no alert binary sensor discovery config")
+	}
+	var recovery map[string]any
+	if err := json.Unmarshal([]byte(messages["homeassistant/sensor/home_solar/next_recovery/config"].Payload), &recovery); err != nil {
+		t.Fatalf("decode next recovery discovery: %v", err)
+	}
+	if recovery["device_class"] != "timestamp" ||
+		recovery["value_template"] != "{{ value_json.next_recovery if value_json.next_recovery else None }}" {
+		t.Errorf("next recovery discovery = %v, want a timestamp that is unknown without a recovery", recovery)
+	}
+
+	state := messages["home/solar/state"]
+	if state.Retain {
+		t.Error("state retained with mqtt_retain=false")
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(state.Payload), &got); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	if got["current_kw"] != 1.23 || got["today_kwh"] != 9.88 || got["alert_active"] != "ON" ||
+		got["alert_severity"] != "warning" || got["next_recovery"] != "2026-03-02T15:00:00Z" {
+		t.Errorf("state = %v", got)
+	}
+
+	var forecast struct {
+		Forecast []mqttForecastHour `json:"forecast"`
+	}
+	if err := json.Unmarshal([]byte(messages["home/solar/forecast"].Payload), &forecast); err != nil {
+		t.Fatalf("decode forecast: %v", err)
+	}
+	if len(forecast.Forecast) != 3 || forecast.Forecast[0].P10 == nil || *forecast.Forecast[0].P10 != 0.5 || forecast.Forecast[1].P10 != nil {
+		t.Errorf("forecast = %+v, want 3 hours with the first hour's band", forecast.Forecast)
+	}
+}
+
+func TestMQTTPublishWithoutDiscovery(t *testing.T) {
+	broker := newTestBroker(t, nil)
+	config := &domain.Config{MQTT: domain.MQTTConfig{
+		BrokerURL:   broker.URL("tcp"),
+		ClientID:    "solar-forecast",
+		TopicPrefix: "solar_forecast",
+		Retain:      true,
+	}}
+	snapshot := newMQTTTestSnapshot()
+	snapshot.AlertActive, snapshot.NextRecovery = false, time.Time{}
+
+	if err := NewMQTTPublisher(config, nopLogger{}).PublishForecast(context.Background(), snapshot); err != nil {
+		t.Fatalf("PublishForecast: %v", err)
+	}
+	broker.Close()
+
+	messages := broker.Messages()
+	if len(messages) != 2 || !messages[0].Retain || messages[0].QoS != 0 {
+		t.Fatalf("messages = %+v, want retained QoS 0 state and forecast only", messages)
+	}
+	var got map[string]any
+	json.Unmarshal([]byte(messages[0].Payload), &got)
+	if got["alert_active"] != "OFF" || got["alert_severity"] != nil || got["next_recovery"] != nil {
+		t.Errorf("state = %v", got)
+	}
+}
+
+func TestMQTTNodeID(t *testing.T) {
+	if got := mqttNodeID("home/solar forecast"); got != "home_solar_forecast" {
+		t.Errorf("mqttNodeID = %q", got)
+	}
+}
@@ -0,0 +1,266 @@
+package adapters
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/pem"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testBrokerLogin is the client ID and credentials of a CONNECT
+type testBrokerLogin struct {
+	ClientID string
+	Username string
+	Password string
+}
+
+// testBrokerMessage is one received PUBLISH
+type testBrokerMessage struct {
+	Topic   string
+	Payload string
+	QoS     byte
+	Retain  bool
+}
+
+// testBroker is an in-process MQTT 3.1.1 broker that records logins and
+// publishes and acknowledges them, standing in for Mosquitto
+type testBroker struct {
+	listener  net.Listener
+	wg        sync.WaitGroup
+	connackRC byte // CONNACK return code; non-zero refuses logins
+
+	mu       sync.Mutex
+	logins   []testBrokerLogin
+	messages []testBrokerMessage
+}
+
+// newTestBroker starts a broker on a local port, over TLS when tlsConfig is set
+func newTestBroker(t *testing.T, tlsConfig *tls.Config) *testBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	b := &testBroker{listener: listener}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			b.wg.Add(1)
+			go func() {
+				defer b.wg.Done()
+				defer conn.Close()
+				b.serve(conn)
+			}()
+		}
+	}()
+	t.Cleanup(b.Close)
+	return b
+}
+
+// URL returns the broker URL with the given scheme
+func (b *testBroker) URL(scheme string) string {
+	return scheme + "://" + b.listener.Addr().String()
+}
+
+// Close stops the broker and waits until every session has been handled
+func (b *testBroker) Close() {
+	b.listener.Close()
+	b.wg.Wait()
+}
+
+// Messages returns the received publishes
+func (b *testBroker) Messages() []testBrokerMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]testBrokerMessage{}, b.messages...)
+}
+
+// serve handles one client session
+func (b *testBroker) serve(conn net.Conn) {
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(conn)
+
+	packetType, body, err := readMQTTPacket(reader)
+	if err != nil || packetType != mqttConnect {
+		return
+	}
+	_, body = testMQTTString(body) // Protocol name
+	flags := body[1]
+	clientID, body := testMQTTString(body[4:])
+	login := testBrokerLogin{ClientID: clientID}
+	if flags&0x80 != 0 {
+		login.Username, body = testMQTTString(body)
+	}
+	if flags&0x40 != 0 {
+		login.Password, _ = testMQTTString(body)
+	}
+	b.mu.Lock()
+	b.logins = append(b.logins, login)
+	b.mu.Unlock()
+
+	conn.Write([]byte{mqttConnack, 2, 0, b.connackRC})
+	if b.connackRC != 0 {
+		return
+	}
+
+	for {
+		header, body, err := readMQTTPacket(reader)
+		if err != nil || header&0xF0 == mqttDisconnect {
+			return
+		}
+		if header&0xF0 != mqttPublish {
+			continue
+		}
+		msg := testBrokerMessage{QoS: header >> 1 & 0x03, Retain: header&0x01 != 0}
+		msg.Topic, body = testMQTTString(body)
+		if msg.QoS > 0 {
+			conn.Write([]byte{mqttPuback, 2, body[0], body[1]})
+			body = body[2:]
+		}
+		msg.Payload = string(body)
+		b.mu.Lock()
+		b.messages = append(b.messages, msg)
+		b.mu.Unlock()
+	}
+}
+
+// testMQTTString decodes a length-prefixed string and returns the rest
+func testMQTTString(b []byte) (string, []byte) {
+	n := int(binary.BigEndian.Uint16(b))
+	return string(b[2 : 2+n]), b[2+n:]
+}
+
+func TestMQTTClientPublish(t *testing.T) {
+	broker := newTestBroker(t, nil)
+
+	client, err := dialMQTT(context.Background(), mqttOptions{
+		BrokerURL: broker.URL("tcp"),
+		ClientID:  "solar-test",
+		Username:  "homeassistant",
+		Password:  "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("dialMQTT: %v", err)
+	}
+	if err := client.Publish("solar/state", []byte(`{"on":true}`), 1, true); err != nil {
+		t.Fatalf("Publish QoS 1: %v", err)
+	}
+	long := strings.Repeat("x", 300) // Needs a two-byte remaining length
+	if err := client.Publish("solar/long", []byte(long), 0, false); err != nil {
+		t.Fatalf("Publish QoS 0: %v", err)
+	}
+	if err := client.Disconnect(); err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+	broker.Close()
+
+	if want := (testBrokerLogin{"solar-test", "homeassistant", "s3cret"}); len(broker.logins) != 1 || broker.logins[0] != want {
+		t.Errorf("logins = %+v, want %+v", broker.logins, want)
+	}
+	want := []testBrokerMessage{
+		{Topic: "solar/state", Payload: `{"on":true}`, QoS: 1, Retain: true},
+		{Topic: "solar/long", Payload: long},
+	}
+	got := broker.Messages()
+	if len(got) != len(want) {
+		t.Fatalf("messages = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("message %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMQTTClientLoginRefused(t *testing.T) {
+	broker := newTestBroker(t, nil)
+	broker.connackRC = 4
+
+	_, err := dialMQTT(context.Background(), mqttOptions{BrokerURL: broker.URL("mqtt"), ClientID: "solar-test", Username: "wrong"})
+	if err == nil || !strings.Contains(err.Error(), "bad user name or password") {
+		t.Errorf("err = %v, want the refusal reason", err)
+	}
+}
+
+func TestMQTTClientTLS(t *testing.T) {
+	// The httptest certificate is valid for 127.0.0.1
+	server := httptest.NewTLSServer(nil)
+	server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	broker := newTestBroker(t, server.TLS)
+
+	if _, err := dialMQTT(context.Background(), mqttOptions{BrokerURL: broker.URL("ssl"), ClientID: "solar-test"}); err == nil {
+		t.Error("dialMQTT trusted a certificate outside the system roots")
+	}
+
+	client, err := dialMQTT(context.Background(), mqttOptions{BrokerURL: broker.URL("mqtts"), ClientID: "solar-test", CAFile: caFile})
+	if err != nil {
+		t.Fatalf("dialMQTT with CA file: %v", err)
+	}
+	if err := client.Publish("solar/state", []byte("{}"), 1, false); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	client.Disconnect()
+	broker.Close()
+	if got := broker.Messages(); len(got) != 1 || got[0].Topic != "solar/state" {
+		t.Errorf("messages = %+v", got)
+	}
+}
+
+func TestMQTTRemainingLength(t *testing.T) {
+	tests := map[int][]byte{
+		0:       {0x00},
+		127:     {0x7F},
+		128:     {0x80, 0x01},
+		16383:   {0xFF, 0x7F},
+		2097152: {0x80, 0x80, 0x80, 0x01},
+	}
+	for n, want := range tests {
+		if got := mqttRemainingLength(n); string(got) != string(want) {
+			t.Errorf("mqttRemainingLength(%d) = % x, want % x", n, got, want)
+		}
+	}
+}
+
+// TestMQTTClientLocalBroker publishes to a real broker, such as a local
+// Mosquitto, when SOLAR_TEST_MQTT_BROKER is set (e.g. tcp://localhost:1883)
+func TestMQTTClientLocalBroker(t *testing.T) {
+	brokerURL := os.Getenv("SOLAR_TEST_MQTT_BROKER")
+	if brokerURL == "" {
+		t.Skip("SOLAR_TEST_MQTT_BROKER not set")
+	}
+
+	client, err := dialMQTT(context.Background(), mqttOptions{
+		BrokerURL: brokerURL,
+		ClientID:  "solar-forecast-test",
+		Username:  os.Getenv("SOLAR_TEST_MQTT_USERNAME"),
+		Password:  os.Getenv("SOLAR_TEST_MQTT_PASSWORD"),
+	})
+	if err != nil {
+		t.Fatalf("dialMQTT: %v", err)
+	}
+	defer client.Disconnect()
+	if err := client.Publish("solar_forecast/test", []byte(time.Now().Format(time.RFC3339)), 1, false); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
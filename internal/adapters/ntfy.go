@@ -0,0 +1,141 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+const defaultNtfyServerURL = "https://ntfy.sh"
+
+// NtfyAdapter implements PushNotifier by publishing to an ntfy topic
+type NtfyAdapter struct {
+	pushMessages
+	serverURL   string
+	topic       string
+	accessToken string
+	username    string
+	password    string
+	clickURL    string   // Opened when the notification is tapped
+	tags        []string // Extra tags (emoji short codes) added to every message
+	client      *http.Client
+}
+
+// NewNtfyAdapter creates a new ntfy adapter
+func NewNtfyAdapter(config *domain.Config, templates *Templates, logger domain.Logger) *NtfyAdapter {
+	serverURL := config.Ntfy.ServerURL
+	if serverURL == "" {
+		serverURL = defaultNtfyServerURL
+	}
+	return &NtfyAdapter{
+		pushMessages: newPushMessages(config, templates, logger),
+		serverURL:    strings.TrimRight(serverURL, "/"),
+		topic:        config.Ntfy.Topic,
+		accessToken:  config.Ntfy.AccessToken,
+		username:     config.Ntfy.Username,
+		password:     config.Ntfy.Password,
+		clickURL:     config.Ntfy.ClickURL,
+		tags:         config.Ntfy.Tags,
+		client:       &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// ntfyPriority maps an alert severity to an ntfy priority (1 min to 5 max):
+// info is default (3), warning is high (4) and critical is max (5)
+func ntfyPriority(severity domain.Severity) int {
+	switch severity {
+	case domain.SeverityInfo:
+		return 3
+	case domain.SeverityCritical:
+		return 5
+	default:
+		return 4
+	}
+}
+
+// ntfySeverityTag is the emoji tag shown in front of the title
+func ntfySeverityTag(severity domain.Severity) string {
+	switch severity {
+	case domain.SeverityInfo:
+		return "information_source"
+	case domain.SeverityCritical:
+		return "rotating_light"
+	default:
+		return "warning"
+	}
+}
+
+// SendNotification publishes the message to the topic. The chart is uploaded
+// as an attachment, with the message moving to a header.
+func (n *NtfyAdapter) SendNotification(ctx context.Context, title, message string, imageData []byte, severity domain.Severity) error {
+	if n.topic == "" {
+		n.logger.Debug("ntfy not configured, skipping notification")
+		return nil // Not an error, just not configured
+	}
+
+	var body io.Reader = strings.NewReader(message)
+	method := http.MethodPost
+	if len(imageData) > 0 {
+		body = bytes.NewReader(imageData)
+		method = http.MethodPut
+	}
+	req, err := http.NewRequestWithContext(ctx, method, n.serverURL+"/"+n.topic, body)
+	if err != nil {
+		return fmt.Errorf("failed to create ntfy request: %w", err)
+	}
+
+	// Header values are RFC 2047 encoded so titles with emoji and multi-line messages survive
+	priority := ntfyPriority(severity)
+	req.Header.Set("X-Title", mime.BEncoding.Encode("UTF-8", title))
+	req.Header.Set("X-Priority", strconv.Itoa(priority))
+	req.Header.Set("X-Tags", strings.Join(append([]string{ntfySeverityTag(severity)}, n.tags...), ","))
+	if n.clickURL != "" {
+		req.Header.Set("X-Click", n.clickURL)
+	}
+	if len(imageData) > 0 {
+		req.Header.Set("X-Filename", "chart.png")
+		req.Header.Set("X-Message", mime.BEncoding.Encode("UTF-8", message))
+	}
+	if n.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+n.accessToken)
+	} else if n.username != "" {
+		req.SetBasicAuth(n.username, n.password)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		n.logger.Error("Failed to send ntfy notification", "error", err.Error())
+		return fmt.Errorf("failed to send ntfy notification: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		// ntfy reports errors as {"code":40301,"http":403,"error":"forbidden"}
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		description := strings.TrimSpace(string(respBody))
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
+			description = apiErr.Error
+		}
+		n.logger.Error("ntfy server returned error", "status", resp.StatusCode, "error", description)
+		return fmt.Errorf("ntfy error: %s (status %d)", description, resp.StatusCode)
+	}
+
+	n.logger.Info("ntfy notification sent successfully",
+		"title", title,
+		"topic", n.topic,
+		"priority", priority,
+		"has_image", len(imageData) > 0)
+	return nil
+}
@@ -0,0 +1,104 @@
+package adapters
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+func TestNtfyPublishWithAttachment(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		io.WriteString(w, `{"id":"abc","event":"message"}`)
+	}))
+	defer server.Close()
+
+	adapter := NewNtfyAdapter(&domain.Config{Ntfy: domain.NtfyConfig{
+		ServerURL:   server.URL,
+		Topic:       "solar",
+		AccessToken: "tk_secret",
+		ClickURL:    "https://example.com/solar",
+		Tags:        []string{"sunny"},
+	}}, nil, nopLogger{})
+
+	err := adapter.SendNotification(context.Background(), "🚨 Solar Production Critical", "Low production\nfor 6 hours", []byte("png"), domain.SeverityCritical)
+	if err != nil {
+		t.Fatalf("SendNotification: %v", err)
+	}
+
+	if got.Method != http.MethodPut || got.URL.Path != "/solar" || string(gotBody) != "png" {
+		t.Errorf("request = %s %s with body %q, want the chart PUT to the topic", got.Method, got.URL.Path, gotBody)
+	}
+	decoder := new(mime.WordDecoder)
+	title, _ := decoder.DecodeHeader(got.Header.Get("X-Title"))
+	message, _ := decoder.DecodeHeader(got.Header.Get("X-Message"))
+	if title != "🚨 Solar Production Critical" || message != "Low production\nfor 6 hours" {
+		t.Errorf("title = %q, message = %q", title, message)
+	}
+	headers := map[string]string{
+		"X-Priority":    "5",
+		"X-Tags":        "rotating_light,sunny",
+		"X-Click":       "https://example.com/solar",
+		"X-Filename":    "chart.png",
+		"Authorization": "Bearer tk_secret",
+	}
+	for name, want := range headers {
+		if value := got.Header.Get(name); value != want {
+			t.Errorf("%s = %q, want %q", name, value, want)
+		}
+	}
+}
+
+func TestNtfyPublishTextAndError(t *testing.T) {
+	var gotMethod, gotBody, gotUser string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		gotUser, _, _ = r.BasicAuth()
+		if r.Header.Get("X-Priority") == "4" {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"code":40301,"http":403,"error":"forbidden"}`)
+			return
+		}
+		io.WriteString(w, `{}`)
+	}))
+	defer server.Close()
+
+	adapter := NewNtfyAdapter(&domain.Config{Ntfy: domain.NtfyConfig{
+		ServerURL: server.URL,
+		Topic:     "solar",
+		Username:  "alice",
+		Password:  "secret",
+	}}, nil, nopLogger{})
+
+	if err := adapter.SendNotification(context.Background(), "Title", "Recovered", nil, domain.SeverityInfo); err != nil {
+		t.Fatalf("SendNotification: %v", err)
+	}
+	if gotMethod != http.MethodPost || gotBody != "Recovered" || gotUser != "alice" {
+		t.Errorf("request = %s %q as %q, want the message POSTed with basic auth", gotMethod, gotBody, gotUser)
+	}
+
+	err := adapter.SendNotification(context.Background(), "Title", "Message", nil, domain.SeverityWarning)
+	if err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Errorf("err = %v, want the server's error", err)
+	}
+}
+
+func TestNtfyPriority(t *testing.T) {
+	want := map[domain.Severity]int{domain.SeverityInfo: 3, domain.SeverityWarning: 4, domain.SeverityCritical: 5}
+	for severity, priority := range want {
+		if got := ntfyPriority(severity); got != priority {
+			t.Errorf("ntfyPriority(%s) = %d, want %d", severity, got, priority)
+		}
+	}
+}
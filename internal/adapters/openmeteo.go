@@ -13,23 +13,33 @@ import (
 
 // OpenMeteoAdapter implements WeatherForecastProvider using Open-Meteo API
 type OpenMeteoAdapter struct {
-	httpClient      *http.Client
-	retryAttempts   int
-	retryDelay      time.Duration
-	logger          domain.Logger
+	httpClient    *http.Client
+	retryAttempts int
+	retryDelay    time.Duration
+	tiltedPlane   domain.PanelOrientation // Plane requested for global_tilted_irradiance
+	logger        domain.Logger
 }
 
 // OpenMeteoResponse represents the API response structure
 type OpenMeteoResponse struct {
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	Hourly    struct {
-		Time                     []string  `json:"time"`
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	Timezone             string  `json:"timezone"`
+	UTCOffsetSeconds     int     `json:"utc_offset_seconds"`
+	TimezoneAbbreviation string  `json:"timezone_abbreviation"`
+	Hourly               struct {
+		Time                     []int64   `json:"time"` // Unix seconds
 		Temperature2m            []float64 `json:"temperature_2m"`
 		CloudCover               []int     `json:"cloud_cover"`
 		ShortwaveRadiation       []float64 `json:"shortwave_radiation"`
 		RelativeHumidity2m       []int     `json:"relative_humidity_2m"`
 		PrecipitationProbability []int     `json:"precipitation_probability"`
+
+		// Optional irradiance components; entries may be null for some hours or models
+		DirectNormalIrradiance []*float64 `json:"direct_normal_irradiance"`
+		DiffuseRadiation       []*float64 `json:"diffuse_radiation"`
+		GlobalTiltedIrradiance []*float64 `json:"global_tilted_irradiance"`
+		WindSpeed10m           []*float64 `json:"wind_speed_10m"`
 	} `json:"hourly"`
 }
 
@@ -41,15 +51,19 @@ func NewOpenMeteoAdapter(config *domain.Config, logger domain.Logger) *OpenMeteo
 		},
 		retryAttempts: config.APIRetryAttempts,
 		retryDelay:    time.Duration(config.APIRetryDelaySeconds) * time.Second,
-		logger:        logger,
+		// Tilted irradiance can only be requested for one plane; use the first array
+		tiltedPlane: config.PVArrays()[0].Orientation(),
+		logger:      logger,
 	}
 }
 
 // GetForecast fetches 7-day weather forecast from Open-Meteo API with retries
 func (a *OpenMeteoAdapter) GetForecast(ctx context.Context, latitude, longitude float64) (*domain.ForecastData, error) {
+	// Open-Meteo measures azimuth from south (0) with east negative and west positive
 	url := fmt.Sprintf(
-		"https://api.open-meteo.com/v1/forecast?latitude=%.2f&longitude=%.2f&hourly=temperature_2m,cloud_cover,shortwave_radiation,relative_humidity_2m,precipitation_probability&forecast_days=7&timezone=auto",
+		"https://api.open-meteo.com/v1/forecast?latitude=%.2f&longitude=%.2f&hourly=temperature_2m,cloud_cover,shortwave_radiation,relative_humidity_2m,precipitation_probability,direct_normal_irradiance,diffuse_radiation,global_tilted_irradiance,wind_speed_10m&wind_speed_unit=ms&tilt=%.1f&azimuth=%.1f&forecast_days=7&timezone=auto&timeformat=unixtime",
 		latitude, longitude,
+		a.tiltedPlane.TiltDeg, a.tiltedPlane.AzimuthDeg-180,
 	)
 
 	var lastErr error
@@ -131,12 +145,12 @@ func (a *OpenMeteoAdapter) buildForecastData(apiResp OpenMeteoResponse) (*domain
 		minLen = len(apiResp.Hourly.PrecipitationProbability)
 	}
 
+	// Hours come as Unix times, so each is the correct instant, which the solar
+	// position calculation depends on; the zone only sets the local calendar days
+	location := forecastLocation(apiResp.Timezone, apiResp.TimezoneAbbreviation, apiResp.UTCOffsetSeconds)
+
 	for i := 0; i < minLen && i < 168; i++ { // Limit to 168 hours (7 days)
-		hour, err := time.Parse("2006-01-02T15:04", apiResp.Hourly.Time[i])
-		if err != nil {
-			a.logger.Error("Failed to parse time", "time_string", apiResp.Hourly.Time[i], "error", err.Error())
-			continue
-		}
+		hour := time.Unix(apiResp.Hourly.Time[i], 0).In(location)
 
 		cloudCover := apiResp.Hourly.CloudCover[i]
 		if cloudCover < 0 {
@@ -162,14 +176,35 @@ func (a *OpenMeteoAdapter) buildForecastData(apiResp OpenMeteoResponse) (*domain
 			precipProb = 100
 		}
 
-		forecast.Hours = append(forecast.Hours, domain.ForecastHour{
-			Hour:                        hour,
-			Temperature:                 apiResp.Hourly.Temperature2m[i],
-			CloudCover:                  cloudCover,
+		forecastHour := domain.ForecastHour{
+			Hour:                       hour,
+			Temperature:                apiResp.Hourly.Temperature2m[i],
+			CloudCover:                 cloudCover,
 			GlobalHorizontalIrradiance: apiResp.Hourly.ShortwaveRadiation[i],
-			RelativeHumidity:            humidity,
-			PrecipitationProbability:    precipProb,
-		})
+			RelativeHumidity:           humidity,
+			PrecipitationProbability:   precipProb,
+			WindSpeed:                  domain.DefaultWindSpeed,
+		}
+
+		if wind, ok := optionalValue(apiResp.Hourly.WindSpeed10m, i); ok {
+			forecastHour.WindSpeed = wind
+		}
+
+		// Beam/diffuse split - fall back to GHI-only modelling if either is missing
+		dni, hasDNI := optionalValue(apiResp.Hourly.DirectNormalIrradiance, i)
+		dhi, hasDHI := optionalValue(apiResp.Hourly.DiffuseRadiation, i)
+		if hasDNI && hasDHI {
+			forecastHour.DirectNormalIrradiance = dni
+			forecastHour.DiffuseHorizontalIrradiance = dhi
+			forecastHour.HasIrradianceComponents = true
+		}
+
+		if gti, ok := optionalValue(apiResp.Hourly.GlobalTiltedIrradiance, i); ok {
+			forecastHour.GlobalTiltedIrradiance = gti
+			forecastHour.TiltedPlane = &a.tiltedPlane
+		}
+
+		forecast.Hours = append(forecast.Hours, forecastHour)
 	}
 
 	if len(forecast.Hours) == 0 {
@@ -178,3 +213,23 @@ func (a *OpenMeteoAdapter) buildForecastData(apiResp OpenMeteoResponse) (*domain
 
 	return forecast, nil
 }
+
+// optionalValue returns the i-th value of an optional hourly series and whether it was present
+func optionalValue(values []*float64, i int) (float64, bool) {
+	if i >= len(values) || values[i] == nil {
+		return 0, false
+	}
+	return *values[i], true
+}
+
+// forecastLocation returns the time zone Open-Meteo resolved for the location,
+// so hours after a DST change get their own offset. When the zone is not in the
+// tz database, the fixed offset of the first forecast hour is used.
+func forecastLocation(timezone, abbreviation string, utcOffsetSeconds int) *time.Location {
+	if timezone != "" {
+		if location, err := time.LoadLocation(timezone); err == nil {
+			return location
+		}
+	}
+	return time.FixedZone(abbreviation, utcOffsetSeconds)
+}
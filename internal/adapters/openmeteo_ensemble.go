@@ -0,0 +1,246 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// Hourly variables requested from the ensemble API. Each is returned once per member,
+// e.g. "shortwave_radiation_member07" (plus a "_<model>" suffix when several models are requested).
+// The irradiance components match the deterministic forecast, so members go
+// through the same beam/diffuse and tilted-plane modelling.
+const (
+	ensembleVarTemperature = "temperature_2m"
+	ensembleVarCloudCover  = "cloud_cover"
+	ensembleVarGHI         = "shortwave_radiation"
+	ensembleVarDNI         = "direct_normal_irradiance"
+	ensembleVarDHI         = "diffuse_radiation"
+	ensembleVarGTI         = "global_tilted_irradiance"
+	ensembleVarWindSpeed   = "wind_speed_10m"
+)
+
+var ensembleVariables = []string{
+	ensembleVarTemperature, ensembleVarCloudCover, ensembleVarGHI,
+	ensembleVarDNI, ensembleVarDHI, ensembleVarGTI, ensembleVarWindSpeed,
+}
+
+// OpenMeteoEnsembleAdapter implements EnsembleForecastProvider using the Open-Meteo ensemble API
+type OpenMeteoEnsembleAdapter struct {
+	httpClient    *http.Client
+	baseURL       string
+	models        []string
+	retryAttempts int
+	retryDelay    time.Duration
+	tiltedPlane   domain.PanelOrientation // Plane requested for global_tilted_irradiance
+	logger        domain.Logger
+}
+
+// OpenMeteoEnsembleResponse represents the ensemble API response structure.
+// Member series are keyed dynamically, so hourly data is decoded as raw JSON.
+type OpenMeteoEnsembleResponse struct {
+	Timezone             string                     `json:"timezone"`
+	UTCOffsetSeconds     int                        `json:"utc_offset_seconds"`
+	TimezoneAbbreviation string                     `json:"timezone_abbreviation"`
+	Hourly               map[string]json.RawMessage `json:"hourly"`
+}
+
+// NewOpenMeteoEnsembleAdapter creates a new Open-Meteo ensemble adapter
+func NewOpenMeteoEnsembleAdapter(config *domain.Config, logger domain.Logger) *OpenMeteoEnsembleAdapter {
+	return &OpenMeteoEnsembleAdapter{
+		httpClient: &http.Client{
+			Timeout: time.Duration(config.APITimeoutSeconds) * time.Second,
+		},
+		baseURL:       "https://ensemble-api.open-meteo.com/v1/ensemble",
+		models:        config.EnsembleModels,
+		retryAttempts: config.APIRetryAttempts,
+		retryDelay:    time.Duration(config.APIRetryDelaySeconds) * time.Second,
+		// The same plane as the deterministic forecast's tilted irradiance
+		tiltedPlane: config.PVArrays()[0].Orientation(),
+		logger:      logger,
+	}
+}
+
+// GetEnsembleForecast fetches all ensemble members from Open-Meteo with retries
+func (a *OpenMeteoEnsembleAdapter) GetEnsembleForecast(ctx context.Context, latitude, longitude float64) (*domain.EnsembleForecast, error) {
+	// Open-Meteo measures azimuth from south (0) with east negative and west positive
+	url := fmt.Sprintf(
+		"%s?latitude=%.2f&longitude=%.2f&hourly=%s&wind_speed_unit=ms&tilt=%.1f&azimuth=%.1f&models=%s&forecast_days=7&timezone=auto&timeformat=unixtime",
+		a.baseURL, latitude, longitude,
+		strings.Join(ensembleVariables, ","),
+		a.tiltedPlane.TiltDeg, a.tiltedPlane.AzimuthDeg-180,
+		strings.Join(a.models, ","),
+	)
+
+	var lastErr error
+	for attempt := 0; attempt < a.retryAttempts; attempt++ {
+		if attempt > 0 {
+			a.logger.Info("Retrying Open-Meteo ensemble API", "attempt", attempt, "delay_seconds", a.retryDelay.Seconds())
+			select {
+			case <-time.After(a.retryDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("User-Agent", "SolarForecast/1.0")
+
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			a.logger.Error("Failed to fetch from Open-Meteo ensemble API", "error", err.Error(), "attempt", attempt+1)
+			continue
+		}
+
+		data, err := a.parseResponse(resp)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			a.logger.Error("Failed to parse Open-Meteo ensemble response", "error", err.Error(), "attempt", attempt+1)
+			continue
+		}
+
+		a.logger.Info("Successfully fetched ensemble forecast from Open-Meteo", "members", len(data.Members))
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("failed to get ensemble forecast after %d attempts: %w", a.retryAttempts, lastErr)
+}
+
+// parseResponse parses the Open-Meteo ensemble API response
+func (a *OpenMeteoEnsembleAdapter) parseResponse(resp *http.Response) (*domain.EnsembleForecast, error) {
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp OpenMeteoEnsembleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON response: %w", err)
+	}
+
+	return a.buildEnsembleForecast(apiResp)
+}
+
+// buildEnsembleForecast groups the per-member hourly series into ensemble members
+func (a *OpenMeteoEnsembleAdapter) buildEnsembleForecast(apiResp OpenMeteoEnsembleResponse) (*domain.EnsembleForecast, error) {
+	var times []int64 // Unix seconds
+	if err := json.Unmarshal(apiResp.Hourly["time"], &times); err != nil {
+		return nil, fmt.Errorf("failed to decode hourly time: %w", err)
+	}
+
+	// Zoned like the deterministic forecast, so member hours line up with its hours
+	location := forecastLocation(apiResp.Timezone, apiResp.TimezoneAbbreviation, apiResp.UTCOffsetSeconds)
+	hours := make([]time.Time, len(times))
+	for i, t := range times {
+		hours[i] = time.Unix(t, 0).In(location)
+	}
+
+	// member name -> variable -> series
+	series := make(map[string]map[string][]*float64)
+	for key, raw := range apiResp.Hourly {
+		variable, member, ok := a.splitEnsembleKey(key)
+		if !ok {
+			continue
+		}
+		var values []*float64
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+		if series[member] == nil {
+			series[member] = make(map[string][]*float64)
+		}
+		series[member][variable] = values
+	}
+
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	forecast := &domain.EnsembleForecast{}
+	for _, name := range names {
+		member := domain.EnsembleMember{Name: name}
+		vars := series[name]
+		for i := 0; i < len(hours) && i < 168; i++ { // Limit to 168 hours (7 days)
+			// Members of shorter-range models end with nulls; skip those hours
+			ghi, hasGHI := optionalValue(vars[ensembleVarGHI], i)
+			temp, hasTemp := optionalValue(vars[ensembleVarTemperature], i)
+			if !hasGHI || !hasTemp {
+				continue
+			}
+
+			hour := domain.ForecastHour{
+				Hour:                       hours[i],
+				Temperature:                temp,
+				GlobalHorizontalIrradiance: ghi,
+				WindSpeed:                  domain.DefaultWindSpeed,
+			}
+			if cloud, ok := optionalValue(vars[ensembleVarCloudCover], i); ok {
+				hour.CloudCover = int(cloud)
+			}
+			if wind, ok := optionalValue(vars[ensembleVarWindSpeed], i); ok {
+				hour.WindSpeed = wind
+			}
+
+			// Beam/diffuse split - fall back to GHI-only modelling if either is missing
+			dni, hasDNI := optionalValue(vars[ensembleVarDNI], i)
+			dhi, hasDHI := optionalValue(vars[ensembleVarDHI], i)
+			if hasDNI && hasDHI {
+				hour.DirectNormalIrradiance = dni
+				hour.DiffuseHorizontalIrradiance = dhi
+				hour.HasIrradianceComponents = true
+			}
+			if gti, ok := optionalValue(vars[ensembleVarGTI], i); ok {
+				hour.GlobalTiltedIrradiance = gti
+				hour.TiltedPlane = &a.tiltedPlane
+			}
+			member.Hours = append(member.Hours, hour)
+		}
+		if len(member.Hours) > 0 {
+			forecast.Members = append(forecast.Members, member)
+		}
+	}
+
+	if len(forecast.Members) == 0 {
+		return nil, fmt.Errorf("no ensemble members in response")
+	}
+
+	return forecast, nil
+}
+
+// splitEnsembleKey splits an hourly key such as "shortwave_radiation_member07_gfs_seamless"
+// into its variable and member name ("gfs_seamless/member07"). The control run has no
+// member number. A missing model suffix means a single model was requested.
+func (a *OpenMeteoEnsembleAdapter) splitEnsembleKey(key string) (variable, member string, ok bool) {
+	for _, v := range ensembleVariables {
+		if key != v && !strings.HasPrefix(key, v+"_") {
+			continue
+		}
+		rest := strings.TrimPrefix(strings.TrimPrefix(key, v), "_")
+
+		memberID := "control"
+		if strings.HasPrefix(rest, "member") {
+			memberID, rest, _ = strings.Cut(rest, "_")
+		}
+
+		model := rest
+		if model == "" && len(a.models) > 0 {
+			model = a.models[0]
+		}
+		return v, model + "/" + memberID, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,201 @@
+package adapters
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, args ...interface{})  {}
+func (nopLogger) Error(msg string, args ...interface{}) {}
+func (nopLogger) Debug(msg string, args ...interface{}) {}
+func (nopLogger) Warn(msg string, args ...interface{})  {}
+
+func TestBuildForecastDataIrradianceFallback(t *testing.T) {
+	body := `{
+		"timezone": "Europe/Berlin",
+		"utc_offset_seconds": 3600,
+		"timezone_abbreviation": "CET",
+		"hourly": {
+			"time": [1742468400, 1742472000],
+			"temperature_2m": [15.0, 16.0],
+			"cloud_cover": [10, 20],
+			"shortwave_radiation": [600.0, 550.0],
+			"relative_humidity_2m": [50, 55],
+			"precipitation_probability": [0, 5],
+			"direct_normal_irradiance": [700.0, null],
+			"diffuse_radiation": [120.0, 130.0],
+			"global_tilted_irradiance": [750.0, 690.0]
+		}
+	}`
+
+	var resp OpenMeteoResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	adapter := &OpenMeteoAdapter{
+		tiltedPlane: domain.PanelOrientation{TiltDeg: 30, AzimuthDeg: 135},
+		logger:      nopLogger{},
+	}
+
+	forecast, err := adapter.buildForecastData(resp)
+	if err != nil {
+		t.Fatalf("buildForecastData: %v", err)
+	}
+	if len(forecast.Hours) != 2 {
+		t.Fatalf("len(Hours) = %d, want 2", len(forecast.Hours))
+	}
+
+	first, second := forecast.Hours[0], forecast.Hours[1]
+
+	if _, offset := first.Hour.Zone(); offset != 3600 || first.Hour.Hour() != 12 {
+		t.Errorf("first hour = %v, want 12:00 at offset 3600", first.Hour)
+	}
+	if !first.HasIrradianceComponents || first.DirectNormalIrradiance != 700 || first.DiffuseHorizontalIrradiance != 120 {
+		t.Errorf("first hour components = %+v, want DNI 700 / DHI 120", first)
+	}
+	if second.HasIrradianceComponents {
+		t.Errorf("second hour has components, want GHI-only fallback when DNI is null")
+	}
+	if second.TiltedPlane == nil || second.GlobalTiltedIrradiance != 690 {
+		t.Errorf("second hour GTI = %.0f (plane %v), want 690 on the requested plane",
+			second.GlobalTiltedIrradiance, second.TiltedPlane)
+	}
+}
+
+func TestBuildForecastDataAcrossDSTChange(t *testing.T) {
+	// Europe/Berlin moves from CET to CEST at 01:00 UTC on 2025-03-30; the
+	// response's offset and abbreviation are those of the first hour
+	var resp OpenMeteoResponse
+	resp.Timezone = "Europe/Berlin"
+	resp.UTCOffsetSeconds = 3600
+	resp.TimezoneAbbreviation = "CET"
+	resp.Hourly.Time = []int64{1743292800, 1743296400, 1743300000}
+	resp.Hourly.Temperature2m = []float64{5, 5, 5}
+	resp.Hourly.CloudCover = []int{0, 0, 0}
+	resp.Hourly.ShortwaveRadiation = []float64{0, 0, 0}
+	resp.Hourly.RelativeHumidity2m = []int{80, 80, 80}
+	resp.Hourly.PrecipitationProbability = []int{0, 0, 0}
+
+	adapter := &OpenMeteoAdapter{logger: nopLogger{}}
+	forecast, err := adapter.buildForecastData(resp)
+	if err != nil {
+		t.Fatalf("buildForecastData: %v", err)
+	}
+
+	wantClock := []int{1, 3, 4}
+	wantOffset := []int{3600, 7200, 7200}
+	for i, hour := range forecast.Hours {
+		_, offset := hour.Hour.Zone()
+		if hour.Hour.Hour() != wantClock[i] || offset != wantOffset[i] || hour.Hour.Unix() != resp.Hourly.Time[i] {
+			t.Errorf("hour %d = %v, want %02d:00 at offset %d", i, hour.Hour, wantClock[i], wantOffset[i])
+		}
+	}
+}
+
+func TestBuildForecastDataWithoutOptionalSeries(t *testing.T) {
+	var resp OpenMeteoResponse
+	resp.Hourly.Time = []int64{1742468400}
+	resp.Hourly.Temperature2m = []float64{15}
+	resp.Hourly.CloudCover = []int{10}
+	resp.Hourly.ShortwaveRadiation = []float64{600}
+	resp.Hourly.RelativeHumidity2m = []int{50}
+	resp.Hourly.PrecipitationProbability = []int{0}
+
+	adapter := &OpenMeteoAdapter{logger: nopLogger{}}
+
+	forecast, err := adapter.buildForecastData(resp)
+	if err != nil {
+		t.Fatalf("buildForecastData: %v", err)
+	}
+
+	hour := forecast.Hours[0]
+	if hour.HasIrradianceComponents || hour.TiltedPlane != nil {
+		t.Errorf("hour = %+v, want GHI-only data when optional series are absent", hour)
+	}
+}
+
+func TestBuildEnsembleForecast(t *testing.T) {
+	// The second hour is the first after Europe/Berlin moves to summer time
+	body := `{
+		"timezone": "Europe/Berlin",
+		"utc_offset_seconds": 3600,
+		"timezone_abbreviation": "CET",
+		"hourly": {
+			"time": [1743292800, 1743296400],
+			"temperature_2m_icon_seamless": [15.0, 16.0],
+			"shortwave_radiation_icon_seamless": [600.0, 550.0],
+			"temperature_2m_member01_icon_seamless": [14.0, 15.0],
+			"shortwave_radiation_member01_icon_seamless": [300.0, 250.0],
+			"direct_normal_irradiance_member01_icon_seamless": [150.0, null],
+			"diffuse_radiation_member01_icon_seamless": [200.0, 180.0],
+			"global_tilted_irradiance_member01_icon_seamless": [340.0, 280.0],
+			"cloud_cover_member01_icon_seamless": [80, 90],
+			"temperature_2m_member01_gfs_seamless": [13.0, null],
+			"shortwave_radiation_member01_gfs_seamless": [400.0, null],
+			"wind_speed_10m_member01_gfs_seamless": [4.5, null],
+			"relative_humidity_2m": [50, 55]
+		}
+	}`
+
+	var resp OpenMeteoEnsembleResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	adapter := &OpenMeteoEnsembleAdapter{
+		models:      []string{"icon_seamless", "gfs_seamless"},
+		tiltedPlane: domain.PanelOrientation{TiltDeg: 35, AzimuthDeg: 180},
+		logger:      nopLogger{},
+	}
+	forecast, err := adapter.buildEnsembleForecast(resp)
+	if err != nil {
+		t.Fatalf("buildEnsembleForecast: %v", err)
+	}
+
+	if len(forecast.Members) != 3 {
+		t.Fatalf("members = %d, want 3", len(forecast.Members))
+	}
+
+	byName := make(map[string]domain.EnsembleMember)
+	for _, m := range forecast.Members {
+		byName[m.Name] = m
+	}
+
+	control, ok := byName["icon_seamless/control"]
+	if !ok || len(control.Hours) != 2 || control.Hours[0].GlobalHorizontalIrradiance != 600 {
+		t.Errorf("icon control member = %+v", control)
+	}
+	if _, offset := control.Hours[1].Hour.Zone(); offset != 7200 || control.Hours[1].Hour.Hour() != 3 {
+		t.Errorf("hour after the DST change = %v, want 03:00 at offset 7200", control.Hours[1].Hour)
+	}
+
+	icon := byName["icon_seamless/member01"]
+	if len(icon.Hours) != 2 || icon.Hours[1].CloudCover != 90 || icon.Hours[0].WindSpeed != domain.DefaultWindSpeed {
+		t.Errorf("icon member01 = %+v", icon)
+	}
+
+	// Members carry the same irradiance components as the deterministic forecast;
+	// an hour missing either of DNI and DHI falls back to GHI-only modelling
+	if first := icon.Hours[0]; !first.HasIrradianceComponents || first.DirectNormalIrradiance != 150 ||
+		first.DiffuseHorizontalIrradiance != 200 || first.GlobalTiltedIrradiance != 340 ||
+		first.TiltedPlane == nil || first.TiltedPlane.TiltDeg != 35 {
+		t.Errorf("icon member01 first hour = %+v, want its irradiance components", first)
+	}
+	if second := icon.Hours[1]; second.HasIrradianceComponents || second.TiltedPlane == nil {
+		t.Errorf("icon member01 second hour = %+v, want GHI-only components with the tilted irradiance", second)
+	}
+
+	// The GFS member ends early; null hours are dropped
+	gfs := byName["gfs_seamless/member01"]
+	if len(gfs.Hours) != 1 || gfs.Hours[0].WindSpeed != 4.5 {
+		t.Errorf("gfs member01 = %+v", gfs)
+	}
+	if _, offset := gfs.Hours[0].Hour.Zone(); offset != 3600 {
+		t.Errorf("zone offset = %d, want 3600", offset)
+	}
+}
@@ -0,0 +1,59 @@
+package adapters
+
+import (
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// pushMessages renders the push title, message and chart image. The push
+// adapters embed it so they all share the push templates.
+type pushMessages struct {
+	templates *Templates
+	settings  TemplateSettings // Configuration values exposed to the templates
+	chart     chartOptions
+	logger    domain.Logger
+}
+
+// newPushMessages creates the push renderer for an adapter
+func newPushMessages(config *domain.Config, templates *Templates, logger domain.Logger) pushMessages {
+	return pushMessages{
+		templates: templates,
+		settings:  NewTemplateSettings(config),
+		chart: chartOptions{
+			displayHours:           config.ChartDisplayHours,
+			daylightGHIThreshold:   config.DaylightGHIThreshold,
+			nightCompressionFactor: config.NightCompressionFactor,
+			inverterACLimitKW:      config.InverterACLimitKW,
+		},
+		logger: logger,
+	}
+}
+
+// AlertMessage renders the push title and message for an alert or alert update
+func (m *pushMessages) AlertMessage(analysis *domain.AlertAnalysis) (string, string, error) {
+	return m.renderMessage("push_alert", NewAlertTemplateData(analysis, m.settings, m.templates.Locale()))
+}
+
+// RecoveryMessage renders the push title and message for a recovery
+func (m *pushMessages) RecoveryMessage(summary *domain.RecoverySummary) (string, string, error) {
+	return m.renderMessage("push_recovery", NewRecoveryTemplateData(summary, m.settings))
+}
+
+// renderMessage renders the "<kind>_title" and "<kind>_message" templates
+func (m *pushMessages) renderMessage(kind string, data any) (string, string, error) {
+	title, err := m.templates.Text(kind+"_title", data)
+	if err != nil {
+		return "", "", err
+	}
+	message, err := m.templates.Text(kind+"_message", data)
+	if err != nil {
+		return "", "", err
+	}
+	return title, message, nil
+}
+
+// GenerateChartImage creates a PNG image of the production and cloud coverage chart
+func (m *pushMessages) GenerateChartImage(production []domain.SolarProduction) ([]byte, error) {
+	opts := m.chart
+	opts.locale = m.templates.Locale()
+	return renderChartPNG(production, opts, m.logger)
+}
@@ -10,6 +10,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/b0d/solar-forecast/internal/domain"
@@ -18,24 +19,88 @@ import (
 
 // PushoverAdapter implements PushNotifier using Pushover API
 type PushoverAdapter struct {
+	pushMessages
 	userKey                string
 	apiToken               string
-	logger                 domain.Logger
-	daylightGHIThreshold   float64
-	nightCompressionFactor float64 // Compression factor for nighttime hours in charts
-	chartDisplayHours      int     // Hours to display in charts
+	emergencyRetrySeconds  int // How often Pushover repeats an unacknowledged critical alert
+	emergencyExpireSeconds int // When Pushover stops repeating a critical alert
 }
 
 // NewPushoverAdapter creates a new Pushover adapter
-func NewPushoverAdapter(config *domain.Config, logger domain.Logger) *PushoverAdapter {
+func NewPushoverAdapter(config *domain.Config, templates *Templates, logger domain.Logger) *PushoverAdapter {
 	return &PushoverAdapter{
+		pushMessages:           newPushMessages(config, templates, logger),
 		userKey:                config.PushoverUserKey,
 		apiToken:               config.PushoverAPIToken,
-		logger:                 logger,
-		daylightGHIThreshold:   config.DaylightGHIThreshold,
-		nightCompressionFactor: config.NightCompressionFactor,
-		chartDisplayHours:      config.ChartDisplayHours,
+		emergencyRetrySeconds:  config.PushoverEmergencyRetrySeconds,
+		emergencyExpireSeconds: config.PushoverEmergencyExpireSeconds,
+	}
+}
+
+// pushoverPriority maps an alert severity to a Pushover priority:
+// info is normal (0), warning is high (1) and critical is emergency (2)
+func pushoverPriority(severity domain.Severity) int {
+	switch severity {
+	case domain.SeverityInfo:
+		return 0
+	case domain.SeverityCritical:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// arrayLineColors distinguishes per-array production lines in charts
+var arrayLineColors = []color.RGBA{
+	{22, 160, 133, 255}, // Teal
+	{39, 174, 96, 255},  // Green
+	{160, 82, 45, 255},  // Brown
+	{52, 73, 94, 255},   // Slate
+	{232, 67, 147, 255}, // Pink
+}
+
+// arrayLineColor returns the chart color for the i-th array
+func arrayLineColor(i int) color.RGBA {
+	return arrayLineColors[i%len(arrayLineColors)]
+}
+
+// chartArrayNames returns the array names to chart individually.
+// A single array is already represented by the total production line.
+func chartArrayNames(production []domain.SolarProduction) []string {
+	if len(production) == 0 || len(production[0].Arrays) < 2 {
+		return nil
+	}
+	names := make([]string, len(production[0].Arrays))
+	for i, array := range production[0].Arrays {
+		names[i] = array.Name
+	}
+	return names
+}
+
+// clippingMarkerColor marks hours capped by the inverter AC limit in charts
+var clippingMarkerColor = color.RGBA{44, 62, 80, 220}
+
+// hasClippedHours reports whether any hour in the chart range was clipped
+func hasClippedHours(production []domain.SolarProduction) bool {
+	for _, prod := range production {
+		if prod.Clipped {
+			return true
+		}
+	}
+	return false
+}
+
+// uncertaintyBandColor fills the ensemble P10-P90 production band in charts
+var uncertaintyBandColor = color.RGBA{247, 147, 30, 60}
+
+// hasQuantiles reports whether any hour in the chart range has ensemble quantiles
+func hasQuantiles(production []domain.SolarProduction) bool {
+	for _, prod := range production {
+		if prod.Quantiles != nil {
+			return true
+		}
 	}
+	return false
 }
 
 // calculateSmartSpacingPNG calculates non-uniform X positions that compress nighttime hours for PNG charts
@@ -69,17 +134,28 @@ func calculateSmartSpacingPNG(production []domain.SolarProduction, totalWidth fl
 	return xPositions
 }
 
-// GenerateChartImage creates a PNG image of the production and cloud coverage chart
-func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction) ([]byte, error) {
-	// Sort and filter to next N hours from now
+// chartOptions are the display settings shared by the PNG chart users
+type chartOptions struct {
+	displayHours           int
+	daylightGHIThreshold   float64
+	nightCompressionFactor float64
+	inverterACLimitKW      float64
+	locale                 *domain.Locale // Language of the title, legend and day labels
+}
+
+// renderChartPNG draws the production and cloud coverage chart for the push
+// notification and the inline email image
+func renderChartPNG(production []domain.SolarProduction, opts chartOptions, logger domain.Logger) ([]byte, error) {
+	// Sort a copy, as concurrent channels share the analysis, and filter to next N hours from now
+	production = append([]domain.SolarProduction(nil), production...)
 	sort.Slice(production, func(i, j int) bool {
 		return production[i].Hour.Before(production[j].Hour)
 	})
-	production = filterFromNow(production, p.chartDisplayHours)
+	production = filterFromNow(production, opts.displayHours)
 
 	// Debug: log time range
 	if len(production) > 0 {
-		p.logger.Debug("Pushover chart time range",
+		logger.Debug("PNG chart time range",
 			"now", time.Now().Format("2006-01-02 15:04:05"),
 			"first_hour", production[0].Hour.Format("2006-01-02 15:04:05"),
 			"last_hour", production[len(production)-1].Hour.Format("2006-01-02 15:04:05"),
@@ -108,6 +184,9 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 		if prod.EstimatedOutputKW > maxProduction {
 			maxProduction = prod.EstimatedOutputKW
 		}
+		if prod.Quantiles != nil && prod.Quantiles.P90 > maxProduction {
+			maxProduction = prod.Quantiles.P90
+		}
 	}
 	maxProduction = float64(int(maxProduction) + 1)
 	if maxProduction < domain.MinChartProductionScale {
@@ -132,7 +211,7 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 	for i := 0; i <= 4; i++ {
 		y := float64(padding) + (float64(i) / 4.0) * float64(chartHeight)
 		value := maxProduction - (float64(i)/4.0)*maxProduction
-		dc.DrawStringAnchored(fmt.Sprintf("%.1f kW", value), float64(padding-10), y, 1, 0.5)
+		dc.DrawStringAnchored(opts.locale.Sprintf("%.1f kW", value), float64(padding-10), y, 1, 0.5)
 	}
 
 	// Y-axis labels (cloud coverage - right side)
@@ -145,7 +224,34 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 
 	// Calculate smart point spacing (compress nighttime hours)
 	totalChartWidth := float64(chartWidth - padding)
-	xPositions := calculateSmartSpacingPNG(production, totalChartWidth, p.daylightGHIThreshold, p.nightCompressionFactor)
+	xPositions := calculateSmartSpacingPNG(production, totalChartWidth, opts.daylightGHIThreshold, opts.nightCompressionFactor)
+
+	// Draw ensemble uncertainty band (P10-P90 filled, P50 dashed) behind the production line.
+	// Hours without quantiles collapse the band onto the deterministic estimate.
+	showBand := hasQuantiles(production)
+	if showBand {
+		bandY := func(prod domain.SolarProduction, q domain.AlertQuantile) float64 {
+			return float64(padding+chartHeight) - (prod.OutputAt(q)/maxProduction)*float64(chartHeight)
+		}
+		dc.SetColor(uncertaintyBandColor)
+		for i, prod := range production {
+			dc.LineTo(float64(padding)+xPositions[i], bandY(prod, domain.AlertOnP90))
+		}
+		for i := len(production) - 1; i >= 0; i-- {
+			dc.LineTo(float64(padding)+xPositions[i], bandY(production[i], domain.AlertOnP10))
+		}
+		dc.ClosePath()
+		dc.Fill()
+
+		dc.SetColor(color.RGBA{247, 147, 30, 200})
+		dc.SetLineWidth(2)
+		dc.SetDash(4, 4)
+		for i, prod := range production {
+			dc.LineTo(float64(padding)+xPositions[i], bandY(prod, domain.AlertOnP50))
+		}
+		dc.Stroke()
+		dc.SetDash()
+	}
 
 	// Draw production line (orange)
 	dc.SetColor(color.RGBA{247, 147, 30, 255})
@@ -165,6 +271,27 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 	}
 	dc.Stroke()
 
+	// Draw per-array contribution lines (thin, one color per array)
+	arrayNames := chartArrayNames(production)
+	dc.SetLineWidth(2)
+	for a := range arrayNames {
+		dc.SetColor(arrayLineColor(a))
+		for i, prod := range production {
+			x := float64(padding) + xPositions[i]
+			kw := 0.0
+			if a < len(prod.Arrays) {
+				kw = prod.Arrays[a].OutputKW
+			}
+			y := float64(padding+chartHeight) - (kw/maxProduction)*float64(chartHeight)
+			if i == 0 {
+				dc.MoveTo(x, y)
+			} else {
+				dc.LineTo(x, y)
+			}
+		}
+		dc.Stroke()
+	}
+
 	// Draw cloud coverage line (blue, dashed)
 	dc.SetColor(color.RGBA{52, 152, 219, 180})
 	dc.SetLineWidth(3)
@@ -219,7 +346,7 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 	// Add data point labels for production (daylight hours only)
 	for i, prod := range production {
 		// Skip dots and labels for nighttime hours
-		if prod.GHI < p.daylightGHIThreshold {
+		if prod.GHI < opts.daylightGHIThreshold {
 			continue
 		}
 
@@ -258,14 +385,38 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 
 		// Draw value label for daylight hours
 		dc.SetColor(color.RGBA{247, 147, 30, 255})
-		dc.DrawStringAnchored(fmt.Sprintf("%.1f", kw), x, y-10, 0.5, 1)
+		dc.DrawStringAnchored(opts.locale.Sprintf("%.1f", kw), x, y-10, 0.5, 1)
+	}
+
+	// Mark clipped hours: dashed line at the inverter limit and a ring around each clipped point
+	if opts.inverterACLimitKW > 0 && hasClippedHours(production) {
+		limitY := float64(padding+chartHeight) - (opts.inverterACLimitKW/maxProduction)*float64(chartHeight)
+		dc.SetColor(clippingMarkerColor)
+		dc.SetLineWidth(1.5)
+		dc.SetDash(6, 4)
+		dc.DrawLine(float64(padding), limitY, float64(chartWidth), limitY)
+		dc.Stroke()
+		dc.SetDash()
+		dc.DrawStringAnchored(opts.locale.Sprintf("Inverter limit %.1f kW (clipped ◯)", opts.inverterACLimitKW), float64(chartWidth-5), limitY-8, 1, 0.5)
+
+		dc.SetLineWidth(2)
+		for i, prod := range production {
+			if !prod.Clipped {
+				continue
+			}
+			x := float64(padding) + xPositions[i]
+			y := float64(padding+chartHeight) - (prod.EstimatedOutputKW/maxProduction)*float64(chartHeight)
+			dc.DrawCircle(x, y, 9)
+			dc.Stroke()
+		}
+		dc.SetLineWidth(4) // Reset
 	}
 
 	// Add data point labels for cloud coverage (daylight hours only)
 	dc.SetColor(color.RGBA{52, 152, 219, 255})
 	for i, prod := range production {
 		// Skip dots and labels for nighttime hours
-		if prod.GHI < p.daylightGHIThreshold {
+		if prod.GHI < opts.daylightGHIThreshold {
 			continue
 		}
 
@@ -285,7 +436,7 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 	dc.SetColor(color.RGBA{155, 89, 182, 255})
 	for i, prod := range production {
 		// Skip dots and labels for nighttime hours
-		if prod.GHI < p.daylightGHIThreshold {
+		if prod.GHI < opts.daylightGHIThreshold {
 			continue
 		}
 
@@ -313,7 +464,7 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 			dc.DrawLine(x, float64(padding), x, float64(padding+chartHeight))
 			dc.Stroke()
 			// Add day label
-			dayLabel := production[i].Hour.Format("Jan 2")
+			dayLabel := opts.locale.FormatTime(production[i].Hour, "Jan 2")
 			dc.DrawStringAnchored(dayLabel, x, float64(padding-10), 0.5, 0.5)
 		}
 	}
@@ -324,7 +475,7 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 	dc.SetColor(color.RGBA{44, 62, 80, 255})
 	for i, prod := range production {
 		// Only show time labels during daylight hours
-		if prod.GHI >= p.daylightGHIThreshold {
+		if prod.GHI >= opts.daylightGHIThreshold {
 			x := float64(padding) + xPositions[i]
 			// Format time as just hour (e.g., "14" instead of "14:00")
 			timeStr := prod.Hour.Format("15")
@@ -334,15 +485,23 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 
 	// Title
 	dc.SetColor(color.RGBA{44, 62, 80, 255})
-	dc.DrawStringAnchored("Solar Production & Cloud Coverage (Next 48h)", float64(width/2), 25, 0.5, 0.5)
+	dc.DrawStringAnchored(opts.locale.T("Solar Production & Cloud Coverage (Next 48h)"), float64(width/2), 25, 0.5, 0.5)
 
 	// Legend
 	dc.SetColor(color.RGBA{247, 147, 30, 255})
-	dc.DrawStringAnchored("● Production (kW)", float64(padding+60), float64(padding-15), 0.5, 0.5)
+	dc.DrawStringAnchored("● "+opts.locale.T("Production (kW)"), float64(padding+60), float64(padding-15), 0.5, 0.5)
 	dc.SetColor(color.RGBA{52, 152, 219, 255})
-	dc.DrawStringAnchored("● Cloud Coverage (%)", float64(padding+220), float64(padding-15), 0.5, 0.5)
+	dc.DrawStringAnchored("● "+opts.locale.T("Cloud Coverage (%)"), float64(padding+220), float64(padding-15), 0.5, 0.5)
 	dc.SetColor(color.RGBA{155, 89, 182, 255})
-	dc.DrawStringAnchored("● Rain Chance (%)", float64(padding+400), float64(padding-15), 0.5, 0.5)
+	dc.DrawStringAnchored("● "+opts.locale.T("Rain Chance (%)"), float64(padding+400), float64(padding-15), 0.5, 0.5)
+	if showBand {
+		dc.SetColor(color.RGBA{247, 147, 30, 255})
+		dc.DrawStringAnchored("▒ P10–P90 / - - P50", float64(padding+580), float64(padding-15), 0.5, 0.5)
+	}
+	for a, name := range arrayNames {
+		dc.SetColor(arrayLineColor(a))
+		dc.DrawStringAnchored("— "+name+" (kW)", float64(padding+a*140), float64(height-10), 0, 0.5)
+	}
 
 	// Encode to PNG
 	var buf bytes.Buffer
@@ -354,7 +513,7 @@ func (p *PushoverAdapter) GenerateChartImage(production []domain.SolarProduction
 }
 
 // SendNotification sends a push notification with chart image via Pushover API
-func (p *PushoverAdapter) SendNotification(ctx context.Context, title, message string, imageData []byte) error {
+func (p *PushoverAdapter) SendNotification(ctx context.Context, title, message string, imageData []byte, severity domain.Severity) error {
 	// Check if Pushover is configured
 	if p.userKey == "" || p.apiToken == "" ||
 		p.userKey == "YOUR_PUSHOVER_USER_KEY" || p.apiToken == "YOUR_PUSHOVER_API_TOKEN" {
@@ -371,7 +530,13 @@ func (p *PushoverAdapter) SendNotification(ctx context.Context, title, message s
 	writer.WriteField("user", p.userKey)
 	writer.WriteField("title", title)
 	writer.WriteField("message", message)
-	writer.WriteField("priority", "1")
+	priority := pushoverPriority(severity)
+	writer.WriteField("priority", strconv.Itoa(priority))
+	if priority == 2 {
+		// Emergency priority repeats until acknowledged or expired
+		writer.WriteField("retry", strconv.Itoa(p.emergencyRetrySeconds))
+		writer.WriteField("expire", strconv.Itoa(p.emergencyExpireSeconds))
+	}
 	writer.WriteField("sound", "solar")
 
 	// Add image attachment if provided
@@ -428,6 +593,7 @@ func (p *PushoverAdapter) SendNotification(ctx context.Context, title, message s
 
 	p.logger.Info("Push notification sent successfully",
 		"title", title,
+		"priority", priority,
 		"has_image", len(imageData) > 0,
 		"image_size_kb", len(imageData)/1024)
 	return nil
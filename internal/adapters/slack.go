@@ -0,0 +1,95 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// SlackAdapter implements PushNotifier by posting Block Kit messages to a
+// Slack incoming webhook
+type SlackAdapter struct {
+	pushMessages
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackAdapter creates a new Slack adapter
+func NewSlackAdapter(config *domain.Config, templates *Templates, logger domain.Logger) *SlackAdapter {
+	return &SlackAdapter{
+		pushMessages: newPushMessages(config, templates, logger),
+		webhookURL:   config.SlackWebhookURL,
+		client:       &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// slackBlock is a Block Kit layout block
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+// slackText is a Block Kit text object
+type slackText struct {
+	Type string `json:"type"` // plain_text or mrkdwn
+	Text string `json:"text"`
+}
+
+// SendNotification posts the title and message. Incoming webhooks cannot
+// upload files, so the chart image is not sent.
+func (s *SlackAdapter) SendNotification(ctx context.Context, title, message string, imageData []byte, severity domain.Severity) error {
+	return s.send(ctx, title, slackMessageBlocks(title, message))
+}
+
+// SendAlertNotification posts the alert with its key fields
+func (s *SlackAdapter) SendAlertNotification(ctx context.Context, title, message string, analysis *domain.AlertAnalysis, imageData []byte) error {
+	blocks := slackMessageBlocks(title, message)
+	var fields []slackText
+	for _, fact := range alertFacts(analysis, s.settings, s.templates.Locale()) {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: "*" + slackEscape(fact.Label) + "*\n" + slackEscape(fact.Value)})
+	}
+	blocks = append(blocks, slackBlock{Type: "section", Fields: fields})
+	return s.send(ctx, title, blocks)
+}
+
+// slackMessageBlocks lays out the title as a header and the message below it
+func slackMessageBlocks(title, message string) []slackBlock {
+	blocks := []slackBlock{{Type: "header", Text: &slackText{Type: "plain_text", Text: truncateRunes(title, 150)}}}
+	if message != "" {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: truncateRunes(slackEscape(message), 3000)}})
+	}
+	return blocks
+}
+
+// send posts the blocks, with the title as the notification text
+func (s *SlackAdapter) send(ctx context.Context, title string, blocks []slackBlock) error {
+	if s.webhookURL == "" {
+		s.logger.Debug("Slack not configured, skipping notification")
+		return nil // Not an error, just not configured
+	}
+
+	body, err := json.Marshal(map[string]any{"text": title, "blocks": blocks})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+	if err := postWebhook(ctx, s.client, "slack", s.webhookURL, "application/json", body); err != nil {
+		s.logger.Error("Failed to send Slack notification", "error", err.Error())
+		return err
+	}
+
+	s.logger.Info("Slack notification sent successfully", "title", title)
+	return nil
+}
+
+// slackEscape escapes the characters Slack's mrkdwn treats as control characters
+func slackEscape(text string) string {
+	return slackEscaper.Replace(text)
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
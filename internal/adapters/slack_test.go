@@ -0,0 +1,61 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+func TestSlackSendAlertNotification(t *testing.T) {
+	var got struct {
+		Text   string       `json:"text"`
+		Blocks []slackBlock `json:"blocks"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	adapter := NewSlackAdapter(&domain.Config{SlackWebhookURL: server.URL}, nil, nopLogger{})
+	err := adapter.SendAlertNotification(context.Background(), "🚨 Solar Production Critical", "Production < 2 kW", newChatTestAnalysis(), []byte("png"))
+	if err != nil {
+		t.Fatalf("SendAlertNotification: %v", err)
+	}
+
+	if got.Text != "🚨 Solar Production Critical" || len(got.Blocks) != 3 {
+		t.Fatalf("message = %+v, want a header, the message and the fields", got)
+	}
+	if got.Blocks[0].Type != "header" || got.Blocks[1].Text.Text != "Production &lt; 2 kW" {
+		t.Errorf("blocks = %+v, want the title header and the escaped message", got.Blocks[:2])
+	}
+	var fields []string
+	for _, field := range got.Blocks[2].Fields {
+		fields = append(fields, field.Text)
+	}
+	if want := "*Low window*\nMon 09:00–14:00|*Consecutive hours*\n6|*Minimum output*\n0.4 kW|*Expected Recovery*\nMon Mar 2, 15:00"; strings.Join(fields, "|") != want {
+		t.Errorf("fields = %q", fields)
+	}
+}
+
+func TestSlackWebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "no_service")
+	}))
+	defer server.Close()
+
+	adapter := NewSlackAdapter(&domain.Config{SlackWebhookURL: server.URL}, nil, nopLogger{})
+	err := adapter.SendNotification(context.Background(), "Title", "Message", nil, domain.SeverityInfo)
+	if err == nil || !strings.Contains(err.Error(), "no_service") {
+		t.Errorf("err = %v, want the webhook's error", err)
+	}
+}
@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// smtpTimeout bounds a delivery when the context has no deadline
+const smtpTimeout = 30 * time.Second
+
+// SMTPClient delivers messages through any SMTP server
+type SMTPClient struct {
+	config domain.SMTPConfig
+	logger domain.Logger
+}
+
+// NewSMTPClient creates a client for the configured mail server
+func NewSMTPClient(config domain.SMTPConfig, logger domain.Logger) *SMTPClient {
+	return &SMTPClient{
+		config: config,
+		logger: logger,
+	}
+}
+
+// Send delivers a complete MIME message from the sender to each recipient
+func (c *SMTPClient) Send(ctx context.Context, from string, to []string, msg []byte) error {
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port))
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	if c.config.TLSMode == domain.SMTPTLSImplicit {
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return fmt.Errorf("TLS handshake with %s failed: %w", addr, err)
+		}
+		conn = tlsConn
+	}
+
+	client, err := smtp.NewClient(conn, c.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session with %s: %w", addr, err)
+	}
+	defer client.Close()
+
+	if c.config.TLSMode == domain.SMTPTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not support STARTTLS", addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS with %s failed: %w", addr, err)
+		}
+	}
+
+	if auth := c.auth(); auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("%s does not support authentication", addr)
+		}
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP %s authentication failed: %w", c.config.Auth, err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("MAIL FROM rejected: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("RCPT TO %s rejected: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA rejected: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+
+	c.logger.Debug("SMTP delivery complete", "server", addr, "tls", string(c.config.TLSMode), "recipients", len(to))
+	return client.Quit()
+}
+
+// tlsConfig verifies the server against the custom CA bundle when one is
+// configured, otherwise against the system roots
+func (c *SMTPClient) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{ServerName: c.config.Host, MinVersion: tls.VersionTLS12}
+	if c.config.CAFile == "" {
+		return config, nil
+	}
+
+	pem, err := os.ReadFile(c.config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SMTP CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in SMTP CA file %s", c.config.CAFile)
+	}
+	config.RootCAs = pool
+	return config, nil
+}
+
+// auth returns the configured authentication mechanism, or nil for none
+func (c *SMTPClient) auth() smtp.Auth {
+	switch c.config.Auth {
+	case domain.SMTPAuthPlain:
+		return smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.Host)
+	case domain.SMTPAuthLogin:
+		return &loginAuth{username: c.config.Username, password: c.config.Password}
+	case domain.SMTPAuthCRAMMD5:
+		return smtp.CRAMMD5Auth(c.config.Username, c.config.Password)
+	default:
+		return nil
+	}
+}
+
+// loginAuth implements the LOGIN mechanism, which net/smtp does not provide.
+// Like PLAIN it sends the password as is, so it is refused without TLS except
+// to localhost.
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !domain.IsLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	prompt := strings.ToLower(strings.TrimSpace(string(fromServer)))
+	switch {
+	case strings.HasPrefix(prompt, "user"):
+		return []byte(a.username), nil
+	case strings.HasPrefix(prompt, "pass"):
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN challenge %q", fromServer)
+	}
+}
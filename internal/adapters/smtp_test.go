@@ -0,0 +1,338 @@
+package adapters
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// smtpStandIn is a minimal SMTP server for exercising SMTPClient. It supports
+// STARTTLS or implicit TLS, AUTH PLAIN, LOGIN and CRAM-MD5, and records the
+// messages it accepts.
+type smtpStandIn struct {
+	listener    net.Listener
+	tlsConfig   *tls.Config
+	implicitTLS bool
+	mechanism   string // Advertised AUTH mechanism, empty for none
+	username    string
+	password    string
+
+	mu       sync.Mutex
+	messages []standInMessage
+}
+
+type standInMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+func startSMTPStandIn(t *testing.T, cert tls.Certificate, implicitTLS bool, mechanism string) *smtpStandIn {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpStandIn{
+		listener:    listener,
+		tlsConfig:   &tls.Config{Certificates: []tls.Certificate{cert}},
+		implicitTLS: implicitTLS,
+		mechanism:   mechanism,
+		username:    "solar@example.com",
+		password:    "secret",
+	}
+	if implicitTLS {
+		s.listener = tls.NewListener(listener, s.tlsConfig)
+	}
+	t.Cleanup(func() { s.listener.Close() })
+
+	go func() {
+		for {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStandIn) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+	text := textproto.NewConn(conn)
+	_, secure := conn.(*tls.Conn)
+	authenticated := s.mechanism == ""
+	var msg standInMessage
+
+	text.PrintfLine("220 stand-in ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			lines := []string{"stand-in"}
+			if !secure && !s.implicitTLS {
+				lines = append(lines, "STARTTLS")
+			}
+			if s.mechanism != "" {
+				lines = append(lines, "AUTH "+s.mechanism)
+			}
+			for i, l := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				text.PrintfLine("250%s%s", sep, l)
+			}
+		case "STARTTLS":
+			text.PrintfLine("220 ready")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, secure = tlsConn, true
+			text = textproto.NewConn(conn)
+		case "AUTH":
+			if s.authenticate(text, arg) {
+				authenticated = true
+				text.PrintfLine("235 authenticated")
+			} else {
+				text.PrintfLine("535 bad credentials")
+			}
+		case "MAIL":
+			if !authenticated {
+				text.PrintfLine("530 authentication required")
+				continue
+			}
+			msg = standInMessage{from: strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")}
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			msg.to = append(msg.to, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.data = string(data)
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func (s *smtpStandIn) authenticate(text *textproto.Conn, arg string) bool {
+	mechanism, initial, _ := strings.Cut(arg, " ")
+	if !strings.EqualFold(mechanism, s.mechanism) {
+		return false
+	}
+	challenge := func(prompt string) string {
+		text.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte(prompt)))
+		line, _ := text.ReadLine()
+		decoded, _ := base64.StdEncoding.DecodeString(line)
+		return string(decoded)
+	}
+
+	switch strings.ToUpper(mechanism) {
+	case "PLAIN":
+		decoded, _ := base64.StdEncoding.DecodeString(initial)
+		return string(decoded) == "\x00"+s.username+"\x00"+s.password
+	case "LOGIN":
+		return challenge("Username:") == s.username && challenge("Password:") == s.password
+	case "CRAM-MD5":
+		nonce := "<1896.697170952@stand-in>"
+		user, digest, _ := strings.Cut(challenge(nonce), " ")
+		mac := hmac.New(md5.New, []byte(s.password))
+		mac.Write([]byte(nonce))
+		return user == s.username && digest == hex.EncodeToString(mac.Sum(nil))
+	}
+	return false
+}
+
+// standInCertificate creates a self-signed certificate for 127.0.0.1 and writes
+// it as a PEM CA bundle
+func standInCertificate(t *testing.T) (tls.Certificate, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "stand-in"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
+func TestSMTPClientSend(t *testing.T) {
+	cert, caFile := standInCertificate(t)
+
+	tests := []struct {
+		name        string
+		implicitTLS bool
+		mechanism   string
+		config      domain.SMTPConfig
+		wantErr     string
+	}{
+		{
+			name:   "plain relay without TLS or auth",
+			config: domain.SMTPConfig{TLSMode: domain.SMTPTLSNone, Auth: domain.SMTPAuthNone},
+		},
+		{
+			name:      "STARTTLS with PLAIN",
+			mechanism: "PLAIN",
+			config:    domain.SMTPConfig{TLSMode: domain.SMTPTLSStartTLS, Auth: domain.SMTPAuthPlain, CAFile: caFile},
+		},
+		{
+			name:        "implicit TLS with LOGIN",
+			implicitTLS: true,
+			mechanism:   "LOGIN",
+			config:      domain.SMTPConfig{TLSMode: domain.SMTPTLSImplicit, Auth: domain.SMTPAuthLogin, CAFile: caFile},
+		},
+		{
+			name:      "STARTTLS with CRAM-MD5",
+			mechanism: "CRAM-MD5",
+			config:    domain.SMTPConfig{TLSMode: domain.SMTPTLSStartTLS, Auth: domain.SMTPAuthCRAMMD5, CAFile: caFile},
+		},
+		{
+			name:      "server certificate not trusted without the CA file",
+			mechanism: "PLAIN",
+			config:    domain.SMTPConfig{TLSMode: domain.SMTPTLSStartTLS, Auth: domain.SMTPAuthPlain},
+			wantErr:   "STARTTLS",
+		},
+		{
+			name:      "wrong password",
+			mechanism: "LOGIN",
+			config:    domain.SMTPConfig{TLSMode: domain.SMTPTLSStartTLS, Auth: domain.SMTPAuthLogin, CAFile: caFile, Password: "wrong"},
+			wantErr:   "authentication failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startSMTPStandIn(t, cert, tt.implicitTLS, tt.mechanism)
+			config := tt.config
+			config.Host = "127.0.0.1"
+			config.Port = server.port()
+			if config.Auth != domain.SMTPAuthNone {
+				config.Username = server.username
+				if config.Password == "" {
+					config.Password = server.password
+				}
+			}
+
+			client := NewSMTPClient(config, nopLogger{})
+			msg := []byte("Subject: test\r\n\r\nhello\r\n")
+			err := client.Send(context.Background(), "solar@example.com", []string{"a@example.com", "b@example.com"}, msg)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Send error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if len(server.messages) != 1 {
+				t.Fatalf("server received %d messages, want 1", len(server.messages))
+			}
+			got := server.messages[0]
+			if got.from != "solar@example.com" || len(got.to) != 2 || !strings.Contains(got.data, "hello") {
+				t.Errorf("received message = %+v", got)
+			}
+		})
+	}
+}
+
+func TestSMTPClientRequiresSTARTTLS(t *testing.T) {
+	// A server that never offers STARTTLS must not receive credentials in clear text
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		fmt.Fprint(conn, "220 stand-in\r\n")
+		r.ReadString('\n')
+		fmt.Fprint(conn, "250 stand-in\r\n")
+		r.ReadString('\n')
+	}()
+
+	client := NewSMTPClient(domain.SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     listener.Addr().(*net.TCPAddr).Port,
+		TLSMode:  domain.SMTPTLSStartTLS,
+		Auth:     domain.SMTPAuthPlain,
+		Username: "solar@example.com",
+		Password: "secret",
+	}, nopLogger{})
+
+	err = client.Send(context.Background(), "solar@example.com", []string{"a@example.com"}, []byte("hi"))
+	if err == nil || !strings.Contains(err.Error(), "does not support STARTTLS") {
+		t.Errorf("Send error = %v, want STARTTLS refusal", err)
+	}
+}
@@ -0,0 +1,104 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// TeamsAdapter implements PushNotifier by posting Adaptive Cards to a
+// Microsoft Teams incoming webhook or Workflows webhook
+type TeamsAdapter struct {
+	pushMessages
+	webhookURL string
+	client     *http.Client
+}
+
+// NewTeamsAdapter creates a new Teams adapter
+func NewTeamsAdapter(config *domain.Config, templates *Templates, logger domain.Logger) *TeamsAdapter {
+	return &TeamsAdapter{
+		pushMessages: newPushMessages(config, templates, logger),
+		webhookURL:   config.TeamsWebhookURL,
+		client:       &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// teamsColor is the Adaptive Card text color of the title for a severity
+func teamsColor(severity domain.Severity) string {
+	switch severity {
+	case domain.SeverityInfo:
+		return "Accent"
+	case domain.SeverityCritical:
+		return "Attention"
+	default:
+		return "Warning"
+	}
+}
+
+// SendNotification posts the title and message as an Adaptive Card. Webhook
+// cards cannot carry attachments, so the chart image is not sent.
+func (t *TeamsAdapter) SendNotification(ctx context.Context, title, message string, imageData []byte, severity domain.Severity) error {
+	return t.send(ctx, title, teamsCardBody(title, message, severity))
+}
+
+// SendAlertNotification posts the alert as an Adaptive Card with a fact set
+// of its key fields
+func (t *TeamsAdapter) SendAlertNotification(ctx context.Context, title, message string, analysis *domain.AlertAnalysis, imageData []byte) error {
+	var facts []map[string]string
+	for _, fact := range alertFacts(analysis, t.settings, t.templates.Locale()) {
+		facts = append(facts, map[string]string{"title": fact.Label, "value": fact.Value})
+	}
+	body := append(teamsCardBody(title, message, analysis.Severity), map[string]any{"type": "FactSet", "facts": facts})
+	return t.send(ctx, title, body)
+}
+
+// teamsCardBody lays out the title and message as card text blocks
+func teamsCardBody(title, message string, severity domain.Severity) []map[string]any {
+	body := []map[string]any{{
+		"type":   "TextBlock",
+		"text":   title,
+		"size":   "Medium",
+		"weight": "Bolder",
+		"color":  teamsColor(severity),
+		"wrap":   true,
+	}}
+	if message != "" {
+		body = append(body, map[string]any{"type": "TextBlock", "text": message, "wrap": true})
+	}
+	return body
+}
+
+// send posts the card body wrapped in the message envelope Teams expects
+func (t *TeamsAdapter) send(ctx context.Context, title string, cardBody []map[string]any) error {
+	if t.webhookURL == "" {
+		t.logger.Debug("Teams not configured, skipping notification")
+		return nil // Not an error, just not configured
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    cardBody,
+			},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode teams message: %w", err)
+	}
+	if err := postWebhook(ctx, t.client, "teams", t.webhookURL, "application/json", payload); err != nil {
+		t.logger.Error("Failed to send Teams notification", "error", err.Error())
+		return err
+	}
+
+	t.logger.Info("Teams notification sent successfully", "title", title)
+	return nil
+}
@@ -0,0 +1,53 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+func TestTeamsSendAlertNotification(t *testing.T) {
+	var got struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string              `json:"type"`
+					Text  string              `json:"text"`
+					Color string              `json:"color"`
+					Facts []map[string]string `json:"facts"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted) // Workflows webhooks answer 202
+	}))
+	defer server.Close()
+
+	adapter := NewTeamsAdapter(&domain.Config{TeamsWebhookURL: server.URL}, nil, nopLogger{})
+	err := adapter.SendAlertNotification(context.Background(), "🚨 Solar Production Critical", "Production below 2 kW", newChatTestAnalysis(), nil)
+	if err != nil {
+		t.Fatalf("SendAlertNotification: %v", err)
+	}
+
+	if got.Type != "message" || len(got.Attachments) != 1 || got.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("message = %+v, want one Adaptive Card attachment", got)
+	}
+	body := got.Attachments[0].Content.Body
+	if len(body) != 3 || body[0].Text != "🚨 Solar Production Critical" || body[0].Color != "Attention" || body[2].Type != "FactSet" {
+		t.Fatalf("card body = %+v, want the title, message and fact set", body)
+	}
+	if facts := body[2].Facts; len(facts) != 4 || facts[1]["title"] != "Consecutive hours" || facts[1]["value"] != "6" {
+		t.Errorf("facts = %v", facts)
+	}
+}
@@ -0,0 +1,204 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+const (
+	defaultTelegramAPIBaseURL = "https://api.telegram.org"
+	telegramCaptionLimit      = 1024 // Characters allowed in a photo caption
+	telegramMessageLimit      = 4096 // Characters allowed in a text message
+	telegramMaxRetries        = 3    // Retries after a 429 response
+	telegramMaxRetryAfter     = 60 * time.Second
+)
+
+// TelegramAdapter implements PushNotifier using the Telegram Bot API
+type TelegramAdapter struct {
+	pushMessages
+	botToken   string
+	chatID     string
+	apiBaseURL string
+	client     *http.Client
+	retryUnit  time.Duration // Duration of one retry_after unit (a second; shorter in tests)
+}
+
+// NewTelegramAdapter creates a new Telegram adapter
+func NewTelegramAdapter(config *domain.Config, templates *Templates, logger domain.Logger) *TelegramAdapter {
+	apiBaseURL := config.TelegramAPIBaseURL
+	if apiBaseURL == "" {
+		apiBaseURL = defaultTelegramAPIBaseURL
+	}
+	return &TelegramAdapter{
+		pushMessages: newPushMessages(config, templates, logger),
+		botToken:     config.TelegramBotToken,
+		chatID:       config.TelegramChatID,
+		apiBaseURL:   strings.TrimRight(apiBaseURL, "/"),
+		client:       &http.Client{Timeout: 15 * time.Second},
+		retryUnit:    time.Second,
+	}
+}
+
+// telegramResponse is the envelope of every Bot API response
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// SendNotification sends the chart as a photo with the title and message as
+// its caption, or a text message when there is no chart. Info alerts are
+// delivered silently. Only a failed photo, or a failed text without a chart,
+// is reported as an error.
+func (t *TelegramAdapter) SendNotification(ctx context.Context, title, message string, imageData []byte, severity domain.Severity) error {
+	if t.botToken == "" || t.chatID == "" {
+		t.logger.Debug("Telegram not configured, skipping notification")
+		return nil // Not an error, just not configured
+	}
+
+	text := title
+	if message != "" {
+		text += "\n\n" + message
+	}
+	silent := severity == domain.SeverityInfo
+
+	if len(imageData) == 0 {
+		if err := t.sendMessage(ctx, text, silent); err != nil {
+			return err
+		}
+	} else {
+		// A text too long for a caption follows the photo as its own message.
+		// Once the photo is in the chat the notification counts as delivered: a
+		// retry would post the chart again, so a failed follow-up is only logged.
+		caption := text
+		if utf8.RuneCountInString(caption) > telegramCaptionLimit {
+			caption = truncateRunes(title, telegramCaptionLimit)
+		}
+		if err := t.sendPhoto(ctx, caption, imageData, silent); err != nil {
+			return err
+		}
+		if caption != text {
+			if err := t.sendMessage(ctx, text, silent); err != nil {
+				t.logger.Error("Failed to send telegram text after the chart photo", "error", err.Error())
+			}
+		}
+	}
+
+	t.logger.Info("Telegram notification sent successfully",
+		"title", title,
+		"silent", silent,
+		"has_image", len(imageData) > 0)
+	return nil
+}
+
+// sendPhoto uploads the chart PNG with a caption
+func (t *TelegramAdapter) sendPhoto(ctx context.Context, caption string, imageData []byte, silent bool) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("chat_id", t.chatID)
+	writer.WriteField("caption", caption)
+	writer.WriteField("disable_notification", fmt.Sprint(silent))
+	part, err := writer.CreateFormFile("photo", "chart.png")
+	if err != nil {
+		return fmt.Errorf("failed to create telegram photo part: %w", err)
+	}
+	if _, err := part.Write(imageData); err != nil {
+		return fmt.Errorf("failed to write telegram photo: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to build telegram photo request: %w", err)
+	}
+	return t.call(ctx, "sendPhoto", writer.FormDataContentType(), body.Bytes())
+}
+
+// sendMessage sends a plain-text message
+func (t *TelegramAdapter) sendMessage(ctx context.Context, text string, silent bool) error {
+	body, err := json.Marshal(map[string]any{
+		"chat_id":              t.chatID,
+		"text":                 truncateRunes(text, telegramMessageLimit),
+		"disable_notification": silent,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode telegram message: %w", err)
+	}
+	return t.call(ctx, "sendMessage", "application/json", body)
+}
+
+// call posts to a Bot API method, waiting out 429 rate limits for the
+// retry_after Telegram asks for
+func (t *TelegramAdapter) call(ctx context.Context, method, contentType string, body []byte) error {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.apiBaseURL+"/bot"+t.botToken+"/"+method, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create telegram request: %w", err)
+		}
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			// The URL contains the bot token, so only the cause is reported
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return fmt.Errorf("failed to send telegram %s: %w", method, err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read telegram response: %w", err)
+		}
+
+		var result telegramResponse
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return fmt.Errorf("telegram API error: %s (status %d)", string(respBody), resp.StatusCode)
+		}
+		if resp.StatusCode == http.StatusOK && result.OK {
+			return nil
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < telegramMaxRetries {
+			wait := time.Duration(max(result.Parameters.RetryAfter, 1)) * t.retryUnit
+			if wait > telegramMaxRetryAfter {
+				return fmt.Errorf("telegram rate limit: retry after %s exceeds %s", wait, telegramMaxRetryAfter)
+			}
+			t.logger.Warn("Telegram rate limit, retrying", "method", method, "attempt", attempt+1, "retry_after", wait.String())
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+
+		t.logger.Error("Telegram API returned error",
+			"method", method,
+			"status", resp.StatusCode,
+			"description", result.Description)
+		return fmt.Errorf("telegram API error: %s (status %d)", result.Description, resp.StatusCode)
+	}
+}
+
+// truncateRunes shortens s to at most limit characters, marking the cut with an ellipsis
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:limit-1]) + "…"
+}
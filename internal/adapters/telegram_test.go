@@ -0,0 +1,145 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// newTelegramTestAdapter points a Telegram adapter at a local stand-in for the Bot API
+func newTelegramTestAdapter(t *testing.T, handler http.HandlerFunc) *TelegramAdapter {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	adapter := NewTelegramAdapter(&domain.Config{
+		TelegramBotToken:   "123:abc",
+		TelegramChatID:     "-1001",
+		TelegramAPIBaseURL: server.URL + "/",
+	}, nil, nopLogger{})
+	adapter.retryUnit = time.Millisecond
+	return adapter
+}
+
+func TestTelegramSendPhoto(t *testing.T) {
+	var gotPath, gotChat, gotCaption, gotSilent string
+	var gotPhoto []byte
+	adapter := newTelegramTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm: %v", err)
+		}
+		gotChat = r.FormValue("chat_id")
+		gotCaption = r.FormValue("caption")
+		gotSilent = r.FormValue("disable_notification")
+		if file, _, err := r.FormFile("photo"); err == nil {
+			gotPhoto, _ = io.ReadAll(file)
+		}
+		io.WriteString(w, `{"ok":true,"result":{}}`)
+	})
+
+	err := adapter.SendNotification(context.Background(), "⚠️ Solar Production Alert", "Low production for 6 hours", []byte("png"), domain.SeverityInfo)
+	if err != nil {
+		t.Fatalf("SendNotification: %v", err)
+	}
+	if gotPath != "/bot123:abc/sendPhoto" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotChat != "-1001" || gotCaption != "⚠️ Solar Production Alert\n\nLow production for 6 hours" || gotSilent != "true" {
+		t.Errorf("chat_id = %q, caption = %q, disable_notification = %q", gotChat, gotCaption, gotSilent)
+	}
+	if string(gotPhoto) != "png" {
+		t.Errorf("photo = %q, want the chart image", gotPhoto)
+	}
+}
+
+func TestTelegramLongCaptionFollowsAsMessage(t *testing.T) {
+	var methods []string
+	var text string
+	adapter := newTelegramTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		if strings.HasSuffix(r.URL.Path, "/sendMessage") {
+			var body struct {
+				Text string `json:"text"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			text = body.Text
+		}
+		io.WriteString(w, `{"ok":true,"result":{}}`)
+	})
+
+	message := strings.Repeat("x", telegramCaptionLimit)
+	if err := adapter.SendNotification(context.Background(), "Title", message, []byte("png"), domain.SeverityWarning); err != nil {
+		t.Fatalf("SendNotification: %v", err)
+	}
+	if strings.Join(methods, ",") != "sendPhoto,sendMessage" {
+		t.Errorf("methods = %v, want the photo then the full text", methods)
+	}
+	if text != "Title\n\n"+message {
+		t.Errorf("message text has %d characters, want the full text", len(text))
+	}
+}
+
+func TestTelegramFollowUpFailureAfterPhotoIsDelivered(t *testing.T) {
+	var methods []string
+	adapter := newTelegramTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		if strings.HasSuffix(r.URL.Path, "/sendMessage") {
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, `{"ok":false,"error_code":500,"description":"Internal Server Error"}`)
+			return
+		}
+		io.WriteString(w, `{"ok":true,"result":{}}`)
+	})
+
+	// A retry of the whole notification would post the chart photo a second time
+	message := strings.Repeat("x", telegramCaptionLimit)
+	if err := adapter.SendNotification(context.Background(), "Title", message, []byte("png"), domain.SeverityWarning); err != nil {
+		t.Errorf("SendNotification = %v, want the photo to count as delivered", err)
+	}
+	if strings.Join(methods, ",") != "sendPhoto,sendMessage" {
+		t.Errorf("methods = %v, want one photo and one text attempt", methods)
+	}
+}
+
+func TestTelegramRetriesAfterRateLimit(t *testing.T) {
+	calls := 0
+	adapter := newTelegramTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 2","parameters":{"retry_after":2}}`)
+			return
+		}
+		io.WriteString(w, `{"ok":true,"result":{}}`)
+	})
+
+	if err := adapter.SendNotification(context.Background(), "Title", "Message", nil, domain.SeverityWarning); err != nil {
+		t.Fatalf("SendNotification: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 2 rate-limited attempts and a successful one", calls)
+	}
+}
+
+func TestTelegramAPIError(t *testing.T) {
+	calls := 0
+	adapter := newTelegramTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+	})
+
+	err := adapter.SendNotification(context.Background(), "Title", "Message", nil, domain.SeverityWarning)
+	if err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Errorf("err = %v, want the API description", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want no retry for a bad request", calls)
+	}
+}
@@ -0,0 +1,327 @@
+package adapters
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// defaultTemplates are the built-in email and push templates. Files named
+// *.html.tmpl are parsed with html/template and *.txt.tmpl with text/template.
+//
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Templates renders email bodies, subjects and push messages in one locale
+type Templates struct {
+	html   *htmltemplate.Template
+	text   *texttemplate.Template
+	locale *domain.Locale
+}
+
+// templateFuncs are the helper functions available to every template. t and
+// printf translate their format through the locale's catalog and write numbers
+// with its decimal separator; date formats a time with translated names.
+func templateFuncs(locale *domain.Locale) map[string]any {
+	return map[string]any{
+		"t": func(message any, args ...any) string {
+			if len(args) == 0 {
+				return locale.T(fmt.Sprint(message))
+			}
+			return locale.Sprintf(fmt.Sprint(message), args...)
+		},
+		"printf":  locale.Sprintf,
+		"date":    locale.FormatTime,
+		"upper":   func(v any) string { return strings.ToUpper(fmt.Sprint(v)) },
+		"join":    strings.Join,
+		"oneLine": func(s string) string { return strings.ReplaceAll(s, "\n", " ") },
+		"mod":     func(a, b int) int { return a % b },
+	}
+}
+
+// LoadTemplates parses the built-in templates, then any *.tmpl files in dir.
+// A user file replaces the built-in file of the same name, and a {{define}}
+// block in it replaces the built-in block of the same name, so a directory
+// can restyle or translate only the parts it needs. An empty dir uses the
+// built-in templates only. A nil locale renders English.
+func LoadTemplates(dir string, locale *domain.Locale) (*Templates, error) {
+	funcs := templateFuncs(locale)
+	t := &Templates{
+		html:   htmltemplate.New("").Funcs(funcs),
+		text:   texttemplate.New("").Funcs(funcs),
+		locale: locale,
+	}
+
+	entries, err := defaultTemplates.ReadDir("templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read built-in templates: %w", err)
+	}
+	for _, entry := range entries {
+		content, err := defaultTemplates.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read built-in template %s: %w", entry.Name(), err)
+		}
+		if err := t.parse(entry.Name(), string(content)); err != nil {
+			return nil, fmt.Errorf("built-in template %s: %w", entry.Name(), err)
+		}
+	}
+
+	if dir == "" {
+		return t, nil
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("template directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates in %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		if err := t.parse(filepath.Base(path), string(content)); err != nil {
+			return nil, fmt.Errorf("template %s: %w", path, err)
+		}
+	}
+	return t, nil
+}
+
+// parse adds one template file under its file name
+func (t *Templates) parse(name, content string) error {
+	switch {
+	case strings.HasSuffix(name, ".html.tmpl"):
+		_, err := t.html.New(name).Parse(content)
+		return err
+	case strings.HasSuffix(name, ".txt.tmpl"):
+		_, err := t.text.New(name).Parse(content)
+		return err
+	default:
+		return fmt.Errorf("template names must end in .html.tmpl or .txt.tmpl")
+	}
+}
+
+// Locale returns the locale the templates render in
+func (t *Templates) Locale() *domain.Locale {
+	if t == nil {
+		return nil
+	}
+	return t.locale
+}
+
+// HTML renders an html/template file or block
+func (t *Templates) HTML(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := t.html.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// Text renders a text/template file or block, trimming surrounding whitespace
+// for single-line blocks such as subjects and titles
+func (t *Templates) Text(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := t.text.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	if strings.HasSuffix(name, ".tmpl") {
+		return buf.String(), nil
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// TemplateSettings are the configuration values templates can use as .Config
+type TemplateSettings struct {
+	ProductionThresholdKW   float64 // production_alert_threshold_kw
+	DailyEnergyThresholdKWh float64 // daily_energy_alert_threshold_kwh, 0 when disabled
+	DaylightGHIThreshold    float64
+	RatedCapacityKW         float64
+	InverterACLimitKW       float64 // 0 when not configured
+	ChartDisplayHours       int
+}
+
+// NewTemplateSettings picks the template settings from the configuration
+func NewTemplateSettings(config *domain.Config) TemplateSettings {
+	return TemplateSettings{
+		ProductionThresholdKW:   config.ProductionAlertThresholdKW,
+		DailyEnergyThresholdKWh: config.DailyEnergyAlertThresholdKWh,
+		DaylightGHIThreshold:    config.DaylightGHIThreshold,
+		RatedCapacityKW:         config.RatedCapacityKW,
+		InverterACLimitKW:       config.InverterACLimitKW,
+		ChartDisplayHours:       config.ChartDisplayHours,
+	}
+}
+
+// AlertTemplateData is the data for alert emails, alert subjects and alert pushes
+type AlertTemplateData struct {
+	Analysis                *domain.AlertAnalysis
+	Config                  TemplateSettings
+	GeneratedAt             time.Time
+	Basis                   string            // Translated ensemble basis such as "P10 of 51 ensemble members", empty for the deterministic forecast
+	ProductionThresholdKW   float64           // Threshold of the duration rule describing the low period, else production_alert_threshold_kw
+	DailyEnergyThresholdKWh float64           // Threshold of the first daily energy rule that fired, else daily_energy_alert_threshold_kwh
+	Recovery                string            // Translated expected recovery such as "Tomorrow 09:00", empty when none is forecast
+	Hours                   []HourSummary     // Upcoming daylight hours, at most 12
+	Days                    []DaySummary      // Forecast energy per calendar day
+	Chart                   htmltemplate.HTML // Production chart: the inline PNG image, or an SVG when no PNG is attached (email only)
+}
+
+// HourSummary is one upcoming daylight hour
+type HourSummary struct {
+	domain.SolarProduction
+	OutputKW  float64 // Output on the alert's production basis (its AlertQuantile)
+	Low       bool    // OutputKW below the alert's production threshold
+	Icon      string  // Weather emoji
+	Condition string  // Translated weather description such as "Mostly Cloudy"
+}
+
+// DaySummary is one day of the daily energy forecast
+type DaySummary struct {
+	domain.DailyEnergy
+	Low        bool    // Below the daily energy threshold
+	BarPercent float64 // Bar width relative to the best day, or the threshold if higher
+}
+
+// RecoveryTemplateData is the data for recovery emails, subjects and pushes
+type RecoveryTemplateData struct {
+	Summary             *domain.RecoverySummary
+	Config              TemplateSettings
+	GeneratedAt         time.Time
+	LowDuration         float64           // Hours the low period lasted, 0 when unknown
+	ForecastLowDuration float64           // Hours the first alert forecast the low period to last, 0 when unknown
+	Chart               htmltemplate.HTML // Upcoming production chart (email only)
+}
+
+// NewAlertTemplateData computes the summaries the alert templates use
+func NewAlertTemplateData(analysis *domain.AlertAnalysis, settings TemplateSettings, locale *domain.Locale) *AlertTemplateData {
+	data := &AlertTemplateData{
+		Analysis:                analysis,
+		Config:                  settings,
+		GeneratedAt:             time.Now(),
+		ProductionThresholdKW:   settings.ProductionThresholdKW,
+		DailyEnergyThresholdKWh: settings.DailyEnergyThresholdKWh,
+	}
+
+	// Custom rules may use other thresholds than the top-level settings; the first
+	// rule of each type is the one the analysis describes
+	var haveProduction, haveEnergy bool
+	for _, rule := range analysis.FiredRules {
+		switch {
+		case rule.Type == domain.RuleTypeDuration && !haveProduction:
+			data.ProductionThresholdKW, haveProduction = rule.Threshold, true
+		case rule.Type == domain.RuleTypeDailyEnergy && !haveEnergy:
+			data.DailyEnergyThresholdKWh, haveEnergy = rule.Threshold, true
+		}
+	}
+
+	if analysis.AlertQuantile != "" && analysis.AlertQuantile != domain.AlertOnDeterministic {
+		data.Basis = locale.Sprintf("%s of %d ensemble members", strings.ToUpper(string(analysis.AlertQuantile)), analysis.EnsembleMembers)
+	}
+
+	if analysis.HasRecovery {
+		switch calendarDaysBetween(data.GeneratedAt, analysis.RecoveryHour) {
+		case 0:
+			data.Recovery = locale.FormatTime(analysis.RecoveryHour, "Today 15:04")
+		case 1:
+			data.Recovery = locale.FormatTime(analysis.RecoveryHour, "Tomorrow 15:04")
+		default:
+			data.Recovery = locale.FormatTime(analysis.RecoveryHour, "Mon Jan 2, 15:04")
+		}
+	}
+
+	hours := upcomingDaylightHours(analysis.AllProductionHours, settings.ChartDisplayHours, settings.DaylightGHIThreshold)
+	if len(hours) > 12 {
+		hours = hours[:12]
+	}
+	for _, prod := range hours {
+		output := prod.OutputAt(analysis.AlertQuantile)
+		data.Hours = append(data.Hours, HourSummary{
+			SolarProduction: prod,
+			OutputKW:        output,
+			Low:             output < data.ProductionThresholdKW,
+			Icon:            weatherIcon(prod.CloudCover, prod.GHI),
+			Condition:       locale.T(weatherCondition(prod.CloudCover, prod.GHI)),
+		})
+	}
+
+	// Scale bars to the best day (or the threshold, if higher)
+	maxEnergy := data.DailyEnergyThresholdKWh
+	for _, day := range analysis.DailyEnergy {
+		if day.EnergyKWh > maxEnergy {
+			maxEnergy = day.EnergyKWh
+		}
+	}
+	if maxEnergy <= 0 {
+		maxEnergy = 1
+	}
+	for _, day := range analysis.DailyEnergy {
+		summary := DaySummary{DailyEnergy: day, BarPercent: day.EnergyKWh / maxEnergy * 100}
+		for _, lowDay := range analysis.LowEnergyDays {
+			if lowDay.Date.Equal(day.Date) {
+				summary.Low = true
+			}
+		}
+		data.Days = append(data.Days, summary)
+	}
+	return data
+}
+
+// NewRecoveryTemplateData computes the summaries the recovery templates use
+func NewRecoveryTemplateData(summary *domain.RecoverySummary, settings TemplateSettings) *RecoveryTemplateData {
+	return &RecoveryTemplateData{
+		Summary:             summary,
+		Config:              settings,
+		GeneratedAt:         time.Now(),
+		LowDuration:         summary.LowDuration().Hours(),
+		ForecastLowDuration: summary.ForecastLowDuration().Hours(),
+	}
+}
+
+// calendarDaysBetween returns how many calendar days t falls after now, counted
+// in t's location: 0 for the same date, 1 for the next one
+func calendarDaysBetween(now, t time.Time) int {
+	now = now.In(t.Location())
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// weatherIcon returns an emoji for the sky conditions
+func weatherIcon(cloudCover int, ghi float64) string {
+	if ghi < 100 {
+		return "🌙" // Night/dark
+	} else if cloudCover >= 80 {
+		return "☁️" // Overcast
+	} else if cloudCover >= 60 {
+		return "⛅" // Mostly cloudy
+	} else if cloudCover >= 30 {
+		return "🌤️" // Partly cloudy
+	}
+	return "☀️" // Clear
+}
+
+// weatherCondition describes the sky conditions
+func weatherCondition(cloudCover int, ghi float64) string {
+	if ghi < 100 {
+		return "Night/Dark"
+	} else if cloudCover >= 80 {
+		return "Heavily Overcast"
+	} else if cloudCover >= 60 {
+		return "Mostly Cloudy"
+	} else if cloudCover >= 30 {
+		return "Partly Cloudy"
+	}
+	return "Clear Skies"
+}
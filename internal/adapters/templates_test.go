@@ -0,0 +1,245 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+func TestLoadTemplatesOverrides(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// Redefines one block of the built-in push.txt.tmpl
+		"titles.txt.tmpl": `{{define "push_alert_title"}}Solare: {{.Analysis.Severity}}{{end}}`,
+		// Replaces the built-in file of the same name
+		"recovery.txt.tmpl": `Produzione ripristinata sopra {{printf "%.1f" .Config.ProductionThresholdKW}} kW`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := LoadTemplates(dir, nil)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	pushover := &PushoverAdapter{pushMessages: pushMessages{templates: templates, settings: TemplateSettings{ProductionThresholdKW: 2.5}, logger: nopLogger{}}}
+
+	analysis := &domain.AlertAnalysis{
+		Severity:   domain.SeverityCritical,
+		FiredRules: []domain.RuleResult{{Name: "low_production", Message: "Low production for 6 hours"}},
+	}
+	title, message, err := pushover.AlertMessage(analysis)
+	if err != nil {
+		t.Fatalf("AlertMessage: %v", err)
+	}
+	if title != "Solare: critical" {
+		t.Errorf("title = %q, want the overridden block", title)
+	}
+	if message != "Low production for 6 hours" {
+		t.Errorf("message = %q, want the built-in block", message)
+	}
+
+	summary := &domain.RecoverySummary{ResolvedAt: time.Now()}
+	text, err := templates.Text("recovery.txt.tmpl", NewRecoveryTemplateData(summary, pushover.settings))
+	if err != nil {
+		t.Fatalf("recovery text: %v", err)
+	}
+	if text != "Produzione ripristinata sopra 2.5 kW" {
+		t.Errorf("recovery text = %q, want the overridden file", text)
+	}
+	if subject, _ := templates.Text("recovery_subject", nil); !strings.Contains(subject, "Solar Production Alert Cleared") {
+		t.Errorf("recovery subject = %q, want the built-in block", subject)
+	}
+}
+
+func TestLoadTemplatesErrors(t *testing.T) {
+	if _, err := LoadTemplates(filepath.Join(t.TempDir(), "missing"), nil); err == nil {
+		t.Error("expected an error for a missing template directory")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.html.tmpl"), []byte(`{{if .Summary}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTemplates(dir, nil); err == nil || !strings.Contains(err.Error(), "broken.html.tmpl") {
+		t.Errorf("err = %v, want a parse error naming the file", err)
+	}
+}
+
+func TestPushAlertMessageUpdate(t *testing.T) {
+	templates, err := LoadTemplates("", nil)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	pushover := &PushoverAdapter{pushMessages: pushMessages{templates: templates, logger: nopLogger{}}}
+
+	recovery := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+	analysis := &domain.AlertAnalysis{
+		Severity:           domain.SeverityWarning,
+		FiredRules:         []domain.RuleResult{{Message: "Low production for 6 hours"}, {Message: "Low energy tomorrow"}},
+		Update:             &domain.AlertUpdate{Changes: []string{"Low period now ends at 14:00"}},
+		AlertQuantile:      domain.AlertOnP10,
+		EnsembleMembers:    51,
+		HasRecovery:        true,
+		RecoveryHour:       recovery,
+		HoursUntilRecovery: 6,
+	}
+	title, message, err := pushover.AlertMessage(analysis)
+	if err != nil {
+		t.Fatalf("AlertMessage: %v", err)
+	}
+	if title != "🔄 Solar Alert Updated" {
+		t.Errorf("title = %q", title)
+	}
+	want := "Low period now ends at 14:00\n\nLow production for 6 hours\nLow energy tomorrow\n" +
+		"Based on P10 of 51 ensemble members\n\nRecovery expected at 14:00 (6 hours)"
+	if message != want {
+		t.Errorf("message = %q, want %q", message, want)
+	}
+}
+
+func TestRenderAlertMessageLocalized(t *testing.T) {
+	es, err := domain.LookupLocale("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	templates, err := LoadTemplates("", es)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	pushover := &PushoverAdapter{pushMessages: pushMessages{templates: templates, logger: nopLogger{}}}
+
+	analysis := &domain.AlertAnalysis{
+		Severity:             domain.SeverityWarning,
+		FiredRules:           []domain.RuleResult{{Name: "low_production", Severity: domain.SeverityWarning, Message: "Producción baja"}},
+		CriteriaTriggered:    domain.AlertCriteria{LowProductionDurationTriggered: true},
+		ConsecutiveHourCount: 6,
+		TotalDaylightHours:   11,
+		AlertQuantile:        domain.AlertOnP10,
+		EnsembleMembers:      51,
+		HasRecovery:          true,
+		RecoveryHour:         time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC),
+		HoursUntilRecovery:   6,
+	}
+	title, message, err := pushover.AlertMessage(analysis)
+	if err != nil {
+		t.Fatalf("AlertMessage: %v", err)
+	}
+	if title != "⚠️ Alerta de producción solar" {
+		t.Errorf("title = %q", title)
+	}
+	if !strings.Contains(message, "Basado en P10 de 51 miembros del conjunto") || !strings.Contains(message, "Recuperación prevista a las 14:00 (6 horas)") {
+		t.Errorf("message = %q, want Spanish text", message)
+	}
+
+	email := &EmailAdapter{templates: templates, settings: TemplateSettings{ProductionThresholdKW: 3.5}, logger: nopLogger{}}
+	data := NewAlertTemplateData(analysis, email.settings, es)
+	msg, err := email.renderMessage("alert", data, nil)
+	if err != nil {
+		t.Fatalf("renderMessage: %v", err)
+	}
+	if !strings.Contains(msg.html, "Producción &lt; 3,5 kW") || !strings.Contains(msg.text, "Producción por debajo de 3,5 kW") {
+		t.Errorf("message does not use the Spanish catalog and decimal comma:\n%s\n%s", msg.text, msg.html)
+	}
+}
+
+func TestNewAlertTemplateDataUsesAlertQuantile(t *testing.T) {
+	start := time.Now().Truncate(time.Hour).Add(time.Hour)
+	analysis := &domain.AlertAnalysis{
+		AlertQuantile: domain.AlertOnP90,
+		AllProductionHours: []domain.SolarProduction{
+			// Low on the deterministic run, but not on the P90 the alert uses
+			{Hour: start, GHI: 500, EstimatedOutputKW: 1.0, Quantiles: &domain.ProductionQuantiles{P10: 0.5, P50: 1.0, P90: 4.0}},
+			// And the other way round
+			{Hour: start.Add(time.Hour), GHI: 500, EstimatedOutputKW: 4.0, Quantiles: &domain.ProductionQuantiles{P10: 2.0, P50: 2.5, P90: 3.0}},
+		},
+	}
+
+	data := NewAlertTemplateData(analysis, TemplateSettings{ProductionThresholdKW: 3.5, ChartDisplayHours: 24, DaylightGHIThreshold: 50}, nil)
+	if len(data.Hours) != 2 {
+		t.Fatalf("hours = %+v, want both daylight hours", data.Hours)
+	}
+	if first := data.Hours[0]; first.OutputKW != 4.0 || first.Low {
+		t.Errorf("first hour = %.1f kW, low %v; want the P90 4.0 kW, not low", first.OutputKW, first.Low)
+	}
+	if second := data.Hours[1]; second.OutputKW != 3.0 || !second.Low {
+		t.Errorf("second hour = %.1f kW, low %v; want the P90 3.0 kW, low", second.OutputKW, second.Low)
+	}
+}
+
+func TestNewAlertTemplateDataUsesFiredRuleThreshold(t *testing.T) {
+	start := time.Now().Truncate(time.Hour).Add(time.Hour)
+	analysis := &domain.AlertAnalysis{
+		// A custom duration rule at 1.0 kW, below the top-level 2.0 kW
+		FiredRules: []domain.RuleResult{{
+			Name: "very_low", Type: domain.RuleTypeDuration, Fired: true,
+			Severity: domain.SeverityWarning, Threshold: 1.0, Message: "Low production",
+		}},
+		CriteriaTriggered:    domain.AlertCriteria{AnyTriggered: true, LowProductionDurationTriggered: true},
+		ConsecutiveHourCount: 6,
+		TotalDaylightHours:   10,
+		HasRecovery:          true,
+		RecoveryHour:         start.Add(2 * time.Hour),
+		HoursUntilRecovery:   2,
+		AllProductionHours: []domain.SolarProduction{
+			{Hour: start, GHI: 500, EstimatedOutputKW: 0.5},
+			{Hour: start.Add(time.Hour), GHI: 500, EstimatedOutputKW: 1.5},
+		},
+	}
+	settings := TemplateSettings{ProductionThresholdKW: 2.0, ChartDisplayHours: 24, DaylightGHIThreshold: 50}
+
+	data := NewAlertTemplateData(analysis, settings, nil)
+	if data.ProductionThresholdKW != 1.0 {
+		t.Errorf("ProductionThresholdKW = %.1f, want the rule's 1.0", data.ProductionThresholdKW)
+	}
+	if len(data.Hours) != 2 || !data.Hours[0].Low || data.Hours[1].Low {
+		t.Errorf("hours = %+v, want only 0.5 kW low against 1.0 kW", data.Hours)
+	}
+
+	templates, err := LoadTemplates("", nil)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	email := &EmailAdapter{templates: templates, settings: settings, logger: nopLogger{}}
+	msg, err := email.renderMessage("alert", data, nil)
+	if err != nil {
+		t.Fatalf("renderMessage: %v", err)
+	}
+	for _, want := range []string{"Production &lt; 1.0 kW", "rise above 1.0 kW"} {
+		if !strings.Contains(msg.html, want) {
+			t.Errorf("HTML does not contain %q", want)
+		}
+	}
+	if !strings.Contains(msg.text, "Production below 1.0 kW") || strings.Contains(msg.text+msg.html, "2.0 kW") {
+		t.Errorf("message uses the top-level threshold instead of the rule's:\n%s", msg.text)
+	}
+}
+
+func TestCalendarDaysBetween(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	evening := time.Date(2026, 3, 1, 18, 0, 0, 0, loc)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want int
+	}{
+		{"later the same evening", time.Date(2026, 3, 1, 23, 0, 0, 0, loc), 0},
+		{"next morning, under 24 hours away", time.Date(2026, 3, 2, 8, 0, 0, 0, loc), 1},
+		{"day after tomorrow, under 48 hours away", time.Date(2026, 3, 3, 17, 0, 0, 0, loc), 2},
+		{"day after tomorrow, over 48 hours away", time.Date(2026, 3, 3, 20, 0, 0, 0, loc), 2},
+		// 18:00 CET is already March 2 in Tokyo, and 09:00 on March 3 there is still March 3 in CET
+		{"dates taken in the recovery's location", time.Date(2026, 3, 3, 9, 0, 0, 0, time.FixedZone("JST", 9*3600)), 1},
+	}
+	for _, tt := range tests {
+		if got := calendarDaysBetween(evening, tt.t); got != tt.want {
+			t.Errorf("%s: calendarDaysBetween = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
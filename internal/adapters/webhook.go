@@ -0,0 +1,213 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// Webhook request headers
+const (
+	webhookEventHeader     = "X-Solar-Event"
+	webhookDeliveryHeader  = "X-Solar-Delivery"
+	webhookTimestampHeader = "X-Solar-Timestamp"
+	webhookSignatureHeader = "X-Solar-Signature"
+)
+
+// webhookMinAttemptTime is the least time a retry needs after its backoff;
+// a retry that cannot get it before the context deadline is skipped
+const webhookMinAttemptTime = time.Second
+
+// WebhookAdapter implements PushNotifier by posting versioned JSON events,
+// signed with HMAC-SHA256, to a generic webhook. Failed deliveries are
+// retried with exponential backoff.
+type WebhookAdapter struct {
+	pushMessages
+	url         string
+	secret      string
+	maxAttempts int
+	retryDelay  time.Duration
+	client      *http.Client
+}
+
+// NewWebhookAdapter creates a new webhook adapter
+func NewWebhookAdapter(config *domain.Config, templates *Templates, logger domain.Logger) *WebhookAdapter {
+	return &WebhookAdapter{
+		pushMessages: newPushMessages(config, templates, logger),
+		url:          config.Webhook.URL,
+		secret:       config.Webhook.Secret,
+		maxAttempts:  config.Webhook.MaxAttempts,
+		retryDelay:   time.Duration(config.Webhook.RetryDelaySeconds) * time.Second,
+		client:       &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// SendNotification does nothing: webhook events need the alert analysis or
+// recovery summary, which plain notifications do not carry
+func (w *WebhookAdapter) SendNotification(ctx context.Context, title, message string, imageData []byte, severity domain.Severity) error {
+	w.logger.Debug("Webhook only carries alert and recovery events, skipping notification", "title", title)
+	return nil
+}
+
+// SendAlertNotification posts an alert_opened event, or alert_updated when the
+// analysis updates an earlier alert
+func (w *WebhookAdapter) SendAlertNotification(ctx context.Context, title, message string, analysis *domain.AlertAnalysis, imageData []byte) error {
+	event := WebhookEventAlertOpened
+	if analysis.Update != nil {
+		event = WebhookEventAlertUpdated
+	}
+	return w.send(ctx, webhookEvent{
+		ID:         analysis.NotificationID,
+		Event:      event,
+		OccurredAt: analysis.NotifiedAt,
+		Severity:   analysis.Severity,
+		Title:      title,
+		Message:    message,
+		Alert:      newWebhookAlert(analysis),
+		Production: newWebhookProduction(analysis.AllProductionHours),
+	})
+}
+
+// SendRecoveryNotification posts an alert_recovered event
+func (w *WebhookAdapter) SendRecoveryNotification(ctx context.Context, title, message string, summary *domain.RecoverySummary, imageData []byte) error {
+	return w.send(ctx, webhookEvent{
+		ID:         summary.NotificationID,
+		Event:      WebhookEventAlertRecovered,
+		OccurredAt: summary.ResolvedAt,
+		Severity:   domain.SeverityInfo,
+		Title:      title,
+		Message:    message,
+		Alert:      newWebhookAlert(summary.Analysis),
+		Recovery:   newWebhookRecovery(summary),
+		Production: newWebhookProduction(summary.Analysis.AllProductionHours),
+	})
+}
+
+// send stamps and encodes the event and delivers it, retrying failures. The
+// event ID and time are the notification's, so retries on later runs post the
+// same body; a notification without them gets a random ID and the current time.
+func (w *WebhookAdapter) send(ctx context.Context, event webhookEvent) error {
+	if w.url == "" {
+		w.logger.Debug("Webhook not configured, skipping notification")
+		return nil // Not an error, just not configured
+	}
+
+	if event.ID == "" {
+		id, err := newWebhookEventID()
+		if err != nil {
+			return err
+		}
+		event.ID = id
+	}
+	event.SchemaVersion = WebhookSchemaVersion
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	attempts := max(w.maxAttempts, 1)
+	delay := w.retryDelay
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay+webhookMinAttemptTime {
+				w.logger.Warn("Not enough time left to retry webhook", "event", event.Event, "attempt", attempt, "delay_seconds", delay.Seconds())
+				break
+			}
+			w.logger.Info("Retrying webhook", "event", event.Event, "attempt", attempt, "delay_seconds", delay.Seconds())
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("webhook %s not delivered: %w (last error: %v)", event.Event, ctx.Err(), lastErr)
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		retryable, err := w.post(ctx, event, body)
+		if err == nil {
+			w.logger.Info("Webhook event delivered", "event", event.Event, "id", event.ID, "attempt", attempt)
+			return nil
+		}
+		lastErr = err
+		w.logger.Warn("Webhook delivery failed", "event", event.Event, "attempt", attempt, "error", err.Error())
+		if !retryable {
+			break
+		}
+	}
+
+	w.logger.Error("Failed to deliver webhook event", "event", event.Event, "id", event.ID, "error", lastErr.Error())
+	return fmt.Errorf("webhook %s not delivered: %w", event.Event, lastErr)
+}
+
+// post makes one delivery attempt. Network errors, 429 and 5xx responses are
+// worth retrying; other failures are not.
+func (w *WebhookAdapter) post(ctx context.Context, event webhookEvent, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, event.Event)
+	req.Header.Set(webhookDeliveryHeader, event.ID)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	if w.secret != "" {
+		req.Header.Set(webhookSignatureHeader, WebhookSignature(w.secret, timestamp, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		// The webhook URL may embed a token, so only the cause is reported
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return true, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, fmt.Errorf("webhook error: %s (status %d)", strings.TrimSpace(string(respBody)), resp.StatusCode)
+	}
+	return false, nil
+}
+
+// WebhookSignature returns the X-Solar-Signature value for a request body:
+// "sha256=" and the hex HMAC-SHA256, keyed with the secret, of the
+// X-Solar-Timestamp value, a dot and the body
+func WebhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookEventID returns a random event ID
+func newWebhookEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook event ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
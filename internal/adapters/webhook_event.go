@@ -0,0 +1,299 @@
+package adapters
+
+import (
+	_ "embed"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// WebhookSchemaVersion is the version of the webhook event schema. It is only
+// increased when a field is removed or changes meaning; fields may be added
+// within a version, so receivers should ignore fields they do not know.
+const WebhookSchemaVersion = 1
+
+// Webhook event types
+const (
+	WebhookEventAlertOpened    = "alert_opened"    // First alert of a low production period
+	WebhookEventAlertUpdated   = "alert_updated"   // The alert changed materially since it was sent
+	WebhookEventAlertRecovered = "alert_recovered" // The low production period is over
+)
+
+// WebhookEventSchema is the JSON Schema of the webhook events
+//
+//go:embed webhook_event.schema.json
+var WebhookEventSchema []byte
+
+// webhookEvent is the JSON body of every webhook request
+type webhookEvent struct {
+	SchemaVersion int              `json:"schema_version"`
+	ID            string           `json:"id"`
+	Event         string           `json:"event"`
+	OccurredAt    time.Time        `json:"occurred_at"`
+	Severity      domain.Severity  `json:"severity"`
+	Title         string           `json:"title"`
+	Message       string           `json:"message"`
+	Alert         webhookAlert     `json:"alert"`
+	Recovery      *webhookRecovery `json:"recovery"`
+	Production    []webhookHour    `json:"production"`
+}
+
+// webhookAlert is the alert analysis. For recoveries it is the current
+// forecast, which no longer fires any rule.
+type webhookAlert struct {
+	Severity            domain.Severity      `json:"severity"`
+	Criteria            webhookCriteria      `json:"criteria"`
+	FiredRules          []webhookRule        `json:"fired_rules"`
+	RecommendedAction   string               `json:"recommended_action"`
+	LowHours            []time.Time          `json:"low_hours"`
+	ConsecutiveLowHours int                  `json:"consecutive_low_hours"`
+	FirstLowHour        *time.Time           `json:"first_low_hour"`
+	LastLowHour         *time.Time           `json:"last_low_hour"`
+	MinOutputKW         float64              `json:"min_output_kw"`
+	TotalDaylightHours  int                  `json:"total_daylight_hours"`
+	RecoveryHour        *time.Time           `json:"recovery_hour"`
+	HoursUntilRecovery  int                  `json:"hours_until_recovery"`
+	DailyEnergy         []webhookDay         `json:"daily_energy"`
+	LowEnergyDays       []string             `json:"low_energy_days"`
+	AlertQuantile       domain.AlertQuantile `json:"alert_quantile"`
+	EnsembleMembers     int                  `json:"ensemble_members"`
+	Update              *webhookUpdate       `json:"update"`
+}
+
+// webhookCriteria tells which kinds of rule fired
+type webhookCriteria struct {
+	LowProductionDuration bool `json:"low_production_duration"`
+	LowDailyEnergy        bool `json:"low_daily_energy"`
+}
+
+// webhookRule is one fired rule with the hours and days behind it
+type webhookRule struct {
+	Name          string          `json:"name"`
+	Type          string          `json:"type"`
+	Severity      domain.Severity `json:"severity"`
+	Message       string          `json:"message"`
+	EvidenceHours []time.Time     `json:"evidence_hours"`
+	Days          []string        `json:"days"`
+	RecoveryHour  *time.Time      `json:"recovery_hour"`
+}
+
+// webhookDay is one calendar day's forecast energy
+type webhookDay struct {
+	Date      string  `json:"date"`
+	EnergyKWh float64 `json:"energy_kwh"`
+	Hours     int     `json:"hours"`
+	Complete  bool    `json:"complete"`
+}
+
+// webhookUpdate describes what changed since the previous notification
+type webhookUpdate struct {
+	Changes  []string           `json:"changes"`
+	Previous webhookFingerprint `json:"previous"`
+}
+
+// webhookFingerprint is the summary an alert update is compared against
+type webhookFingerprint struct {
+	FirstLowHour *time.Time      `json:"first_low_hour"`
+	LastLowHour  *time.Time      `json:"last_low_hour"`
+	LowHourCount int             `json:"low_hour_count"`
+	MinOutputKW  float64         `json:"min_output_kw"`
+	Severity     domain.Severity `json:"severity"`
+	Rules        []string        `json:"rules"`
+}
+
+// webhookRecovery is the resolved episode and how it compared with its forecast
+type webhookRecovery struct {
+	EpisodeID                string          `json:"episode_id"`
+	StartedAt                time.Time       `json:"started_at"`
+	ResolvedAt               time.Time       `json:"resolved_at"`
+	Severity                 domain.Severity `json:"severity"`
+	FiredRules               []string        `json:"fired_rules"`
+	LowDurationHours         float64         `json:"low_duration_hours"`
+	ForecastLowDurationHours float64         `json:"forecast_low_duration_hours"`
+	RestOfTodayKWh           float64         `json:"rest_of_today_kwh"`
+	TomorrowKWh              float64         `json:"tomorrow_kwh"`
+	TomorrowComplete         bool            `json:"tomorrow_complete"`
+}
+
+// webhookHour is one forecast hour
+type webhookHour struct {
+	Hour                     time.Time          `json:"hour"`
+	OutputKW                 float64            `json:"output_kw"`
+	OutputPercent            float64            `json:"output_percent"`
+	DCOutputKW               float64            `json:"dc_output_kw"`
+	Clipped                  bool               `json:"clipped"`
+	ClippedKW                float64            `json:"clipped_kw"`
+	CloudCover               int                `json:"cloud_cover"`
+	TemperatureC             float64            `json:"temperature_c"`
+	GHI                      float64            `json:"ghi"`
+	POA                      float64            `json:"poa"`
+	PrecipitationProbability int                `json:"precipitation_probability"`
+	SunElevationDeg          float64            `json:"sun_elevation_deg"`
+	CellTemperatureC         float64            `json:"cell_temperature_c"`
+	WindSpeed                float64            `json:"wind_speed"`
+	Quantiles                *webhookQuantiles  `json:"quantiles"`
+	Arrays                   []webhookArrayHour `json:"arrays"`
+}
+
+// webhookQuantiles is an hour's ensemble production band
+type webhookQuantiles struct {
+	P10     float64 `json:"p10"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	Members int     `json:"members"`
+}
+
+// webhookArrayHour is one array's share of an hour's production
+type webhookArrayHour struct {
+	Name             string  `json:"name"`
+	OutputKW         float64 `json:"output_kw"`
+	POA              float64 `json:"poa"`
+	CellTemperatureC float64 `json:"cell_temperature_c"`
+}
+
+// newWebhookAlert converts an analysis to its event form
+func newWebhookAlert(analysis *domain.AlertAnalysis) webhookAlert {
+	fingerprint := domain.NewAlertFingerprint(analysis)
+	alert := webhookAlert{
+		Severity: analysis.Severity,
+		Criteria: webhookCriteria{
+			LowProductionDuration: analysis.CriteriaTriggered.LowProductionDurationTriggered,
+			LowDailyEnergy:        analysis.CriteriaTriggered.LowDailyEnergyTriggered,
+		},
+		FiredRules:          []webhookRule{},
+		RecommendedAction:   analysis.RecommendedAction,
+		LowHours:            webhookHours(analysis.LowProductionHours),
+		ConsecutiveLowHours: analysis.ConsecutiveHourCount,
+		FirstLowHour:        optionalTime(analysis.FirstLowProductionHour),
+		LastLowHour:         optionalTime(analysis.LastLowProductionHour),
+		MinOutputKW:         fingerprint.MinOutputKW,
+		TotalDaylightHours:  analysis.TotalDaylightHours,
+		HoursUntilRecovery:  analysis.HoursUntilRecovery,
+		DailyEnergy:         []webhookDay{},
+		LowEnergyDays:       webhookDates(analysis.LowEnergyDays),
+		AlertQuantile:       analysis.AlertQuantile,
+		EnsembleMembers:     analysis.EnsembleMembers,
+	}
+	if alert.AlertQuantile == "" {
+		alert.AlertQuantile = domain.AlertOnDeterministic
+	}
+	if analysis.HasRecovery {
+		alert.RecoveryHour = optionalTime(analysis.RecoveryHour)
+	}
+	for _, result := range analysis.FiredRules {
+		rule := webhookRule{
+			Name:          result.Name,
+			Type:          result.Type,
+			Severity:      result.Severity,
+			Message:       result.Message,
+			EvidenceHours: webhookHours(result.Evidence),
+			Days:          webhookDates(result.Days),
+		}
+		if result.HasRecovery {
+			rule.RecoveryHour = optionalTime(result.RecoveryHour)
+		}
+		alert.FiredRules = append(alert.FiredRules, rule)
+	}
+	for _, day := range analysis.DailyEnergy {
+		alert.DailyEnergy = append(alert.DailyEnergy, webhookDay{
+			Date:      day.Date.Format("2006-01-02"),
+			EnergyKWh: day.EnergyKWh,
+			Hours:     day.Hours,
+			Complete:  day.Complete,
+		})
+	}
+	if analysis.Update != nil {
+		previous := analysis.Update.Previous
+		alert.Update = &webhookUpdate{
+			Changes: append([]string{}, analysis.Update.Changes...),
+			Previous: webhookFingerprint{
+				FirstLowHour: optionalTime(previous.FirstLowHour),
+				LastLowHour:  optionalTime(previous.LastLowHour),
+				LowHourCount: previous.LowHourCount,
+				MinOutputKW:  previous.MinOutputKW,
+				Severity:     previous.Severity,
+				Rules:        append([]string{}, previous.Rules...),
+			},
+		}
+	}
+	return alert
+}
+
+// newWebhookRecovery converts a recovery summary to its event form
+func newWebhookRecovery(summary *domain.RecoverySummary) *webhookRecovery {
+	return &webhookRecovery{
+		EpisodeID:                summary.Episode.ID,
+		StartedAt:                summary.Episode.StartedAt,
+		ResolvedAt:               summary.ResolvedAt,
+		Severity:                 summary.Episode.Severity,
+		FiredRules:               append([]string{}, summary.Episode.FiredRules...),
+		LowDurationHours:         summary.LowDuration().Hours(),
+		ForecastLowDurationHours: summary.ForecastLowDuration().Hours(),
+		RestOfTodayKWh:           summary.RestOfTodayKWh,
+		TomorrowKWh:              summary.TomorrowKWh,
+		TomorrowComplete:         summary.TomorrowComplete,
+	}
+}
+
+// newWebhookProduction converts the forecast hours to their event form
+func newWebhookProduction(production []domain.SolarProduction) []webhookHour {
+	hours := make([]webhookHour, 0, len(production))
+	for _, prod := range production {
+		hour := webhookHour{
+			Hour:                     prod.Hour,
+			OutputKW:                 prod.EstimatedOutputKW,
+			OutputPercent:            prod.OutputPercentage,
+			DCOutputKW:               prod.DCOutputKW,
+			Clipped:                  prod.Clipped,
+			ClippedKW:                prod.ClippedKW,
+			CloudCover:               prod.CloudCover,
+			TemperatureC:             prod.Temperature,
+			GHI:                      prod.GHI,
+			POA:                      prod.POA,
+			PrecipitationProbability: prod.PrecipitationProbability,
+			SunElevationDeg:          prod.SunElevationDeg,
+			CellTemperatureC:         prod.CellTemperature,
+			WindSpeed:                prod.WindSpeed,
+			Arrays:                   []webhookArrayHour{},
+		}
+		if q := prod.Quantiles; q != nil {
+			hour.Quantiles = &webhookQuantiles{P10: q.P10, P50: q.P50, P90: q.P90, Members: q.Members}
+		}
+		for _, array := range prod.Arrays {
+			hour.Arrays = append(hour.Arrays, webhookArrayHour{
+				Name:             array.Name,
+				OutputKW:         array.OutputKW,
+				POA:              array.POA,
+				CellTemperatureC: array.CellTemperature,
+			})
+		}
+		hours = append(hours, hour)
+	}
+	return hours
+}
+
+// webhookHours lists the hours of production entries
+func webhookHours(production []domain.SolarProduction) []time.Time {
+	hours := make([]time.Time, 0, len(production))
+	for _, prod := range production {
+		hours = append(hours, prod.Hour)
+	}
+	return hours
+}
+
+// webhookDates lists the dates of days as YYYY-MM-DD
+func webhookDates(days []domain.DailyEnergy) []string {
+	dates := make([]string, 0, len(days))
+	for _, day := range days {
+		dates = append(dates, day.Date.Format("2006-01-02"))
+	}
+	return dates
+}
+
+// optionalTime returns nil for the zero time, which is sent as null
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,301 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/b0d/solar-forecast/internal/domain"
+)
+
+// newWebhookTestAnalysis returns an alert update with every optional part of
+// the event filled in
+func newWebhookTestAnalysis() *domain.AlertAnalysis {
+	analysis := newChatTestAnalysis()
+	start := analysis.FirstLowProductionHour
+	for i := 0; i < 12; i++ {
+		analysis.AllProductionHours = append(analysis.AllProductionHours, domain.SolarProduction{
+			Hour:              start.Add(time.Duration(i) * time.Hour),
+			EstimatedOutputKW: 0.5,
+			Quantiles:         &domain.ProductionQuantiles{P10: 0.2, P50: 0.5, P90: 0.9, Members: 51},
+			Arrays:            []domain.ArrayProduction{{Name: "south", OutputKW: 0.5}},
+		})
+	}
+	analysis.LowProductionHours = analysis.FiredRules[0].Evidence
+	analysis.DailyEnergy = []domain.DailyEnergy{{Date: start.Truncate(24 * time.Hour), EnergyKWh: 6, Hours: 12}}
+	analysis.LowEnergyDays = analysis.DailyEnergy
+	analysis.Update = &domain.AlertUpdate{
+		Previous: domain.AlertFingerprint{FirstLowHour: start, LastLowHour: start.Add(3 * time.Hour), LowHourCount: 4, Severity: domain.SeverityWarning, Rules: []string{"low_production"}},
+		Changes:  []string{"Severity warning → critical"},
+	}
+	return analysis
+}
+
+func TestWebhookSendAlertSigned(t *testing.T) {
+	var got map[string]any
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ := io.ReadAll(r.Body)
+		if want := WebhookSignature("s3cret", r.Header.Get("X-Solar-Timestamp"), body); r.Header.Get("X-Solar-Signature") != want {
+			t.Errorf("signature = %q, want %q", r.Header.Get("X-Solar-Signature"), want)
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	adapter := NewWebhookAdapter(&domain.Config{Webhook: domain.WebhookConfig{URL: server.URL, Secret: "s3cret"}}, nil, nopLogger{})
+	analysis := newChatTestAnalysis()
+	if err := adapter.SendAlertNotification(context.Background(), "Title", "Message", analysis, nil); err != nil {
+		t.Fatalf("SendAlertNotification: %v", err)
+	}
+	if got["event"] != WebhookEventAlertOpened || header.Get("X-Solar-Event") != WebhookEventAlertOpened {
+		t.Errorf("event = %v, header %q", got["event"], header.Get("X-Solar-Event"))
+	}
+	if got["schema_version"] != float64(WebhookSchemaVersion) || got["id"] != header.Get("X-Solar-Delivery") || got["recovery"] != nil {
+		t.Errorf("event = %v", got)
+	}
+
+	analysis.Update = &domain.AlertUpdate{Changes: []string{"Severity warning → critical"}}
+	if err := adapter.SendAlertNotification(context.Background(), "Title", "Message", analysis, nil); err != nil {
+		t.Fatalf("SendAlertNotification: %v", err)
+	}
+	if got["event"] != WebhookEventAlertUpdated {
+		t.Errorf("event = %v, want %s", got["event"], WebhookEventAlertUpdated)
+	}
+}
+
+func TestWebhookSendRecovery(t *testing.T) {
+	var got webhookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Solar-Signature") != "" {
+			t.Errorf("unsigned webhook sent signature %q", r.Header.Get("X-Solar-Signature"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	adapter := NewWebhookAdapter(&domain.Config{Webhook: domain.WebhookConfig{URL: server.URL}}, nil, nopLogger{})
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	summary := &domain.RecoverySummary{
+		Episode:        domain.AlertEpisode{ID: "ep-1", StartedAt: start, Severity: domain.SeverityWarning},
+		Analysis:       &domain.AlertAnalysis{},
+		ResolvedAt:     start.Add(6 * time.Hour),
+		TomorrowKWh:    21.5,
+		NotificationID: "ep-1/recovery",
+	}
+	if err := adapter.SendRecoveryNotification(context.Background(), "Title", "Message", summary, nil); err != nil {
+		t.Fatalf("SendRecoveryNotification: %v", err)
+	}
+	if got.ID != "ep-1/recovery" || got.Event != WebhookEventAlertRecovered || got.Severity != domain.SeverityInfo || got.Recovery == nil {
+		t.Fatalf("event = %+v", got)
+	}
+	if got.Recovery.EpisodeID != "ep-1" || got.Recovery.TomorrowKWh != 21.5 || got.Recovery.LowDurationHours != 6 {
+		t.Errorf("recovery = %+v", got.Recovery)
+	}
+}
+
+func TestWebhookRetriesWithBackoff(t *testing.T) {
+	var requests int
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		ids = append(ids, r.Header.Get("X-Solar-Delivery"))
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	adapter := NewWebhookAdapter(&domain.Config{Webhook: domain.WebhookConfig{URL: server.URL, MaxAttempts: 4}}, nil, nopLogger{})
+	adapter.retryDelay = time.Millisecond
+	if err := adapter.SendAlertNotification(context.Background(), "Title", "Message", newChatTestAnalysis(), nil); err != nil {
+		t.Fatalf("SendAlertNotification: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+	if ids[0] == "" || ids[0] != ids[2] {
+		t.Errorf("delivery IDs = %v, want one ID reused by every retry", ids)
+	}
+}
+
+func TestWebhookKeepsNotificationIDOnLaterRuns(t *testing.T) {
+	var ids, bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get("X-Solar-Delivery"))
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	// A notification retried from the outbox is sent again with the analysis it was queued with
+	adapter := NewWebhookAdapter(&domain.Config{Webhook: domain.WebhookConfig{URL: server.URL}}, nil, nopLogger{})
+	analysis := newChatTestAnalysis()
+	analysis.NotificationID = "ep-1/update/1"
+	analysis.NotifiedAt = time.Date(2025, 6, 21, 7, 0, 0, 0, time.UTC)
+	for range 2 {
+		if err := adapter.SendAlertNotification(context.Background(), "Title", "Message", analysis, nil); err != nil {
+			t.Fatalf("SendAlertNotification: %v", err)
+		}
+	}
+	if len(ids) != 2 || ids[0] != "ep-1/update/1" || ids[1] != ids[0] {
+		t.Errorf("delivery IDs = %v, want the notification's ID on every send", ids)
+	}
+	// Receivers deduplicating on the ID must see the same body, occurred_at included
+	if len(bodies) != 2 || bodies[1] != bodies[0] || !strings.Contains(bodies[0], `"occurred_at":"2025-06-21T07:00:00Z"`) {
+		t.Errorf("bodies differ between sends or lack the notification time:\n%s\n%s", bodies[0], bodies[1])
+	}
+}
+
+func TestWebhookClientErrorNotRetried(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, "bad signature")
+	}))
+	defer server.Close()
+
+	adapter := NewWebhookAdapter(&domain.Config{Webhook: domain.WebhookConfig{URL: server.URL, MaxAttempts: 4}}, nil, nopLogger{})
+	adapter.retryDelay = time.Millisecond
+	err := adapter.SendAlertNotification(context.Background(), "Title", "Message", newChatTestAnalysis(), nil)
+	if err == nil || !strings.Contains(err.Error(), "bad signature") {
+		t.Errorf("err = %v, want the server's response", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	if got := WebhookSignature("secret", "1700000000", []byte("{}")); got != want {
+		t.Errorf("WebhookSignature = %q, want %q", got, want)
+	}
+}
+
+func TestWebhookEventMatchesSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(WebhookEventSchema, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	for _, event := range []webhookEvent{
+		{
+			SchemaVersion: WebhookSchemaVersion,
+			ID:            "id",
+			Event:         WebhookEventAlertUpdated,
+			Severity:      domain.SeverityCritical,
+			Alert:         newWebhookAlert(newWebhookTestAnalysis()),
+			Production:    newWebhookProduction(newWebhookTestAnalysis().AllProductionHours),
+		},
+		{
+			SchemaVersion: WebhookSchemaVersion,
+			ID:            "id",
+			Event:         WebhookEventAlertRecovered,
+			Severity:      domain.SeverityInfo,
+			Alert:         newWebhookAlert(&domain.AlertAnalysis{}),
+			Recovery:      newWebhookRecovery(&domain.RecoverySummary{Analysis: &domain.AlertAnalysis{}}),
+			Production:    newWebhookProduction(nil),
+		},
+	} {
+		body, err := json.Marshal(event)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		var value any
+		json.Unmarshal(body, &value)
+		checkSchema(t, event.Event, value, schema, schema)
+	}
+}
+
+// checkSchema checks that a decoded JSON value has the schema's required
+// properties and no undeclared ones. It covers the subset of JSON Schema the
+// event schema uses.
+func checkSchema(t *testing.T, path string, value any, schema, root map[string]any) {
+	t.Helper()
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		checkSchema(t, path, value, root["$defs"].(map[string]any)[name].(map[string]any), root)
+		return
+	}
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		for _, branch := range oneOf {
+			branch := branch.(map[string]any)
+			if (value == nil) == (branch["type"] == "null") {
+				checkSchema(t, path, value, branch, root)
+				return
+			}
+		}
+		t.Errorf("%s: no oneOf branch for %v", path, value)
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if schema["type"] != "object" {
+			t.Errorf("%s: object where the schema has %v", path, schema["type"])
+			return
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for _, key := range schema["required"].([]any) {
+			if _, ok := v[key.(string)]; !ok {
+				t.Errorf("%s: missing required %q", path, key)
+			}
+		}
+		for key, field := range v {
+			property, ok := properties[key].(map[string]any)
+			if !ok {
+				t.Errorf("%s: %q is not in the schema", path, key)
+				continue
+			}
+			checkSchema(t, path+"."+key, field, property, root)
+		}
+	case []any:
+		if schema["type"] != "array" {
+			t.Errorf("%s: array where the schema has %v", path, schema["type"])
+			return
+		}
+		for _, item := range v {
+			checkSchema(t, path+"[]", item, schema["items"].(map[string]any), root)
+		}
+	}
+}
+
+func TestWebhookRetriesStopAtDeadline(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// The second retry's backoff would run past the channel's deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 2200*time.Millisecond)
+	defer cancel()
+	adapter := NewWebhookAdapter(&domain.Config{Webhook: domain.WebhookConfig{URL: server.URL, MaxAttempts: 4}}, nil, nopLogger{})
+	adapter.retryDelay = 500 * time.Millisecond
+
+	start := time.Now()
+	err := adapter.SendAlertNotification(ctx, "Title", "Message", newChatTestAnalysis(), nil)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("err = %v, want the last delivery error", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2 attempts within the deadline", requests)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("gave up after %v, want before the deadline", elapsed)
+	}
+}
@@ -3,8 +3,11 @@ package config
 import (
 	"bufio"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -13,13 +16,9 @@ import (
 
 // LoadConfig loads configuration from application.properties file
 func LoadConfig(configPath string) (*domain.Config, error) {
-	// Expand ~ to home directory
-	if strings.HasPrefix(configPath, "~") {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
-		}
-		configPath = filepath.Join(home, configPath[1:])
+	configPath, err := expandHome(configPath)
+	if err != nil {
+		return nil, err
 	}
 
 	file, err := os.Open(configPath)
@@ -30,20 +29,68 @@ func LoadConfig(configPath string) (*domain.Config, error) {
 
 	config := &domain.Config{
 		// Set defaults
-		ProductionAlertThresholdKW: 2.0,
-		DurationThresholdHours:     6,
-		DaylightGHIThreshold:       domain.DefaultDaylightGHIThreshold,
-		RatedCapacityKW:            5.0,
-		InverterEfficiency:         0.97,
-		TempCoefficient:            -0.4,
-		ChartDisplayHours:          domain.DefaultChartDisplayHours,
-		AlertAnalysisHours:         domain.DefaultAlertAnalysisHours,
-		NightCompressionFactor:     domain.DefaultNightCompressionFactor,
-		APIRetryAttempts:           3,
-		APIRetryDelaySeconds:       5,
-		APITimeoutSeconds:          10,
+		ProductionAlertThresholdKW:     2.0,
+		DurationThresholdHours:         6,
+		DailyEnergyAlertDays:           domain.DefaultDailyEnergyAlertDays,
+		AlertQuantile:                  domain.AlertOnDeterministic,
+		SeverityTiers:                  domain.DefaultSeverityTiers(),
+		SeverityRoutes:                 domain.DefaultSeverityRoutes(),
+		AlertUpdateHourTolerance:       domain.DefaultAlertUpdateHourTolerance,
+		AlertUpdateKWTolerance:         domain.DefaultAlertUpdateKWTolerance,
+		AlertUpdateMaxPerDay:           domain.DefaultAlertUpdateMaxPerDay,
+		PushoverEmergencyRetrySeconds:  domain.DefaultPushoverEmergencyRetrySeconds,
+		PushoverEmergencyExpireSeconds: domain.DefaultPushoverEmergencyExpireSeconds,
+		DaylightGHIThreshold:           domain.DefaultDaylightGHIThreshold,
+		RatedCapacityKW:                5.0,
+		InverterEfficiency:             0.97,
+		TempCoefficient:                -0.4,
+		PanelTiltDeg:                   0,
+		PanelAzimuthDeg:                180,
+		GroundAlbedo:                   domain.DefaultGroundAlbedo,
+		TranspositionModel:             domain.TranspositionHayDavies,
+		CellTemperature: domain.CellTemperatureConfig{
+			Model:        domain.CellTempModelFaiman,
+			NOCT:         domain.DefaultNOCT,
+			FaimanU0:     domain.DefaultFaimanU0,
+			FaimanU1:     domain.DefaultFaimanU1,
+			SandiaA:      domain.DefaultSandiaA,
+			SandiaB:      domain.DefaultSandiaB,
+			SandiaDeltaT: domain.DefaultSandiaDeltaT,
+		},
+		Webhook: domain.WebhookConfig{
+			MaxAttempts:       domain.DefaultWebhookMaxAttempts,
+			RetryDelaySeconds: domain.DefaultWebhookRetryDelaySeconds,
+		},
+		Outbox: domain.OutboxConfig{
+			MaxAttempts:       domain.DefaultOutboxMaxAttempts,
+			RetryDelayMinutes: domain.DefaultOutboxRetryDelayMinutes,
+		},
+		MQTT: domain.MQTTConfig{
+			ClientID:        domain.DefaultMQTTClientID,
+			TopicPrefix:     domain.DefaultMQTTTopicPrefix,
+			DiscoveryPrefix: domain.DefaultMQTTDiscoveryPrefix,
+			Retain:          true,
+			QoS:             1,
+			ForecastHours:   domain.DefaultMQTTForecastHours,
+		},
+		ChartDisplayHours:      domain.DefaultChartDisplayHours,
+		AlertAnalysisHours:     domain.DefaultAlertAnalysisHours,
+		NightCompressionFactor: domain.DefaultNightCompressionFactor,
+		APIRetryAttempts:       3,
+		APIRetryDelaySeconds:   5,
+		APITimeoutSeconds:      10,
+		RunTimeoutSeconds:      domain.DefaultRunTimeoutSeconds,
+		Locale:                 domain.DefaultLocale,
 	}
 
+	// Per-array settings (array.<name>.<field>) are collected first and resolved after
+	// the whole file is read, so arrays can inherit top-level defaults declared later
+	arraySections := newSections()
+	ruleSections := newSections()
+	recipientSections := newSections()
+	channelSections := newSections()
+	smtpPreset := ""
+
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -83,6 +130,49 @@ func LoadConfig(configPath string) (*domain.Config, error) {
 			if v, err := strconv.Atoi(value); err == nil {
 				config.DurationThresholdHours = v
 			}
+		case "daily_energy_alert_threshold_kwh":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.DailyEnergyAlertThresholdKWh = v
+			}
+		case "daily_energy_alert_days":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.DailyEnergyAlertDays = v
+			}
+		case "ensemble_models":
+			config.EnsembleModels = parseList(value)
+		case "alert_quantile":
+			config.AlertQuantile = domain.AlertQuantile(strings.ToLower(value))
+		case "severity_warning_deficit_percent":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.SeverityTiers.WarningDeficitPercent = v
+			}
+		case "severity_critical_deficit_percent":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.SeverityTiers.CriticalDeficitPercent = v
+			}
+		case "severity_warning_duration_factor":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.SeverityTiers.WarningDurationFactor = v
+			}
+		case "severity_critical_duration_factor":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.SeverityTiers.CriticalDurationFactor = v
+			}
+		case "alert_update_hour_tolerance":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.AlertUpdateHourTolerance = v
+			}
+		case "alert_update_kw_tolerance":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.AlertUpdateKWTolerance = v
+			}
+		case "alert_update_max_per_day":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.AlertUpdateMaxPerDay = v
+			}
+		case "route_info", "route_warning", "route_critical":
+			severity := domain.Severity(strings.TrimPrefix(key, "route_"))
+			config.SeverityRoutes[severity] = append([]string{}, parseList(strings.ToLower(value))...)
 		// analysis_window_start and analysis_window_end are deprecated
 		// daylight detection now uses GHI threshold instead of fixed hours
 		case "daylight_ghi_threshold":
@@ -98,16 +188,86 @@ func LoadConfig(configPath string) (*domain.Config, error) {
 			if v, err := strconv.ParseFloat(value, 64); err == nil {
 				config.InverterEfficiency = v
 			}
+		case "inverter_ac_limit_kw":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.InverterACLimitKW = v
+			}
+		case "inverter_efficiency_curve":
+			curve, err := parseEfficiencyCurve(value)
+			if err != nil {
+				return nil, err
+			}
+			config.InverterEfficiencyCurve = curve
 		case "temp_coefficient":
 			if v, err := strconv.ParseFloat(value, 64); err == nil {
 				config.TempCoefficient = v
 			}
-		case "gmail_app_password":
-			config.GmailAppPassword = value
-		case "gmail_sender":
-			config.GmailSender = value
+		case "panel_tilt_deg":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.PanelTiltDeg = v
+			}
+		case "panel_azimuth_deg":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.PanelAzimuthDeg = v
+			}
+		case "ground_albedo":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.GroundAlbedo = v
+			}
+		case "transposition_model":
+			config.TranspositionModel = domain.TranspositionModel(strings.ToLower(value))
+		case "cell_temp_model":
+			config.CellTemperature.Model = domain.CellTemperatureModel(strings.ToLower(value))
+		case "cell_temp_noct":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.CellTemperature.NOCT = v
+			}
+		case "cell_temp_faiman_u0":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.CellTemperature.FaimanU0 = v
+			}
+		case "cell_temp_faiman_u1":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.CellTemperature.FaimanU1 = v
+			}
+		case "cell_temp_sandia_a":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.CellTemperature.SandiaA = v
+			}
+		case "cell_temp_sandia_b":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.CellTemperature.SandiaB = v
+			}
+		case "cell_temp_sandia_delta_t":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				config.CellTemperature.SandiaDeltaT = v
+			}
+		case "email_sender", "gmail_sender":
+			config.EmailSender = value
+		case "smtp_preset":
+			smtpPreset = strings.ToLower(value)
+		case "smtp_host":
+			config.SMTP.Host = value
+		case "smtp_port":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.SMTP.Port = v
+			}
+		case "smtp_tls":
+			config.SMTP.TLSMode = domain.SMTPTLSMode(strings.ToLower(value))
+		case "smtp_auth":
+			config.SMTP.Auth = domain.SMTPAuthMechanism(strings.ToLower(value))
+		case "smtp_username":
+			config.SMTP.Username = value
+		case "smtp_password", "gmail_app_password":
+			config.SMTP.Password = value
+		case "smtp_ca_file":
+			config.SMTP.CAFile = value
 		case "recipient_email":
 			config.RecipientEmail = value
+		case "locale":
+			config.Locale = value
+		case "template_dir":
+			config.TemplateDir = value
 		// daytime_start_hour and daytime_end_hour are deprecated
 		// sunrise/sunset is now calculated automatically from coordinates
 		case "daytime_start_hour", "daytime_end_hour":
@@ -136,10 +296,110 @@ func LoadConfig(configPath string) (*domain.Config, error) {
 			if v, err := strconv.Atoi(value); err == nil {
 				config.APITimeoutSeconds = v
 			}
+		case "run_timeout_seconds":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.RunTimeoutSeconds = v
+			}
 		case "pushover_user_key":
 			config.PushoverUserKey = value
 		case "pushover_api_token":
 			config.PushoverAPIToken = value
+		case "telegram_bot_token":
+			config.TelegramBotToken = value
+		case "telegram_chat_id":
+			config.TelegramChatID = value
+		case "telegram_api_base_url":
+			config.TelegramAPIBaseURL = value
+		case "slack_webhook_url":
+			config.SlackWebhookURL = value
+		case "discord_webhook_url":
+			config.DiscordWebhookURL = value
+		case "teams_webhook_url":
+			config.TeamsWebhookURL = value
+		case "ntfy_server_url":
+			config.Ntfy.ServerURL = value
+		case "ntfy_topic":
+			config.Ntfy.Topic = value
+		case "ntfy_access_token":
+			config.Ntfy.AccessToken = value
+		case "ntfy_username":
+			config.Ntfy.Username = value
+		case "ntfy_password":
+			config.Ntfy.Password = value
+		case "ntfy_click_url":
+			config.Ntfy.ClickURL = value
+		case "ntfy_tags":
+			config.Ntfy.Tags = parseList(value)
+		case "gotify_server_url":
+			config.Gotify.ServerURL = value
+		case "gotify_app_token":
+			config.Gotify.AppToken = value
+		case "gotify_click_url":
+			config.Gotify.ClickURL = value
+		case "webhook_url":
+			config.Webhook.URL = value
+		case "webhook_secret":
+			config.Webhook.Secret = value
+		case "webhook_max_attempts":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.Webhook.MaxAttempts = v
+			}
+		case "webhook_retry_delay_seconds":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.Webhook.RetryDelaySeconds = v
+			}
+		case "outbox_max_attempts":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.Outbox.MaxAttempts = v
+			}
+		case "outbox_retry_delay_minutes":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.Outbox.RetryDelayMinutes = v
+			}
+		case "mqtt_broker_url":
+			config.MQTT.BrokerURL = value
+		case "mqtt_client_id":
+			config.MQTT.ClientID = value
+		case "mqtt_username":
+			config.MQTT.Username = value
+		case "mqtt_password":
+			config.MQTT.Password = value
+		case "mqtt_topic_prefix":
+			config.MQTT.TopicPrefix = strings.TrimSuffix(value, "/")
+		case "mqtt_discovery_prefix":
+			config.MQTT.DiscoveryPrefix = strings.TrimSuffix(value, "/")
+		case "mqtt_retain":
+			if v, err := strconv.ParseBool(value); err == nil {
+				config.MQTT.Retain = v
+			}
+		case "mqtt_qos":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.MQTT.QoS = v
+			}
+		case "mqtt_forecast_hours":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.MQTT.ForecastHours = v
+			}
+		case "mqtt_ca_file":
+			config.MQTT.CAFile = value
+		case "pushover_emergency_retry_seconds":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.PushoverEmergencyRetrySeconds = v
+			}
+		case "pushover_emergency_expire_seconds":
+			if v, err := strconv.Atoi(value); err == nil {
+				config.PushoverEmergencyExpireSeconds = v
+			}
+		default:
+			if name, field, ok := splitSectionKey(key, "array"); ok {
+				arraySections.add(name, field, value)
+			} else if name, field, ok := splitSectionKey(key, "alert_rule"); ok {
+				ruleSections.add(name, field, value)
+			} else if name, field, ok := splitSectionKey(key, "recipient"); ok {
+				recipientSections.add(name, field, value)
+			} else if name, field, ok := splitSectionKey(key, "channel"); ok {
+				channelSections.add(name, field, value)
+			}
 		}
 	}
 
@@ -147,18 +407,45 @@ func LoadConfig(configPath string) (*domain.Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	config.Arrays = buildArrays(arraySections, config)
+	if len(config.Arrays) > 0 {
+		config.RatedCapacityKW = 0
+		for _, array := range config.Arrays {
+			config.RatedCapacityKW += array.CapacityKW
+		}
+	}
+
+	config.AlertRules = buildAlertRules(ruleSections)
+	config.Channels = buildChannels(channelSections)
+
 	// Apply environment variable overrides
 	applyEnvOverrides(config)
 
+	config.Recipients = buildRecipients(recipientSections, config.RecipientEmail)
+
+	smtp, err := resolveSMTP(config.SMTP, smtpPreset, config.EmailSender)
+	if err != nil {
+		return nil, err
+	}
+	config.SMTP = smtp
+
 	// Validate required fields
-	if config.GmailAppPassword == "" || config.GmailAppPassword == "YOUR_GMAIL_APP_PASSWORD_HERE" {
-		return nil, fmt.Errorf("gmail_app_password not configured - please set in config file or SOLAR_GMAIL_APP_PASSWORD env var")
+	if config.EmailSender == "" || strings.Contains(config.EmailSender, "your-email@gmail.com") {
+		return nil, fmt.Errorf("email_sender not properly configured - please set in config file or SOLAR_EMAIL_SENDER env var")
+	}
+	if err := validateSMTP(config.SMTP); err != nil {
+		return nil, err
 	}
-	if config.GmailSender == "" || strings.Contains(config.GmailSender, "your-email@gmail.com") {
-		return nil, fmt.Errorf("gmail_sender not properly configured - please set in config file or SOLAR_GMAIL_SENDER env var")
+	if len(config.Recipients) == 0 {
+		return nil, fmt.Errorf("no email recipients configured - please set recipient_email (or SOLAR_RECIPIENT_EMAIL env var) or recipient.<name>.address")
 	}
-	if config.RecipientEmail == "" || strings.Contains(config.RecipientEmail, "recipient@example.com") {
-		return nil, fmt.Errorf("recipient_email not configured - please set in config file or SOLAR_RECIPIENT_EMAIL env var")
+	for _, recipient := range config.Recipients {
+		if err := validateRecipient(recipient); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := domain.LookupLocale(config.Locale); err != nil {
+		return nil, fmt.Errorf("locale: %w", err)
 	}
 	// Validate coordinates
 	if config.Latitude == 0 && config.Longitude == 0 {
@@ -178,12 +465,119 @@ func LoadConfig(configPath string) (*domain.Config, error) {
 	if config.DurationThresholdHours < 1 {
 		return nil, fmt.Errorf("duration_threshold_hours must be at least 1, got %d", config.DurationThresholdHours)
 	}
+	if config.DailyEnergyAlertThresholdKWh < 0 {
+		return nil, fmt.Errorf("daily_energy_alert_threshold_kwh must be non-negative, got %.2f", config.DailyEnergyAlertThresholdKWh)
+	}
+	if config.DailyEnergyAlertDays < 1 {
+		return nil, fmt.Errorf("daily_energy_alert_days must be at least 1, got %d", config.DailyEnergyAlertDays)
+	}
+	if _, err := domain.BuildAlertRules(config); err != nil {
+		return nil, err
+	}
+	switch config.AlertQuantile {
+	case domain.AlertOnDeterministic:
+	case domain.AlertOnP10, domain.AlertOnP50, domain.AlertOnP90:
+		if len(config.EnsembleModels) == 0 {
+			return nil, fmt.Errorf("alert_quantile %q requires ensemble_models to be configured", config.AlertQuantile)
+		}
+	default:
+		return nil, fmt.Errorf("alert_quantile must be one of deterministic, p10, p50, p90, got %q", config.AlertQuantile)
+	}
+	if err := validateSeverityTiers(config.SeverityTiers); err != nil {
+		return nil, err
+	}
+	for severity, channels := range config.SeverityRoutes {
+		for _, channel := range channels {
+			if channel != domain.ChannelPush && !domain.IsKnownChannel(channel) {
+				return nil, fmt.Errorf("route_%s: channel must be %q or one of %s, got %q",
+					severity, domain.ChannelPush, strings.Join(domain.KnownChannels, ", "), channel)
+			}
+		}
+	}
+	if config.AlertUpdateHourTolerance < 1 {
+		return nil, fmt.Errorf("alert_update_hour_tolerance must be at least 1, got %d", config.AlertUpdateHourTolerance)
+	}
+	if config.AlertUpdateKWTolerance <= 0 {
+		return nil, fmt.Errorf("alert_update_kw_tolerance must be positive, got %.2f", config.AlertUpdateKWTolerance)
+	}
+	if config.AlertUpdateMaxPerDay < 0 {
+		return nil, fmt.Errorf("alert_update_max_per_day must be non-negative, got %d", config.AlertUpdateMaxPerDay)
+	}
+	if config.PushoverEmergencyRetrySeconds < 30 {
+		return nil, fmt.Errorf("pushover_emergency_retry_seconds must be at least 30, got %d", config.PushoverEmergencyRetrySeconds)
+	}
+	if config.PushoverEmergencyExpireSeconds < config.PushoverEmergencyRetrySeconds || config.PushoverEmergencyExpireSeconds > 10800 {
+		return nil, fmt.Errorf("pushover_emergency_expire_seconds must be between the retry interval and 10800, got %d", config.PushoverEmergencyExpireSeconds)
+	}
+	if config.Webhook.MaxAttempts < 1 {
+		return nil, fmt.Errorf("webhook_max_attempts must be at least 1, got %d", config.Webhook.MaxAttempts)
+	}
+	if config.Webhook.RetryDelaySeconds < 0 {
+		return nil, fmt.Errorf("webhook_retry_delay_seconds must be non-negative, got %d", config.Webhook.RetryDelaySeconds)
+	}
+	if config.Outbox.MaxAttempts < 1 {
+		return nil, fmt.Errorf("outbox_max_attempts must be at least 1, got %d", config.Outbox.MaxAttempts)
+	}
+	if config.Outbox.RetryDelayMinutes < 1 {
+		return nil, fmt.Errorf("outbox_retry_delay_minutes must be at least 1, got %d", config.Outbox.RetryDelayMinutes)
+	}
+	if err := validateMQTT(config.MQTT); err != nil {
+		return nil, err
+	}
+	if config.RunTimeoutSeconds < 2*domain.DefaultChannelTimeoutSeconds {
+		return nil, fmt.Errorf("run_timeout_seconds must be at least %d, got %d", 2*domain.DefaultChannelTimeoutSeconds, config.RunTimeoutSeconds)
+	}
+	for name, channel := range config.Channels {
+		if err := validateChannel(name, channel); err != nil {
+			return nil, err
+		}
+		// A channel may use at most half the run, leaving time for the other steps
+		if channel.TimeoutSeconds > config.RunTimeoutSeconds/2 {
+			return nil, fmt.Errorf("channel.%s.timeout_seconds must be at most half of run_timeout_seconds (%d), got %d",
+				name, config.RunTimeoutSeconds/2, channel.TimeoutSeconds)
+		}
+	}
 	if config.RatedCapacityKW <= 0 {
 		return nil, fmt.Errorf("rated_capacity_kw must be positive, got %.2f", config.RatedCapacityKW)
 	}
+	for _, array := range config.Arrays {
+		if err := validateArray(array); err != nil {
+			return nil, err
+		}
+	}
 	if config.InverterEfficiency <= 0 || config.InverterEfficiency > 1 {
 		return nil, fmt.Errorf("inverter_efficiency must be between 0 and 1, got %.2f", config.InverterEfficiency)
 	}
+	if config.PanelTiltDeg < 0 || config.PanelTiltDeg > 90 {
+		return nil, fmt.Errorf("panel_tilt_deg must be between 0 and 90, got %.1f", config.PanelTiltDeg)
+	}
+	if config.PanelAzimuthDeg < 0 || config.PanelAzimuthDeg >= 360 {
+		return nil, fmt.Errorf("panel_azimuth_deg must be between 0 and 360, got %.1f", config.PanelAzimuthDeg)
+	}
+	if config.GroundAlbedo < 0 || config.GroundAlbedo > 1 {
+		return nil, fmt.Errorf("ground_albedo must be between 0 and 1, got %.2f", config.GroundAlbedo)
+	}
+	if config.TranspositionModel != domain.TranspositionIsotropic && config.TranspositionModel != domain.TranspositionHayDavies {
+		return nil, fmt.Errorf("transposition_model must be %q or %q, got %q",
+			domain.TranspositionIsotropic, domain.TranspositionHayDavies, config.TranspositionModel)
+	}
+	switch config.CellTemperature.Model {
+	case domain.CellTempModelAir, domain.CellTempModelSandia:
+	case domain.CellTempModelNOCT:
+		if config.CellTemperature.NOCT <= 20 {
+			return nil, fmt.Errorf("cell_temp_noct must be above 20, got %.1f", config.CellTemperature.NOCT)
+		}
+	case domain.CellTempModelFaiman:
+		if config.CellTemperature.FaimanU0 <= 0 || config.CellTemperature.FaimanU1 < 0 {
+			return nil, fmt.Errorf("cell_temp_faiman_u0 must be positive and cell_temp_faiman_u1 non-negative, got %.2f and %.2f",
+				config.CellTemperature.FaimanU0, config.CellTemperature.FaimanU1)
+		}
+	default:
+		return nil, fmt.Errorf("cell_temp_model must be one of air, noct, faiman, sandia, got %q", config.CellTemperature.Model)
+	}
+	if config.InverterACLimitKW < 0 {
+		return nil, fmt.Errorf("inverter_ac_limit_kw must be non-negative, got %.2f", config.InverterACLimitKW)
+	}
 	if config.DaylightGHIThreshold < 0 {
 		return nil, fmt.Errorf("daylight_ghi_threshold must be non-negative, got %.2f", config.DaylightGHIThreshold)
 	}
@@ -200,6 +594,389 @@ func LoadConfig(configPath string) (*domain.Config, error) {
 	return config, nil
 }
 
+// sections collects "<prefix>.<name>.<field>" keys grouped by name, preserving the
+// order in which names first appear in the file
+type sections struct {
+	names  []string
+	fields map[string]map[string]string
+}
+
+func newSections() *sections {
+	return &sections{fields: make(map[string]map[string]string)}
+}
+
+// add records one field value for a named section
+func (s *sections) add(name, field, value string) {
+	if _, exists := s.fields[name]; !exists {
+		s.names = append(s.names, name)
+		s.fields[name] = make(map[string]string)
+	}
+	s.fields[name][field] = value
+}
+
+// splitSectionKey splits "prefix.name.field" into name and field
+func splitSectionKey(key, prefix string) (name, field string, ok bool) {
+	rest, found := strings.CutPrefix(key, prefix+".")
+	if !found {
+		return "", "", false
+	}
+	name, field, found = strings.Cut(rest, ".")
+	if !found || name == "" || field == "" {
+		return "", "", false
+	}
+	return name, field, true
+}
+
+// parseFloatField parses a float section field, keeping the fallback when absent or invalid
+func parseFloatField(fields map[string]string, field string, fallback float64) float64 {
+	if raw, ok := fields[field]; ok {
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			return v
+		}
+	}
+	return fallback
+}
+
+// buildArrays converts array.<name>.* sections into PV arrays.
+// Tilt, azimuth and temperature coefficient default to the top-level panel settings.
+func buildArrays(arraySections *sections, config *domain.Config) []domain.PVArray {
+	arrays := make([]domain.PVArray, 0, len(arraySections.names))
+	for _, name := range arraySections.names {
+		fields := arraySections.fields[name]
+		arrays = append(arrays, domain.PVArray{
+			Name:            name,
+			CapacityKW:      parseFloatField(fields, "capacity_kw", 0),
+			TiltDeg:         parseFloatField(fields, "tilt_deg", config.PanelTiltDeg),
+			AzimuthDeg:      parseFloatField(fields, "azimuth_deg", config.PanelAzimuthDeg),
+			LossPercent:     parseFloatField(fields, "loss_percent", 0),
+			TempCoefficient: parseFloatField(fields, "temp_coefficient", config.TempCoefficient),
+		})
+	}
+	return arrays
+}
+
+// buildAlertRules converts alert_rule.<name>.* sections into rule configurations.
+// "type" and "severity" are common to all rules; other fields are rule parameters.
+func buildAlertRules(ruleSections *sections) []domain.AlertRuleConfig {
+	rules := make([]domain.AlertRuleConfig, 0, len(ruleSections.names))
+	for _, name := range ruleSections.names {
+		rule := domain.AlertRuleConfig{Name: name, Params: domain.RuleParams{}}
+		for field, value := range ruleSections.fields[name] {
+			switch field {
+			case "type":
+				rule.Type = strings.ToLower(value)
+			case "severity":
+				rule.Severity = domain.Severity(strings.ToLower(value))
+			default:
+				rule.Params[field] = value
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// buildRecipients converts recipient.<name>.* sections into email recipients.
+// A recipient_email address is kept as a To recipient with default preferences.
+func buildRecipients(recipientSections *sections, recipientEmail string) []domain.EmailRecipient {
+	var recipients []domain.EmailRecipient
+	if recipientEmail != "" && !strings.Contains(recipientEmail, "recipient@example.com") {
+		recipients = append(recipients, domain.EmailRecipient{
+			Name:        "recipient_email",
+			Address:     recipientEmail,
+			Field:       domain.RecipientTo,
+			Kinds:       domain.DefaultRecipientKinds,
+			MinSeverity: domain.SeverityInfo,
+		})
+	}
+	for _, name := range recipientSections.names {
+		fields := recipientSections.fields[name]
+		recipient := domain.EmailRecipient{
+			Name:        name,
+			Address:     fields["address"],
+			Field:       domain.RecipientTo,
+			Kinds:       domain.DefaultRecipientKinds,
+			MinSeverity: domain.SeverityInfo,
+		}
+		if v, ok := fields["field"]; ok {
+			recipient.Field = domain.RecipientField(strings.ToLower(v))
+		}
+		if v, ok := fields["notify"]; ok {
+			recipient.Kinds = nil
+			for _, kind := range parseList(strings.ToLower(v)) {
+				recipient.Kinds = append(recipient.Kinds, domain.EmailKind(kind))
+			}
+		}
+		if v, ok := fields["min_severity"]; ok {
+			recipient.MinSeverity = domain.Severity(strings.ToLower(v))
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients
+}
+
+// buildChannels converts channel.<name>.* sections into per-channel settings.
+// Channels are enabled unless switched off.
+func buildChannels(channelSections *sections) map[string]domain.ChannelConfig {
+	channels := make(map[string]domain.ChannelConfig, len(channelSections.names))
+	for _, name := range channelSections.names {
+		fields := channelSections.fields[name]
+		channel := domain.ChannelConfig{
+			Enabled:        true,
+			TimeoutSeconds: domain.DefaultChannelTimeoutSeconds,
+		}
+		if v, err := strconv.ParseBool(fields["enabled"]); err == nil {
+			channel.Enabled = v
+		}
+		if v, err := strconv.Atoi(fields["timeout_seconds"]); err == nil {
+			channel.TimeoutSeconds = v
+		}
+		channels[name] = channel
+	}
+	return channels
+}
+
+// validateChannel checks the settings of one notification channel
+func validateChannel(name string, channel domain.ChannelConfig) error {
+	if !domain.IsKnownChannel(name) {
+		return fmt.Errorf("channel.%s: unknown notification channel, must be one of %s", name, strings.Join(domain.KnownChannels, ", "))
+	}
+	if channel.TimeoutSeconds < 1 {
+		return fmt.Errorf("channel.%s.timeout_seconds must be at least 1, got %d", name, channel.TimeoutSeconds)
+	}
+	return nil
+}
+
+// validateRecipient checks a single email recipient definition
+func validateRecipient(recipient domain.EmailRecipient) error {
+	if _, err := mail.ParseAddress(recipient.Address); err != nil {
+		if recipient.Name == "recipient_email" {
+			return fmt.Errorf("recipient_email: %w", err)
+		}
+		return fmt.Errorf("recipient.%s.address: %w", recipient.Name, err)
+	}
+	switch recipient.Field {
+	case domain.RecipientTo, domain.RecipientCc, domain.RecipientBcc:
+	default:
+		return fmt.Errorf("recipient.%s.field must be one of to, cc, bcc, got %q", recipient.Name, recipient.Field)
+	}
+	for _, kind := range recipient.Kinds {
+		switch kind {
+		case domain.EmailAlert, domain.EmailRecovery, domain.EmailDigest:
+		default:
+			return fmt.Errorf("recipient.%s.notify must list alert, recovery or digest, got %q", recipient.Name, kind)
+		}
+	}
+	if _, err := domain.ParseSeverity(string(recipient.MinSeverity)); err != nil {
+		return fmt.Errorf("recipient.%s.min_severity: %w", recipient.Name, err)
+	}
+	return nil
+}
+
+// LocaleSetting returns the locale the configuration file and environment ask
+// for, without loading or validating the rest, so that configuration errors can
+// be reported in that language. It returns "" when the file cannot be read.
+func LocaleSetting(configPath string) string {
+	if v := os.Getenv("SOLAR_LOCALE"); v != "" {
+		return v
+	}
+	configPath, err := expandHome(configPath)
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return ""
+	}
+
+	locale := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found || strings.TrimSpace(key) != "locale" {
+			continue
+		}
+		value, _, _ = strings.Cut(value, "#")
+		locale = strings.TrimSpace(value)
+	}
+	return locale
+}
+
+// expandHome expands a leading ~ to the home directory
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// parseList splits a comma-separated value, dropping empty entries
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseEfficiencyCurve parses "load:efficiency" pairs such as "0.1:0.94,0.5:0.97,1.0:0.965"
+func parseEfficiencyCurve(value string) ([]domain.EfficiencyPoint, error) {
+	var curve []domain.EfficiencyPoint
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		loadStr, effStr, found := strings.Cut(pair, ":")
+		if !found {
+			return nil, fmt.Errorf("inverter_efficiency_curve entry %q must be load:efficiency", pair)
+		}
+		load, err := strconv.ParseFloat(strings.TrimSpace(loadStr), 64)
+		if err != nil || load < 0 {
+			return nil, fmt.Errorf("inverter_efficiency_curve entry %q has an invalid load fraction", pair)
+		}
+		eff, err := strconv.ParseFloat(strings.TrimSpace(effStr), 64)
+		if err != nil || eff <= 0 || eff > 1 {
+			return nil, fmt.Errorf("inverter_efficiency_curve entry %q must have an efficiency between 0 and 1", pair)
+		}
+		curve = append(curve, domain.EfficiencyPoint{LoadFraction: load, Efficiency: eff})
+	}
+
+	sort.Slice(curve, func(i, j int) bool {
+		return curve[i].LoadFraction < curve[j].LoadFraction
+	})
+	for i := 1; i < len(curve); i++ {
+		if curve[i].LoadFraction == curve[i-1].LoadFraction {
+			return nil, fmt.Errorf("inverter_efficiency_curve has duplicate load fraction %.2f", curve[i].LoadFraction)
+		}
+	}
+	return curve, nil
+}
+
+// resolveSMTP fills unset mail server settings from the named preset, or from the
+// Gmail preset when no server is configured at all
+func resolveSMTP(smtp domain.SMTPConfig, preset, sender string) (domain.SMTPConfig, error) {
+	if preset == "" && smtp.Host == "" {
+		preset = domain.DefaultSMTPPreset
+	}
+	if preset != "" {
+		settings, ok := domain.SMTPPresets[preset]
+		if !ok {
+			names := make([]string, 0, len(domain.SMTPPresets))
+			for name := range domain.SMTPPresets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return smtp, fmt.Errorf("smtp_preset must be one of %s, got %q", strings.Join(names, ", "), preset)
+		}
+		smtp = smtp.WithPreset(settings)
+	}
+	if smtp.Username == "" && smtp.Password != "" {
+		smtp.Username = sender
+	}
+	return smtp.WithDefaults(), nil
+}
+
+// validateSMTP checks the resolved mail server settings
+func validateSMTP(smtp domain.SMTPConfig) error {
+	if smtp.Port < 1 || smtp.Port > 65535 {
+		return fmt.Errorf("smtp_port must be between 1 and 65535, got %d", smtp.Port)
+	}
+	switch smtp.TLSMode {
+	case domain.SMTPTLSNone, domain.SMTPTLSStartTLS, domain.SMTPTLSImplicit:
+	default:
+		return fmt.Errorf("smtp_tls must be one of none, starttls, tls, got %q", smtp.TLSMode)
+	}
+	switch smtp.Auth {
+	case domain.SMTPAuthNone:
+	case domain.SMTPAuthPlain, domain.SMTPAuthLogin, domain.SMTPAuthCRAMMD5:
+		if smtp.Password == "" || smtp.Password == "YOUR_GMAIL_APP_PASSWORD_HERE" {
+			return fmt.Errorf("smtp_password not configured - please set smtp_password (gmail_app_password for Gmail) in config file or SOLAR_SMTP_PASSWORD env var")
+		}
+		// The clients refuse to send a password in clear text to another host
+		if smtp.Auth != domain.SMTPAuthCRAMMD5 && smtp.TLSMode == domain.SMTPTLSNone && !domain.IsLocalhost(smtp.Host) {
+			return fmt.Errorf("smtp_auth=%s sends the password in clear text and needs smtp_tls=starttls or tls for %s", smtp.Auth, smtp.Host)
+		}
+	default:
+		return fmt.Errorf("smtp_auth must be one of none, plain, login, cram-md5, got %q", smtp.Auth)
+	}
+	if smtp.CAFile != "" {
+		if _, err := os.Stat(smtp.CAFile); err != nil {
+			return fmt.Errorf("smtp_ca_file: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateMQTT checks the broker settings when MQTT publishing is enabled
+func validateMQTT(mqtt domain.MQTTConfig) error {
+	if mqtt.BrokerURL == "" {
+		return nil
+	}
+	broker, err := url.Parse(mqtt.BrokerURL)
+	if err != nil {
+		return fmt.Errorf("mqtt_broker_url: %w", err)
+	}
+	switch broker.Scheme {
+	case "tcp", "mqtt", "ssl", "tls", "mqtts":
+	default:
+		return fmt.Errorf("mqtt_broker_url must start with tcp://, mqtt://, ssl://, tls:// or mqtts://, got %q", mqtt.BrokerURL)
+	}
+	if broker.Hostname() == "" {
+		return fmt.Errorf("mqtt_broker_url has no host: %q", mqtt.BrokerURL)
+	}
+	if mqtt.ClientID == "" || mqtt.TopicPrefix == "" {
+		return fmt.Errorf("mqtt_client_id and mqtt_topic_prefix must not be empty")
+	}
+	if mqtt.QoS != 0 && mqtt.QoS != 1 {
+		return fmt.Errorf("mqtt_qos must be 0 or 1, got %d", mqtt.QoS)
+	}
+	if mqtt.ForecastHours < 1 {
+		return fmt.Errorf("mqtt_forecast_hours must be at least 1, got %d", mqtt.ForecastHours)
+	}
+	if mqtt.CAFile != "" {
+		if _, err := os.Stat(mqtt.CAFile); err != nil {
+			return fmt.Errorf("mqtt_ca_file: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateSeverityTiers checks that each critical boundary is above its warning boundary
+func validateSeverityTiers(tiers domain.SeverityTiers) error {
+	if tiers.WarningDeficitPercent <= 0 || tiers.CriticalDeficitPercent <= tiers.WarningDeficitPercent || tiers.CriticalDeficitPercent > 100 {
+		return fmt.Errorf("severity deficit percents must satisfy 0 < warning < critical <= 100, got %.1f and %.1f",
+			tiers.WarningDeficitPercent, tiers.CriticalDeficitPercent)
+	}
+	if tiers.WarningDurationFactor < 1 || tiers.CriticalDurationFactor <= tiers.WarningDurationFactor {
+		return fmt.Errorf("severity duration factors must satisfy 1 <= warning < critical, got %.2f and %.2f",
+			tiers.WarningDurationFactor, tiers.CriticalDurationFactor)
+	}
+	return nil
+}
+
+// validateArray checks a single PV array definition
+func validateArray(array domain.PVArray) error {
+	if array.CapacityKW <= 0 {
+		return fmt.Errorf("array.%s.capacity_kw must be positive, got %.2f", array.Name, array.CapacityKW)
+	}
+	if array.TiltDeg < 0 || array.TiltDeg > 90 {
+		return fmt.Errorf("array.%s.tilt_deg must be between 0 and 90, got %.1f", array.Name, array.TiltDeg)
+	}
+	if array.AzimuthDeg < 0 || array.AzimuthDeg >= 360 {
+		return fmt.Errorf("array.%s.azimuth_deg must be between 0 and 360, got %.1f", array.Name, array.AzimuthDeg)
+	}
+	if array.LossPercent < 0 || array.LossPercent >= 100 {
+		return fmt.Errorf("array.%s.loss_percent must be between 0 and 100, got %.1f", array.Name, array.LossPercent)
+	}
+	return nil
+}
+
 // applyEnvOverrides applies environment variable overrides to config
 func applyEnvOverrides(config *domain.Config) {
 	// Test mode override (for make mail command)
@@ -207,15 +984,24 @@ func applyEnvOverrides(config *domain.Config) {
 		config.TestMode = true
 		config.ProductionAlertThresholdKW = 5.0
 		config.DurationThresholdHours = 1
-		fmt.Println("[TEST MODE] Using lowered thresholds: 5.0 kW, 1 hour")
 	}
 
 	// Sensitive credentials
+	// SOLAR_GMAIL_* are the names used before other mail servers were supported
 	if v := os.Getenv("SOLAR_GMAIL_APP_PASSWORD"); v != "" {
-		config.GmailAppPassword = v
+		config.SMTP.Password = v
+	}
+	if v := os.Getenv("SOLAR_SMTP_PASSWORD"); v != "" {
+		config.SMTP.Password = v
+	}
+	if v := os.Getenv("SOLAR_SMTP_USERNAME"); v != "" {
+		config.SMTP.Username = v
 	}
 	if v := os.Getenv("SOLAR_GMAIL_SENDER"); v != "" {
-		config.GmailSender = v
+		config.EmailSender = v
+	}
+	if v := os.Getenv("SOLAR_EMAIL_SENDER"); v != "" {
+		config.EmailSender = v
 	}
 	if v := os.Getenv("SOLAR_RECIPIENT_EMAIL"); v != "" {
 		config.RecipientEmail = v
@@ -226,6 +1012,49 @@ func applyEnvOverrides(config *domain.Config) {
 	if v := os.Getenv("SOLAR_PUSHOVER_API_TOKEN"); v != "" {
 		config.PushoverAPIToken = v
 	}
+	if v := os.Getenv("SOLAR_TELEGRAM_BOT_TOKEN"); v != "" {
+		config.TelegramBotToken = v
+	}
+	if v := os.Getenv("SOLAR_SLACK_WEBHOOK_URL"); v != "" {
+		config.SlackWebhookURL = v
+	}
+	if v := os.Getenv("SOLAR_DISCORD_WEBHOOK_URL"); v != "" {
+		config.DiscordWebhookURL = v
+	}
+	if v := os.Getenv("SOLAR_TEAMS_WEBHOOK_URL"); v != "" {
+		config.TeamsWebhookURL = v
+	}
+	if v := os.Getenv("SOLAR_NTFY_ACCESS_TOKEN"); v != "" {
+		config.Ntfy.AccessToken = v
+	}
+	if v := os.Getenv("SOLAR_NTFY_PASSWORD"); v != "" {
+		config.Ntfy.Password = v
+	}
+	if v := os.Getenv("SOLAR_GOTIFY_APP_TOKEN"); v != "" {
+		config.Gotify.AppToken = v
+	}
+	if v := os.Getenv("SOLAR_WEBHOOK_URL"); v != "" {
+		config.Webhook.URL = v
+	}
+	if v := os.Getenv("SOLAR_WEBHOOK_SECRET"); v != "" {
+		config.Webhook.Secret = v
+	}
+	if v := os.Getenv("SOLAR_MQTT_USERNAME"); v != "" {
+		config.MQTT.Username = v
+	}
+	if v := os.Getenv("SOLAR_MQTT_PASSWORD"); v != "" {
+		config.MQTT.Password = v
+	}
+
+	if v := os.Getenv("SOLAR_LOCALE"); v != "" {
+		config.Locale = v
+	}
+	if config.TestMode {
+		// An unsupported locale is reported by validation; until then English is used
+		locale, _ := domain.LookupLocale(config.Locale)
+		fmt.Println(locale.Sprintf("[TEST MODE] Using lowered thresholds: %.1f kW, %d hour",
+			config.ProductionAlertThresholdKW, config.DurationThresholdHours))
+	}
 
 	// Threshold overrides (for testing or adjustment)
 	if v := os.Getenv("SOLAR_PRODUCTION_THRESHOLD_KW"); v != "" {
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+// emailChannel delivers alerts through an EmailNotifier
+type emailChannel struct {
+	notifier EmailNotifier
+}
+
+// NewEmailChannel adapts an email notifier to a notification channel
+func NewEmailChannel(notifier EmailNotifier) Notifier {
+	return &emailChannel{notifier: notifier}
+}
+
+// SendAlert emails an alert or alert update
+func (c *emailChannel) SendAlert(ctx context.Context, analysis *AlertAnalysis) error {
+	return c.notifier.SendAlert(ctx, analysis)
+}
+
+// SendRecovery emails the all-clear
+func (c *emailChannel) SendRecovery(ctx context.Context, summary *RecoverySummary) error {
+	return c.notifier.SendRecoveryEmail(ctx, summary)
+}
+
+// pushChannel delivers alerts through a PushNotifier, with the title, message
+// and chart rendered by the notifier when it can
+type pushChannel struct {
+	notifier PushNotifier
+	locale   *Locale // Language of the built-in texts
+	logger   Logger
+}
+
+// NewPushChannel adapts a push notifier to a notification channel. Texts the
+// notifier does not render itself use the locale's built-in wording.
+func NewPushChannel(notifier PushNotifier, locale *Locale, logger Logger) Notifier {
+	return &pushChannel{notifier: notifier, locale: locale, logger: logger}
+}
+
+// SendAlert pushes an alert or alert update, with the analysis for notifiers
+// that lay out its fields themselves
+func (c *pushChannel) SendAlert(ctx context.Context, analysis *AlertAnalysis) error {
+	title, message := c.alertText(analysis)
+	imageData := c.chartImage(analysis)
+	if notifier, ok := c.notifier.(AlertDetailNotifier); ok {
+		return notifier.SendAlertNotification(ctx, title, message, analysis, imageData)
+	}
+	return c.notifier.SendNotification(ctx, title, message, imageData, analysis.Severity)
+}
+
+// SendRecovery pushes the all-clear, with the summary for notifiers that carry it
+func (c *pushChannel) SendRecovery(ctx context.Context, summary *RecoverySummary) error {
+	title, message := c.recoveryText(summary)
+	imageData := c.chartImage(summary.Analysis)
+	if notifier, ok := c.notifier.(RecoveryDetailNotifier); ok {
+		return notifier.SendRecoveryNotification(ctx, title, message, summary, imageData)
+	}
+	return c.notifier.SendNotification(ctx, title, message, imageData, SeverityInfo)
+}
+
+// chartImage renders the production chart when the notifier supports it.
+// Failures are logged and the notification is sent without an image.
+func (c *pushChannel) chartImage(analysis *AlertAnalysis) []byte {
+	chartGenerator, ok := c.notifier.(interface {
+		GenerateChartImage([]SolarProduction) ([]byte, error)
+	})
+	if !ok {
+		return nil
+	}
+
+	image, err := chartGenerator.GenerateChartImage(analysis.AllProductionHours)
+	if err != nil {
+		c.logger.Warn("Failed to generate chart image for push notification", "error", err.Error())
+		return nil
+	}
+	c.logger.Info("Generated chart image for push notification", "size_bytes", len(image))
+	return image
+}
+
+// alertText returns the push title and message for an alert. Notifiers
+// with their own templates render them; otherwise, or when rendering fails, the
+// built-in wording is used.
+func (c *pushChannel) alertText(analysis *AlertAnalysis) (string, string) {
+	if renderer, ok := c.notifier.(interface {
+		AlertMessage(*AlertAnalysis) (string, string, error)
+	}); ok {
+		title, message, err := renderer.AlertMessage(analysis)
+		if err == nil {
+			return title, message
+		}
+		c.logger.Warn("Failed to render push notification template, using built-in text", "error", err.Error())
+	}
+
+	title := c.locale.T(pushAlertTitle(analysis.Severity))
+	message := c.alertMessage(analysis)
+	if analysis.Update != nil {
+		title = c.locale.T("🔄 Solar Alert Updated")
+		message = strings.Join(analysis.Update.Changes, "\n") + "\n\n" + message
+	}
+	return title, message
+}
+
+// recoveryText returns the push title and message for a recovery, rendered
+// by the notifier's templates when it has them
+func (c *pushChannel) recoveryText(summary *RecoverySummary) (string, string) {
+	if renderer, ok := c.notifier.(interface {
+		RecoveryMessage(*RecoverySummary) (string, string, error)
+	}); ok {
+		title, message, err := renderer.RecoveryMessage(summary)
+		if err == nil {
+			return title, message
+		}
+		c.logger.Warn("Failed to render push notification template, using built-in text", "error", err.Error())
+	}
+	return c.locale.T("✅ Solar Production Recovered"), c.recoveryMessage(summary)
+}
+
+// recoveryMessage builds the recovery push text from the summary
+func (c *pushChannel) recoveryMessage(summary *RecoverySummary) string {
+	message := c.locale.T("Forecast production is back above the alert thresholds.")
+
+	if lasted := summary.LowDuration(); lasted > 0 {
+		message += "\n" + c.locale.Sprintf("Low period lasted %.0f hours", lasted.Hours())
+		if forecast := summary.ForecastLowDuration(); forecast > 0 {
+			message += " " + c.locale.Sprintf("(forecast %.0f)", forecast.Hours())
+		}
+	}
+
+	message += "\n\n" + c.locale.Sprintf("Expected: %.1f kWh rest of today", summary.RestOfTodayKWh)
+	if summary.TomorrowComplete {
+		message += ", " + c.locale.Sprintf("%.1f kWh tomorrow", summary.TomorrowKWh)
+	}
+	return message
+}
+
+// pushAlertTitle returns the push notification title for a severity
+func pushAlertTitle(severity Severity) string {
+	switch severity {
+	case SeverityInfo:
+		return "ℹ️ Solar Production Notice"
+	case SeverityCritical:
+		return "🚨 Solar Production Critical"
+	default:
+		return "⚠️ Solar Production Alert"
+	}
+}
+
+// alertMessage builds the push notification text from the fired rules
+func (c *pushChannel) alertMessage(analysis *AlertAnalysis) string {
+	lines := make([]string, len(analysis.FiredRules))
+	for i, result := range analysis.FiredRules {
+		lines[i] = result.Message
+	}
+	message := strings.Join(lines, "\n")
+
+	if analysis.AlertQuantile != AlertOnDeterministic {
+		message += "\n" + c.locale.Sprintf("Based on %s of %d ensemble members",
+			strings.ToUpper(string(analysis.AlertQuantile)),
+			analysis.EnsembleMembers)
+	}
+
+	if analysis.HasRecovery {
+		message += "\n\n" + c.locale.Sprintf("Recovery expected at %s (%d hours)",
+			analysis.RecoveryHour.Format("15:04"),
+			analysis.HoursUntilRecovery)
+	}
+
+	return message
+}
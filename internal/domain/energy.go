@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// DefaultDailyEnergyAlertDays is the default number of forecast days checked against the daily energy threshold
+const DefaultDailyEnergyAlertDays = 2
+
+// DailyEnergy is the forecast energy for one calendar day
+type DailyEnergy struct {
+	Date      time.Time // Local midnight of the day
+	EnergyKWh float64   // Sum of hourly production on the alert's production basis
+	Hours     int       // Forecast hours covering the day
+	Complete  bool      // True when the forecast covers the whole day
+}
+
+// DailyEnergyTotals sums hourly production into per-calendar-day energy, in the
+// hours' own time zone. Each hourly value is an average over the hour, so kW × 1 h = kWh.
+func DailyEnergyTotals(production []SolarProduction, q AlertQuantile) []DailyEnergy {
+	var days []DailyEnergy
+	for _, prod := range production {
+		y, m, d := prod.Hour.Date()
+		date := time.Date(y, m, d, 0, 0, 0, 0, prod.Hour.Location())
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, DailyEnergy{Date: date})
+		}
+		day := &days[len(days)-1]
+		day.EnergyKWh += prod.OutputAt(q)
+		day.Hours++
+	}
+
+	// Daylight saving days have 23 hours
+	for i := range days {
+		days[i].Complete = days[i].Hours >= 23
+	}
+	return days
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"context"
+	"sort"
+)
+
+// AlertQuantile selects which production estimate the alert criteria are evaluated against
+type AlertQuantile string
+
+const (
+	// AlertOnDeterministic uses the single deterministic forecast (default)
+	AlertOnDeterministic AlertQuantile = "deterministic"
+
+	// AlertOnP10 triggers when the 10th percentile is below threshold, i.e. at least
+	// 10% of ensemble members forecast low production (most sensitive)
+	AlertOnP10 AlertQuantile = "p10"
+
+	// AlertOnP50 triggers when the ensemble median is below threshold
+	AlertOnP50 AlertQuantile = "p50"
+
+	// AlertOnP90 triggers when the 90th percentile is below threshold, i.e. at least
+	// 90% of ensemble members forecast low production (most confident)
+	AlertOnP90 AlertQuantile = "p90"
+)
+
+// MinEnsembleMembers is the fewest members an hour needs before quantiles are reported
+const MinEnsembleMembers = 3
+
+// EnsembleForecastProvider defines the interface for fetching ensemble weather forecasts
+type EnsembleForecastProvider interface {
+	// GetEnsembleForecast retrieves every ensemble member's hourly forecast for given coordinates
+	GetEnsembleForecast(ctx context.Context, latitude, longitude float64) (*EnsembleForecast, error)
+}
+
+// EnsembleForecast holds the hourly forecasts of all ensemble members
+type EnsembleForecast struct {
+	Members []EnsembleMember
+}
+
+// EnsembleMember is one perturbed run of an ensemble model
+type EnsembleMember struct {
+	Name  string // Model and member, e.g. "icon_seamless/member07"
+	Hours []ForecastHour
+}
+
+// ProductionQuantiles summarises the spread of ensemble production for one hour (kW).
+// Production is below P90 in 90% of members and below P10 in 10% of them.
+type ProductionQuantiles struct {
+	P10     float64
+	P50     float64
+	P90     float64
+	Members int // Number of members the quantiles were computed from
+}
+
+// OutputAt returns the production estimate selected by the quantile.
+// Falls back to the deterministic estimate when no ensemble data is available for the hour.
+func (p SolarProduction) OutputAt(q AlertQuantile) float64 {
+	if p.Quantiles == nil {
+		return p.EstimatedOutputKW
+	}
+	switch q {
+	case AlertOnP10:
+		return p.Quantiles.P10
+	case AlertOnP50:
+		return p.Quantiles.P50
+	case AlertOnP90:
+		return p.Quantiles.P90
+	default:
+		return p.EstimatedOutputKW
+	}
+}
+
+// productionQuantiles runs the production model on every ensemble member and returns the
+// P10/P50/P90 production for each forecast hour, keyed by Unix time
+func (s *SolarForecastService) productionQuantiles(ensemble *EnsembleForecast) map[int64]ProductionQuantiles {
+	if ensemble == nil {
+		return nil
+	}
+
+	samples := make(map[int64][]float64)
+	for _, member := range ensemble.Members {
+		for _, hour := range member.Hours {
+			key := hour.Hour.Unix()
+			samples[key] = append(samples[key], s.calculateSolarProduction(hour).EstimatedOutputKW)
+		}
+	}
+
+	quantiles := make(map[int64]ProductionQuantiles, len(samples))
+	for key, values := range samples {
+		if len(values) < MinEnsembleMembers {
+			continue
+		}
+		sort.Float64s(values)
+		quantiles[key] = ProductionQuantiles{
+			P10:     quantile(values, 0.1),
+			P50:     quantile(values, 0.5),
+			P90:     quantile(values, 0.9),
+			Members: len(values),
+		}
+	}
+	return quantiles
+}
+
+// quantile returns the q-th quantile of sorted values using linear interpolation
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q * float64(len(sorted)-1)
+	lower := int(pos)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(lower)
+	return sorted[lower] + frac*(sorted[lower+1]-sorted[lower])
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// NotificationKind identifies what an episode notification was for
+type NotificationKind string
+
+const (
+	NotificationAlert    NotificationKind = "alert"
+	NotificationUpdate   NotificationKind = "update"
+	NotificationRecovery NotificationKind = "recovery"
+)
+
+// EpisodeNotification records one notification sent during an episode
+type EpisodeNotification struct {
+	Kind     NotificationKind
+	Channels []string // Channels the notification was delivered to
+	SentAt   time.Time
+}
+
+// AlertEpisode is one low production period, from the first alert until the
+// forecast no longer triggers any rule. An episode may span several days.
+type AlertEpisode struct {
+	ID            string
+	StartedAt     time.Time        // When the first alert was sent
+	ExpectedEnd   time.Time        // Forecast end of the low period: the recovery hour, or the last low hour
+	FiredRules    []string         // Every rule that fired during the episode, sorted
+	Severity      Severity         // Highest severity seen during the episode
+	Initial       AlertFingerprint // Forecast when the episode opened
+	Latest        AlertFingerprint // Forecast as of the last alert or update sent
+	Notifications []EpisodeNotification
+	ResolvedAt    time.Time // Zero while the episode is open
+}
+
+// NewAlertEpisode opens an episode for an analysis that triggered an alert
+func NewAlertEpisode(analysis *AlertAnalysis, now time.Time) AlertEpisode {
+	fingerprint := NewAlertFingerprint(analysis)
+	episode := AlertEpisode{
+		ID:        "ep-" + now.Format("20060102-150405"),
+		StartedAt: now,
+		Initial:   fingerprint,
+		Latest:    fingerprint,
+	}
+	episode.Observe(analysis)
+	return episode
+}
+
+// IsOpen reports whether the episode has not been resolved yet
+func (e *AlertEpisode) IsOpen() bool {
+	return e.ResolvedAt.IsZero()
+}
+
+// Observe folds a new analysis into the episode: fired rules, highest severity
+// and the expected end of the low period
+func (e *AlertEpisode) Observe(analysis *AlertAnalysis) {
+	known := make(map[string]bool, len(e.FiredRules))
+	for _, name := range e.FiredRules {
+		known[name] = true
+	}
+	for _, result := range analysis.FiredRules {
+		if !known[result.Name] {
+			known[result.Name] = true
+			e.FiredRules = append(e.FiredRules, result.Name)
+		}
+	}
+	sort.Strings(e.FiredRules)
+
+	if analysis.Severity.Rank() > e.Severity.Rank() {
+		e.Severity = analysis.Severity
+	}
+
+	if analysis.HasRecovery {
+		e.ExpectedEnd = analysis.RecoveryHour
+	} else if last := NewAlertFingerprint(analysis).LastLowHour; !last.IsZero() {
+		e.ExpectedEnd = last
+	}
+}
+
+// RecordNotification appends a sent notification to the episode
+func (e *AlertEpisode) RecordNotification(kind NotificationKind, channels []string, at time.Time) {
+	e.Notifications = append(e.Notifications, EpisodeNotification{Kind: kind, Channels: channels, SentAt: at})
+}
+
+// NextNotificationID returns the ID of the episode's next notification of the
+// kind. Updates are numbered, as an episode may send several.
+func (e *AlertEpisode) NextNotificationID(kind NotificationKind) string {
+	if kind == NotificationUpdate {
+		return fmt.Sprintf("%s/%s/%d", e.ID, kind, e.NotificationCount(NotificationUpdate, time.Time{})+1)
+	}
+	return e.ID + "/" + string(kind)
+}
+
+// NotificationCount returns how many notifications of a kind were sent at or after since
+func (e *AlertEpisode) NotificationCount(kind NotificationKind, since time.Time) int {
+	count := 0
+	for _, n := range e.Notifications {
+		if n.Kind == kind && !n.SentAt.Before(since) {
+			count++
+		}
+	}
+	return count
+}
+
+// DeliveredTo reports whether any notification in the episode went to the channel
+func (e *AlertEpisode) DeliveredTo(channel string) bool {
+	for _, n := range e.Notifications {
+		for _, c := range n.Channels {
+			if c == channel {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Superseded reports whether the episode's low period has passed and the
+// analysis describes a separate, later one
+func (e *AlertEpisode) Superseded(analysis *AlertAnalysis, now time.Time) bool {
+	if e.ExpectedEnd.IsZero() || now.Before(e.ExpectedEnd) {
+		return false
+	}
+	first := NewAlertFingerprint(analysis).FirstLowHour
+	return !first.IsZero() && first.After(e.ExpectedEnd)
+}
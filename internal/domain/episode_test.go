@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAlertEpisodeLifecycle(t *testing.T) {
+	// Low production throughout the next six hours, or a sunny forecast
+	forecast := func(low bool) *ForecastData {
+		base := time.Now().Truncate(time.Hour).Add(time.Hour)
+		ghi := 800.0
+		if low {
+			ghi = 200
+		}
+		data := &ForecastData{}
+		for h := 0; h < 6; h++ {
+			data.Hours = append(data.Hours, ForecastHour{
+				Hour:                       base.Add(time.Duration(h) * time.Hour),
+				GlobalHorizontalIrradiance: ghi,
+				Temperature:                25,
+			})
+		}
+		return data
+	}
+
+	weather := &stubWeather{}
+	email := &recordingEmail{}
+	state := &memoryState{}
+	service := NewSolarForecastService(
+		&Config{
+			TestMode:                   true,
+			RatedCapacityKW:            5.0,
+			InverterEfficiency:         1.0,
+			ProductionAlertThresholdKW: 2.0,
+			DurationThresholdHours:     6,
+			DaylightGHIThreshold:       50.0,
+			SeverityRoutes:             SeverityRoutes{SeverityWarning: {ChannelEmail}},
+		},
+		weather, nil, testNotifiers(email, nil), nil, state, nil, &mockLogger{},
+	)
+
+	runs := []struct {
+		name           string
+		low            bool
+		wantAlerts     int
+		wantRecoveries int
+		wantOpen       bool
+		wantClosed     int
+	}{
+		{"low period opens an episode", true, 1, 0, true, 0},
+		{"still low: no repeat alert", true, 1, 0, true, 0},
+		{"recovered: recovery email closes the episode", false, 1, 1, false, 1},
+		{"still fine: no second recovery", false, 1, 1, false, 1},
+		{"low again: new episode", true, 2, 1, true, 1},
+	}
+
+	for _, run := range runs {
+		weather.forecast = forecast(run.low)
+		if err := service.CheckAndAlert(context.Background()); err != nil {
+			t.Fatalf("%s: %v", run.name, err)
+		}
+		if email.alerts != run.wantAlerts || email.recoveries != run.wantRecoveries {
+			t.Errorf("%s: alerts = %d, recoveries = %d, want %d and %d",
+				run.name, email.alerts, email.recoveries, run.wantAlerts, run.wantRecoveries)
+		}
+		if (state.active != nil) != run.wantOpen || len(state.closed) != run.wantClosed {
+			t.Errorf("%s: open = %v, closed = %d, want %v and %d",
+				run.name, state.active != nil, len(state.closed), run.wantOpen, run.wantClosed)
+		}
+	}
+
+	closed := state.closed[0]
+	if closed.ResolvedAt.IsZero() || closed.NotificationCount(NotificationRecovery, time.Time{}) != 1 {
+		t.Errorf("closed episode = %+v, want resolved with one recovery notification", closed)
+	}
+}
+
+func TestAlertEpisodeSuperseded(t *testing.T) {
+	day := time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC)
+	episode := AlertEpisode{ExpectedEnd: day.Add(16 * time.Hour)}
+
+	analysisFrom := func(first time.Time) *AlertAnalysis {
+		return &AlertAnalysis{FiredRules: []RuleResult{{
+			Name:     "low",
+			Fired:    true,
+			Evidence: []SolarProduction{{Hour: first}, {Hour: first.Add(time.Hour)}},
+		}}}
+	}
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		first time.Time
+		want  bool
+	}{
+		{"before expected end", day.Add(12 * time.Hour), day.AddDate(0, 0, 1).Add(9 * time.Hour), false},
+		{"same low period continues past expected end", day.Add(17 * time.Hour), day.Add(15 * time.Hour), false},
+		{"later low period after expected end", day.Add(17 * time.Hour), day.AddDate(0, 0, 1).Add(9 * time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := episode.Superseded(analysisFrom(tt.first), tt.now); got != tt.want {
+				t.Errorf("Superseded = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertEpisodeNextNotificationID(t *testing.T) {
+	sent := time.Date(2025, 6, 21, 9, 0, 0, 0, time.UTC)
+	episode := AlertEpisode{ID: "ep-20250621-090000"}
+	episode.RecordNotification(NotificationAlert, []string{ChannelEmail}, sent)
+	episode.RecordNotification(NotificationUpdate, []string{ChannelEmail}, sent.Add(2*time.Hour))
+
+	tests := map[NotificationKind]string{
+		NotificationAlert:    "ep-20250621-090000/alert",
+		NotificationUpdate:   "ep-20250621-090000/update/2",
+		NotificationRecovery: "ep-20250621-090000/recovery",
+	}
+	for kind, want := range tests {
+		if got := episode.NextNotificationID(kind); got != want {
+			t.Errorf("NextNotificationID(%s) = %q, want %q", kind, got, want)
+		}
+	}
+}
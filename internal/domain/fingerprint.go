@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Default alert update settings
+const (
+	DefaultAlertUpdateHourTolerance = 2
+	DefaultAlertUpdateKWTolerance   = 0.5
+	DefaultAlertUpdateMaxPerDay     = 2
+)
+
+// AlertFingerprint summarises a sent alert so later runs can tell whether the
+// forecast has changed enough to send an update
+type AlertFingerprint struct {
+	FirstLowHour time.Time // Earliest hour behind any fired rule
+	LastLowHour  time.Time // Latest hour behind any fired rule
+	LowHourCount int       // Distinct hours behind the fired rules
+	MinOutputKW  float64   // Lowest output among those hours, on the alert's production basis
+	Severity     Severity
+	Rules        []string // Names of the fired rules, sorted
+}
+
+// AlertUpdate describes how an alert changed since it was last sent
+type AlertUpdate struct {
+	Previous AlertFingerprint
+	Changes  []string // Human-readable list of material changes
+}
+
+// UpdateTolerance is how much a fingerprint may change before an update is sent
+type UpdateTolerance struct {
+	Hours int     // Change in low hour count, or shift of the window start or end
+	KW    float64 // Change in minimum output
+}
+
+// NewAlertFingerprint builds the fingerprint of an analysis from its fired rules
+func NewAlertFingerprint(analysis *AlertAnalysis) AlertFingerprint {
+	fp := AlertFingerprint{Severity: analysis.Severity, MinOutputKW: math.Inf(1)}
+
+	seen := make(map[int64]bool)
+	for _, result := range analysis.FiredRules {
+		fp.Rules = append(fp.Rules, result.Name)
+		for _, prod := range result.Evidence {
+			if seen[prod.Hour.Unix()] {
+				continue
+			}
+			seen[prod.Hour.Unix()] = true
+			fp.LowHourCount++
+			if fp.FirstLowHour.IsZero() || prod.Hour.Before(fp.FirstLowHour) {
+				fp.FirstLowHour = prod.Hour
+			}
+			if prod.Hour.After(fp.LastLowHour) {
+				fp.LastLowHour = prod.Hour
+			}
+			fp.MinOutputKW = math.Min(fp.MinOutputKW, prod.OutputAt(analysis.AlertQuantile))
+		}
+	}
+	if fp.LowHourCount == 0 {
+		fp.MinOutputKW = 0
+	}
+	sort.Strings(fp.Rules)
+	return fp
+}
+
+// IsZero reports whether the fingerprint is unset, as for an episode migrated
+// from a state format that did not record one
+func (f AlertFingerprint) IsZero() bool {
+	return f.Severity == "" && f.LowHourCount == 0 && len(f.Rules) == 0
+}
+
+// Changes lists the material differences from a previous fingerprint in the
+// locale's language. An empty result means the alert is unchanged within the tolerance.
+func (f AlertFingerprint) Changes(previous AlertFingerprint, tolerance UpdateTolerance, locale *Locale) []string {
+	var changes []string
+
+	if f.Severity != previous.Severity {
+		changes = append(changes, locale.Sprintf("Severity %s → %s", locale.T(string(previous.Severity)), locale.T(string(f.Severity))))
+	}
+	if abs(f.LowHourCount-previous.LowHourCount) >= tolerance.Hours {
+		changes = append(changes, locale.Sprintf("Low hours %d → %d", previous.LowHourCount, f.LowHourCount))
+	}
+	if hoursApart(f.FirstLowHour, previous.FirstLowHour) >= tolerance.Hours ||
+		hoursApart(f.LastLowHour, previous.LastLowHour) >= tolerance.Hours {
+		changes = append(changes, locale.Sprintf("Window %s → %s",
+			formatWindow(previous.FirstLowHour, previous.LastLowHour, locale), formatWindow(f.FirstLowHour, f.LastLowHour, locale)))
+	}
+	if math.Abs(f.MinOutputKW-previous.MinOutputKW) >= tolerance.KW {
+		changes = append(changes, locale.Sprintf("Minimum output %.1f → %.1f kW", previous.MinOutputKW, f.MinOutputKW))
+	}
+
+	previousRules := make(map[string]bool, len(previous.Rules))
+	for _, name := range previous.Rules {
+		previousRules[name] = true
+	}
+	for _, name := range f.Rules {
+		if !previousRules[name] {
+			changes = append(changes, locale.Sprintf("New rule fired: %s", name))
+		}
+	}
+
+	return changes
+}
+
+// hoursApart returns the whole hours between two times
+func hoursApart(a, b time.Time) int {
+	return int(math.Abs(a.Sub(b).Hours()))
+}
+
+// formatWindow formats an hour range for change messages
+func formatWindow(first, last time.Time, locale *Locale) string {
+	if first.IsZero() {
+		return locale.T("none")
+	}
+	return locale.FormatTime(first, "Mon 15:04") + "-" + last.Format("15:04")
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlertFingerprintChanges(t *testing.T) {
+	base := time.Date(2025, 6, 21, 9, 0, 0, 0, time.UTC)
+	previous := AlertFingerprint{
+		FirstLowHour: base,
+		LastLowHour:  base.Add(5 * time.Hour),
+		LowHourCount: 6,
+		MinOutputKW:  1.2,
+		Severity:     SeverityWarning,
+		Rules:        []string{"low_production_duration"},
+	}
+	tolerance := UpdateTolerance{Hours: 2, KW: 0.5}
+
+	tests := []struct {
+		name   string
+		modify func(fp *AlertFingerprint)
+		want   []string
+	}{
+		{"unchanged", func(fp *AlertFingerprint) {}, nil},
+		{"within tolerance", func(fp *AlertFingerprint) {
+			fp.LowHourCount = 7
+			fp.LastLowHour = fp.LastLowHour.Add(time.Hour)
+			fp.MinOutputKW = 1.0
+		}, nil},
+		{"period grows", func(fp *AlertFingerprint) {
+			fp.LowHourCount = 11
+			fp.LastLowHour = fp.LastLowHour.Add(5 * time.Hour)
+		}, []string{"Low hours 6 → 11", "Window"}},
+		{"output drops", func(fp *AlertFingerprint) { fp.MinOutputKW = 0.4 }, []string{"Minimum output 1.2 → 0.4 kW"}},
+		{"severity and rules", func(fp *AlertFingerprint) {
+			fp.Severity = SeverityCritical
+			fp.Rules = []string{"dark_day", "low_production_duration"}
+		}, []string{"Severity warning → critical", "New rule fired: dark_day"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := previous
+			current.Rules = append([]string{}, previous.Rules...)
+			tt.modify(&current)
+
+			changes := current.Changes(previous, tolerance, nil)
+			if len(changes) != len(tt.want) {
+				t.Fatalf("changes = %q, want %d entries", changes, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(changes[i], want) {
+					t.Errorf("change %d = %q, want prefix %q", i, changes[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckAndAlertSendsUpdateWhenAlertWorsens(t *testing.T) {
+	forecastWithLowHours := func(low int) *ForecastData {
+		base := time.Date(2025, 6, 21, 7, 0, 0, 0, time.UTC)
+		forecast := &ForecastData{}
+		for h := 0; h < 12; h++ {
+			ghi := 800.0
+			if h < low {
+				ghi = 200
+			}
+			forecast.Hours = append(forecast.Hours, ForecastHour{
+				Hour:                       base.Add(time.Duration(h) * time.Hour),
+				GlobalHorizontalIrradiance: ghi,
+				Temperature:                25,
+			})
+		}
+		return forecast
+	}
+
+	weather := &stubWeather{forecast: forecastWithLowHours(6)}
+	email := &recordingEmail{}
+	state := &memoryState{}
+	service := NewSolarForecastService(
+		&Config{
+			TestMode:                   true,
+			RatedCapacityKW:            5.0,
+			InverterEfficiency:         1.0,
+			ProductionAlertThresholdKW: 2.0,
+			DurationThresholdHours:     6,
+			DaylightGHIThreshold:       50.0,
+			SeverityRoutes:             SeverityRoutes{SeverityWarning: {ChannelEmail}, SeverityCritical: {ChannelEmail}},
+			AlertUpdateHourTolerance:   2,
+			AlertUpdateKWTolerance:     0.5,
+			AlertUpdateMaxPerDay:       1,
+		},
+		weather, nil, testNotifiers(email, nil), nil, state, nil, &mockLogger{},
+	)
+
+	runs := []struct {
+		lowHours    int
+		wantEmails  int
+		wantUpdates int
+	}{
+		{6, 1, 0},  // First alert
+		{7, 1, 0},  // One more hour is within tolerance
+		{11, 2, 1}, // Low period grows: update
+		{12, 2, 1}, // Daily update limit reached
+	}
+
+	for i, run := range runs {
+		weather.forecast = forecastWithLowHours(run.lowHours)
+		if err := service.CheckAndAlert(context.Background()); err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+		updates := state.active.NotificationCount(NotificationUpdate, time.Time{})
+		if email.alerts != run.wantEmails || updates != run.wantUpdates {
+			t.Errorf("run %d (%d low hours): emails = %d, updates = %d, want %d and %d",
+				i, run.lowHours, email.alerts, updates, run.wantEmails, run.wantUpdates)
+		}
+	}
+}
+
+func TestCheckAndAlertSeedsMissingBaseline(t *testing.T) {
+	forecast := func(ghi float64, hours int) *ForecastData {
+		base := time.Now().Truncate(time.Hour).Add(time.Hour)
+		data := &ForecastData{}
+		for h := 0; h < hours; h++ {
+			data.Hours = append(data.Hours, ForecastHour{
+				Hour:                       base.Add(time.Duration(h) * time.Hour),
+				GlobalHorizontalIrradiance: ghi,
+				Temperature:                25,
+			})
+		}
+		return data
+	}
+
+	// An episode migrated from the per-day state format: its alert went out, but no fingerprint was kept
+	started := time.Now().Add(-2 * time.Hour)
+	state := &memoryState{active: &AlertEpisode{
+		ID:            "ep-legacy",
+		StartedAt:     started,
+		Notifications: []EpisodeNotification{{Kind: NotificationAlert, Channels: []string{ChannelEmail}, SentAt: started}},
+	}}
+	weather := &stubWeather{forecast: forecast(200, 6)}
+	email := &recordingEmail{}
+	service := NewSolarForecastService(
+		&Config{
+			TestMode:                   true,
+			RatedCapacityKW:            5.0,
+			InverterEfficiency:         1.0,
+			ProductionAlertThresholdKW: 2.0,
+			DurationThresholdHours:     6,
+			DaylightGHIThreshold:       50.0,
+			SeverityRoutes:             SeverityRoutes{SeverityWarning: {ChannelEmail}},
+			AlertUpdateHourTolerance:   2,
+			AlertUpdateKWTolerance:     0.5,
+			AlertUpdateMaxPerDay:       2,
+		},
+		weather, nil, testNotifiers(email, nil), nil, state, nil, &mockLogger{},
+	)
+
+	if err := service.CheckAndAlert(context.Background()); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if email.alerts != 0 {
+		t.Errorf("emails = %d, want no update for an episode without a baseline", email.alerts)
+	}
+	if state.active.Latest.IsZero() || state.active.Latest.LowHourCount != 6 || state.active.Initial.LowHourCount != 6 {
+		t.Errorf("fingerprints = %+v, %+v; want the current forecast recorded", state.active.Initial, state.active.Latest)
+	}
+
+	// Later changes are measured against the recorded baseline
+	weather.forecast = forecast(200, 11)
+	if err := service.CheckAndAlert(context.Background()); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if email.alerts != 1 || state.active.NotificationCount(NotificationUpdate, time.Time{}) != 1 {
+		t.Errorf("emails = %d, want an update once the low period grows", email.alerts)
+	}
+}
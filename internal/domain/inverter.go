@@ -0,0 +1,73 @@
+package domain
+
+import "math"
+
+// EfficiencyPoint is one point of an inverter part-load efficiency curve
+type EfficiencyPoint struct {
+	LoadFraction float64 // Output as a fraction of the inverter's AC rating (0.1 = 10% load)
+	Efficiency   float64 // DC to AC efficiency at that load (0-1)
+}
+
+// InverterEfficiencyAt interpolates the efficiency curve at a load fraction.
+// Loads outside the curve use the nearest end point. The curve must be sorted by load.
+func InverterEfficiencyAt(curve []EfficiencyPoint, loadFraction float64) float64 {
+	if len(curve) == 0 {
+		return 0
+	}
+	if loadFraction <= curve[0].LoadFraction {
+		return curve[0].Efficiency
+	}
+	for i := 1; i < len(curve); i++ {
+		if loadFraction <= curve[i].LoadFraction {
+			lo, hi := curve[i-1], curve[i]
+			ratio := (loadFraction - lo.LoadFraction) / (hi.LoadFraction - lo.LoadFraction)
+			return lo.Efficiency + ratio*(hi.Efficiency-lo.Efficiency)
+		}
+	}
+	return curve[len(curve)-1].Efficiency
+}
+
+// inverterOutput converts total DC power to AC power, applying the part-load efficiency
+// and the AC output limit. Returns the AC power and the power lost to clipping (kW).
+func (s *SolarForecastService) inverterOutput(dcKW, ratedDCKW float64) (acKW, clippedKW float64) {
+	if dcKW <= 0 {
+		return 0, 0
+	}
+
+	efficiency := s.config.InverterEfficiency
+	if len(s.config.InverterEfficiencyCurve) > 0 {
+		// Part-load is measured against the AC rating when known, otherwise the array size
+		reference := s.config.InverterACLimitKW
+		if reference <= 0 {
+			reference = ratedDCKW
+		}
+		efficiency = inverterEfficiencyForDC(s.config.InverterEfficiencyCurve, dcKW, reference, s.config.InverterACLimitKW > 0)
+	}
+
+	acKW = dcKW * efficiency
+	if s.config.InverterACLimitKW > 0 && acKW > s.config.InverterACLimitKW {
+		clippedKW = acKW - s.config.InverterACLimitKW
+		acKW = s.config.InverterACLimitKW
+	}
+	return acKW, clippedKW
+}
+
+// inverterEfficiencyForDC finds the curve efficiency for a DC input. The curve is indexed by
+// AC load, which itself depends on the efficiency, so the load is refined from the DC ratio
+// until it settles. When the reference is the AC limit, the load cannot exceed 100%.
+func inverterEfficiencyForDC(curve []EfficiencyPoint, dcKW, reference float64, capAtLimit bool) float64 {
+	load := dcKW / reference
+	efficiency := InverterEfficiencyAt(curve, load)
+	for i := 0; i < 10; i++ {
+		next := dcKW * efficiency / reference
+		if capAtLimit && next > 1 {
+			next = 1
+		}
+		if math.Abs(next-load) < 1e-6 {
+			break
+		}
+		load = next
+		efficiency = InverterEfficiencyAt(curve, load)
+	}
+	return efficiency
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// TranspositionModel selects how horizontal irradiance is projected onto a tilted panel
+type TranspositionModel string
+
+const (
+	// TranspositionIsotropic treats diffuse sky radiation as uniform across the sky dome (Liu-Jordan)
+	TranspositionIsotropic TranspositionModel = "isotropic"
+
+	// TranspositionHayDavies adds a circumsolar diffuse component around the sun's disc
+	TranspositionHayDavies TranspositionModel = "hay-davies"
+)
+
+// Irradiance model constants
+const (
+	// SolarConstant is the mean extraterrestrial irradiance at 1 AU (W/m²)
+	SolarConstant = 1367.0
+
+	// DefaultGroundAlbedo is the typical reflectance of grass/urban ground
+	DefaultGroundAlbedo = 0.2
+
+	// minBeamElevationDeg is the sun elevation below which all radiation is treated as diffuse.
+	// Near the horizon the GHI/cos(zenith) ratio explodes and beam estimates become meaningless.
+	minBeamElevationDeg = 3.0
+)
+
+// IrradianceComponents splits global horizontal irradiance into beam and diffuse parts
+type IrradianceComponents struct {
+	GHI float64 // Global horizontal irradiance (W/m²)
+	DNI float64 // Direct normal irradiance (W/m²)
+	DHI float64 // Diffuse horizontal irradiance (W/m²)
+}
+
+// ExtraterrestrialIrradiance returns the irradiance at the top of the atmosphere for a date (W/m²),
+// corrected for the eccentricity of Earth's orbit
+func ExtraterrestrialIrradiance(t time.Time) float64 {
+	dayAngle := 2 * math.Pi * float64(t.YearDay()) / 365.0
+	return SolarConstant * (1 + 0.033*math.Cos(dayAngle))
+}
+
+// DecomposeGHI estimates beam and diffuse components from GHI alone using the Erbs correlation.
+// Used when the weather provider does not supply DNI/DHI directly.
+func DecomposeGHI(ghi float64, pos SolarPosition, t time.Time) IrradianceComponents {
+	if ghi <= 0 {
+		return IrradianceComponents{}
+	}
+	if pos.ElevationDeg < minBeamElevationDeg {
+		return IrradianceComponents{GHI: ghi, DHI: ghi}
+	}
+
+	cosZenith := math.Cos(pos.ZenithDeg() * math.Pi / 180)
+
+	// Clearness index: fraction of extraterrestrial horizontal irradiance reaching the ground
+	kt := ghi / (ExtraterrestrialIrradiance(t) * cosZenith)
+	kt = math.Max(0, math.Min(1, kt))
+
+	var kd float64
+	switch {
+	case kt <= 0.22:
+		kd = 1 - 0.09*kt
+	case kt <= 0.80:
+		kd = 0.9511 - 0.1604*kt + 4.388*kt*kt - 16.638*kt*kt*kt + 12.336*kt*kt*kt*kt
+	default:
+		kd = 0.165
+	}
+
+	dhi := kd * ghi
+	return IrradianceComponents{
+		GHI: ghi,
+		DNI: (ghi - dhi) / cosZenith,
+		DHI: dhi,
+	}
+}
+
+// PlaneOfArrayIrradiance projects irradiance components onto a panel with the given tilt and
+// azimuth (compass degrees, 180 = south) and returns the total irradiance on the panel (W/m²).
+//
+// POA = beam + sky diffuse + ground-reflected
+//   - beam:   DNI × cos(angle of incidence)
+//   - sky:    isotropic DHI × (1 + cos β)/2, or Hay-Davies with a circumsolar share
+//   - ground: GHI × albedo × (1 − cos β)/2
+func PlaneOfArrayIrradiance(c IrradianceComponents, pos SolarPosition, tiltDeg, azimuthDeg, albedo float64, model TranspositionModel, t time.Time) float64 {
+	if c.GHI <= 0 {
+		return 0
+	}
+
+	tilt := tiltDeg * math.Pi / 180
+	zenith := pos.ZenithDeg() * math.Pi / 180
+	cosZenith := math.Cos(zenith)
+	cosTilt := math.Cos(tilt)
+
+	// Angle of incidence between sun rays and panel normal
+	cosAOI := cosZenith*cosTilt +
+		math.Sin(zenith)*math.Sin(tilt)*math.Cos((pos.AzimuthDeg-azimuthDeg)*math.Pi/180)
+	if cosAOI < 0 || pos.ElevationDeg <= 0 {
+		cosAOI = 0 // Sun behind the panel or below the horizon
+	}
+
+	beam := c.DNI * cosAOI
+	ground := c.GHI * albedo * (1 - cosTilt) / 2
+	isotropicView := (1 + cosTilt) / 2
+
+	var sky float64
+	switch model {
+	case TranspositionIsotropic:
+		sky = c.DHI * isotropicView
+	default: // Hay-Davies
+		// Anisotropy index: share of diffuse light treated as circumsolar (follows the beam)
+		anisotropy := c.DNI / ExtraterrestrialIrradiance(t)
+		anisotropy = math.Max(0, math.Min(1, anisotropy))
+		// Ratio of tilted to horizontal beam; floor cos(zenith) to avoid blow-up near sunrise
+		rb := cosAOI / math.Max(cosZenith, 0.01745)
+		sky = c.DHI * (anisotropy*rb + (1-anisotropy)*isotropicView)
+	}
+
+	poa := beam + sky + ground
+	if poa < 0 {
+		return 0
+	}
+	return poa
+}
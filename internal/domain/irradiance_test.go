@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCalculateSolarPosition(t *testing.T) {
+	tests := []struct {
+		name          string
+		time          time.Time
+		latitude      float64
+		longitude     float64
+		wantElevation float64
+		wantAzimuth   float64
+	}{
+		{
+			name:          "equinox solar noon in Valencia - sun due south",
+			time:          time.Date(2025, 3, 20, 12, 9, 0, 0, time.UTC),
+			latitude:      39.47,
+			longitude:     -0.38,
+			wantElevation: 50.5,
+			wantAzimuth:   180,
+		},
+		{
+			name:          "June solstice noon at the equator - sun to the north",
+			time:          time.Date(2025, 6, 21, 12, 2, 0, 0, time.UTC),
+			latitude:      0,
+			longitude:     0,
+			wantElevation: 66.6,
+			wantAzimuth:   0,
+		},
+		{
+			name:          "equinox morning in Valencia - sun in the east",
+			time:          time.Date(2025, 3, 20, 8, 0, 0, 0, time.UTC),
+			latitude:      39.47,
+			longitude:     -0.38,
+			wantElevation: 22.5,
+			wantAzimuth:   110,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos := CalculateSolarPosition(tt.time, tt.latitude, tt.longitude)
+
+			if math.Abs(pos.ElevationDeg-tt.wantElevation) > 1.5 {
+				t.Errorf("ElevationDeg = %.2f, want %.2f ± 1.5", pos.ElevationDeg, tt.wantElevation)
+			}
+
+			// Compare azimuth on the circle (0 and 360 are the same bearing)
+			diff := math.Abs(math.Mod(pos.AzimuthDeg-tt.wantAzimuth+540, 360) - 180)
+			if diff > 3 {
+				t.Errorf("AzimuthDeg = %.2f, want %.2f ± 3", pos.AzimuthDeg, tt.wantAzimuth)
+			}
+		})
+	}
+}
+
+func TestPlaneOfArrayIrradiance(t *testing.T) {
+	noon := time.Date(2025, 12, 21, 12, 0, 0, 0, time.UTC)
+	winterNoon := SolarPosition{ElevationDeg: 27, AzimuthDeg: 180}
+	ghi := 450.0
+	components := DecomposeGHI(ghi, winterNoon, noon)
+
+	t.Run("decomposition is consistent with GHI", func(t *testing.T) {
+		cosZenith := math.Cos(winterNoon.ZenithDeg() * math.Pi / 180)
+		reconstructed := components.DNI*cosZenith + components.DHI
+		if math.Abs(reconstructed-ghi) > 0.01 {
+			t.Errorf("DNI·cos(z) + DHI = %.2f, want %.2f", reconstructed, ghi)
+		}
+	})
+
+	for _, model := range []TranspositionModel{TranspositionIsotropic, TranspositionHayDavies} {
+		t.Run(string(model)+" flat panel receives GHI", func(t *testing.T) {
+			poa := PlaneOfArrayIrradiance(components, winterNoon, 0, 180, DefaultGroundAlbedo, model, noon)
+			if math.Abs(poa-ghi) > 0.01 {
+				t.Errorf("POA = %.2f, want %.2f", poa, ghi)
+			}
+		})
+
+		t.Run(string(model)+" south tilt gains in winter", func(t *testing.T) {
+			poa := PlaneOfArrayIrradiance(components, winterNoon, 30, 180, DefaultGroundAlbedo, model, noon)
+			if poa <= ghi*1.2 {
+				t.Errorf("POA = %.2f, want well above GHI %.2f for a south-facing tilt", poa, ghi)
+			}
+		})
+
+		t.Run(string(model)+" north tilt loses in winter", func(t *testing.T) {
+			poa := PlaneOfArrayIrradiance(components, winterNoon, 30, 0, DefaultGroundAlbedo, model, noon)
+			if poa >= ghi {
+				t.Errorf("POA = %.2f, want below GHI %.2f for a north-facing tilt", poa, ghi)
+			}
+		})
+	}
+
+	t.Run("sun below horizon leaves only diffuse light", func(t *testing.T) {
+		dusk := SolarPosition{ElevationDeg: -2, AzimuthDeg: 250}
+		c := DecomposeGHI(20, dusk, noon)
+		if c.DNI != 0 || c.DHI != 20 {
+			t.Errorf("components = %+v, want all diffuse", c)
+		}
+		poa := PlaneOfArrayIrradiance(c, dusk, 30, 135, DefaultGroundAlbedo, TranspositionHayDavies, noon)
+		if poa <= 0 || poa > 20 {
+			t.Errorf("POA = %.2f, want between 0 and 20", poa)
+		}
+	})
+}
+
+func TestCellTemperature(t *testing.T) {
+	base := CellTemperatureConfig{
+		NOCT:         DefaultNOCT,
+		FaimanU0:     DefaultFaimanU0,
+		FaimanU1:     DefaultFaimanU1,
+		SandiaA:      DefaultSandiaA,
+		SandiaB:      DefaultSandiaB,
+		SandiaDeltaT: DefaultSandiaDeltaT,
+	}
+
+	tests := []struct {
+		model   CellTemperatureModel
+		wantMin float64
+		wantMax float64
+	}{
+		{model: CellTempModelAir, wantMin: 30, wantMax: 30},
+		{model: CellTempModelNOCT, wantMin: 55, wantMax: 58},   // 30 + 25/800·800 = 55 at 1 m/s
+		{model: CellTempModelFaiman, wantMin: 55, wantMax: 57}, // 30 + 800/(25+6.84) ≈ 55.1
+		{model: CellTempModelSandia, wantMin: 52, wantMax: 55}, // 30 + 800·e^(-3.635) + 2.4 ≈ 53.5
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.model), func(t *testing.T) {
+			cfg := base
+			cfg.Model = tt.model
+			got := CellTemperature(cfg, 30, 800, 1)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("CellTemperature = %.2f, want between %.1f and %.1f", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+
+	t.Run("wind cools the cells", func(t *testing.T) {
+		cfg := base
+		cfg.Model = CellTempModelFaiman
+		calm := CellTemperature(cfg, 30, 800, 0)
+		windy := CellTemperature(cfg, 30, 800, 8)
+		if windy >= calm {
+			t.Errorf("windy = %.2f, calm = %.2f, want windy < calm", windy, calm)
+		}
+	})
+
+	t.Run("no irradiance means air temperature", func(t *testing.T) {
+		cfg := base
+		cfg.Model = CellTempModelSandia
+		if got := CellTemperature(cfg, 12, 0, 3); got != 12 {
+			t.Errorf("CellTemperature = %.2f, want 12", got)
+		}
+	})
+}
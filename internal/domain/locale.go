@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is the locale used when none is configured
+const DefaultLocale = "en"
+
+// Locale translates and formats user-facing text. Messages are looked up by
+// their English text, so anything missing from a catalog stays in English.
+// A nil Locale is English.
+type Locale struct {
+	Tag         string
+	Name        string            // Name of the language in itself
+	decimal     string            // Decimal separator
+	days        [7]string         // Weekday names, Sunday first
+	shortDays   [7]string         // Abbreviated weekday names, Sunday first
+	months      [12]string        // Month names, January first
+	shortMonths [12]string        // Abbreviated month names, January first
+	messages    map[string]string // English text or format -> translation
+}
+
+// locales are the bundled catalogs by tag
+var locales = map[string]*Locale{
+	"en": {Tag: "en", Name: "English", decimal: "."},
+	"es": spanish,
+	"it": italian,
+	"de": german,
+	"fr": french,
+}
+
+// LookupLocale returns the locale for a tag such as "es" or "de-AT". Region
+// suffixes fall back to the language.
+func LookupLocale(tag string) (*Locale, error) {
+	if tag == "" {
+		tag = DefaultLocale
+	}
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	if locale, ok := locales[tag]; ok {
+		return locale, nil
+	}
+	if language, _, found := strings.Cut(tag, "-"); found {
+		if locale, ok := locales[language]; ok {
+			return locale, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported locale %q (supported: %s)", tag, strings.Join(SupportedLocales(), ", "))
+}
+
+// SupportedLocales returns the tags of the bundled locales, sorted
+func SupportedLocales() []string {
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// T translates a message
+func (l *Locale) T(message string) string {
+	if l == nil {
+		return message
+	}
+	if translated, ok := l.messages[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf translates the format and formats the arguments, writing
+// floating-point numbers with the locale's decimal separator
+func (l *Locale) Sprintf(format string, args ...any) string {
+	format = l.T(format)
+	if l == nil || l.decimal == "." {
+		return fmt.Sprintf(format, args...)
+	}
+	localized := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case float64:
+			localized[i] = localizedNumber{value: v, decimal: l.decimal}
+		case float32:
+			localized[i] = localizedNumber{value: float64(v), decimal: l.decimal}
+		default:
+			localized[i] = arg
+		}
+	}
+	return fmt.Sprintf(format, localized...)
+}
+
+// FormatTime formats t like time.Format after translating the layout, with
+// weekday and month names in the locale's language
+func (l *Locale) FormatTime(t time.Time, layout string) string {
+	layout = l.T(layout)
+	if l == nil || l.days[0] == "" {
+		return t.Format(layout)
+	}
+
+	// Format the layout piecewise so translated names are never read as layout elements
+	names := []struct {
+		element string
+		value   string
+	}{
+		{"Monday", l.days[t.Weekday()]},
+		{"Mon", l.shortDays[t.Weekday()]},
+		{"January", l.months[t.Month()-1]},
+		{"Jan", l.shortMonths[t.Month()-1]},
+	}
+	var b strings.Builder
+	for layout != "" {
+		index, element, value := len(layout), "", ""
+		for _, name := range names {
+			// Longer names come first, so "Monday" wins over "Mon" at the same index
+			if i := strings.Index(layout, name.element); i >= 0 && i < index {
+				index, element, value = i, name.element, name.value
+			}
+		}
+		if index > 0 {
+			b.WriteString(t.Format(layout[:index]))
+		}
+		b.WriteString(value)
+		layout = layout[min(index+len(element), len(layout)):]
+	}
+	return b.String()
+}
+
+// localizedNumber formats a float with a locale's decimal separator
+type localizedNumber struct {
+	value   float64
+	decimal string
+}
+
+// Format implements fmt.Formatter
+func (n localizedNumber) Format(f fmt.State, verb rune) {
+	formatted := fmt.Sprintf(fmt.FormatString(f, verb), n.value)
+	fmt.Fprint(f, strings.Replace(formatted, ".", n.decimal, 1))
+}
@@ -0,0 +1,188 @@
+package domain
+
+// german is the German (de) catalog
+var german = &Locale{
+	Tag:         "de",
+	Name:        "Deutsch",
+	decimal:     ",",
+	days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	shortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	shortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+	messages: map[string]string{
+		// Date layouts
+		"Jan 2":                         "2. Jan",
+		"Mon Jan 2":                     "Mon 2. Jan",
+		"Mon Jan 2, 15:04":              "Mon 2. Jan, 15:04",
+		"Mon 15:04":                     "Mon 15:04",
+		"Monday, January 2 15:04 MST":   "Monday, 2. January 15:04 MST",
+		"Monday, January 2 • 15:04 MST": "Monday, 2. January • 15:04 MST",
+		"Today 15:04":                   "Heute 15:04",
+		"Tomorrow 15:04":                "Morgen 15:04",
+
+		// Severities and weather
+		"info":             "Info",
+		"warning":          "Warnung",
+		"critical":         "kritisch",
+		"Night/Dark":       "Nacht",
+		"Heavily Overcast": "Stark bewölkt",
+		"Mostly Cloudy":    "Überwiegend bewölkt",
+		"Partly Cloudy":    "Teilweise bewölkt",
+		"Clear Skies":      "Klarer Himmel",
+
+		// Rule messages and alert updates
+		"Low production: %d hours below %.1f kW\n%s-%s": "Niedrige Produktion: %d Stunden unter %.1f kW\n%s-%s",
+		"Low energy %s: %.1f kWh (below %.1f kWh)":      "Wenig Energie %s: %.1f kWh (unter %.1f kWh)",
+		"Low peak %s: %.1f kW (below %.1f kW)":          "Niedrige Spitze %s: %.1f kW (unter %.1f kW)",
+		"Cloud cover %d%%+ for %d hours\n%s-%s":         "Bewölkung %d%%+ für %d Stunden\n%s-%s",
+		"Severity %s → %s":                              "Schweregrad %s → %s",
+		"Low hours %d → %d":                             "Schwache Stunden %d → %d",
+		"Window %s → %s":                                "Zeitraum %s → %s",
+		"Minimum output %.1f → %.1f kW":                 "Minimale Leistung %.1f → %.1f kW",
+		"New rule fired: %s":                            "Neue Regel ausgelöst: %s",
+		"none":                                          "keiner",
+		"%s of %d ensemble members":                     "%s von %d Ensemble-Mitgliedern",
+
+		// Push notifications
+		"⚠️ Solar Production Alert":                               "⚠️ Solarproduktion: Warnung",
+		"ℹ️ Solar Production Notice":                              "ℹ️ Solarproduktion: Hinweis",
+		"🚨 Solar Production Critical":                             "🚨 Solarproduktion kritisch",
+		"🔄 Solar Alert Updated":                                   "🔄 Solarwarnung aktualisiert",
+		"✅ Solar Production Recovered":                            "✅ Solarproduktion erholt",
+		"Based on %s":                                             "Basierend auf %s",
+		"Based on %s of %d ensemble members":                      "Basierend auf %s von %d Ensemble-Mitgliedern",
+		"Recovery expected at %s (%d hours)":                      "Erholung erwartet um %s (%d Stunden)",
+		"Forecast production is back above the alert thresholds.": "Die prognostizierte Produktion liegt wieder über den Warnschwellen.",
+		"Low period lasted %.0f hours":                            "Die schwache Phase dauerte %.0f Stunden",
+		"(forecast %.0f)":                                         "(prognostiziert %.0f)",
+		"Expected: %.1f kWh rest of today":                        "Erwartet: %.1f kWh für den Rest des Tages",
+		"%.1f kWh tomorrow":                                       "%.1f kWh morgen",
+
+		// Email subjects
+		"Updated:": "Aktualisiert:",
+		"Solar Production Notice - Weather Alert":               "Solarproduktion: Hinweis - Wetterwarnung",
+		"Solar Production Critical - Weather Alert":             "Solarproduktion kritisch - Wetterwarnung",
+		"Solar Production Low - Weather Alert":                  "Solarproduktion niedrig - Wetterwarnung",
+		"Solar Production Alert Cleared - Conditions Recovered": "Solarwarnung aufgehoben - Bedingungen erholt",
+
+		// Alert email
+		"Solar Production Alert":                                "Solarproduktion: Warnung",
+		"Forecast Changed Since Earlier Alert":                  "Prognose seit der letzten Warnung geändert",
+		"Low Solar Production Forecasted":                       "Niedrige Solarproduktion erwartet",
+		"Changes:":                                              "Änderungen:",
+		"Forecast (%s). Please review the forecast data below.": "Prognose (%s). Bitte prüfen Sie die Prognosedaten unten.",
+		"Forecast. Please review the forecast data below.":      "Prognose. Bitte prüfen Sie die Prognosedaten unten.",
+		"Production < %.1f kW":                                  "Produktion < %.1f kW",
+		"%d/%d HOURS":                                           "%d/%d STUNDEN",
+		"Energy < %.1f kWh/day":                                 "Energie < %.1f kWh/Tag",
+		"%d DAY(S)":                                             "%d TAG(E)",
+		"Expected Recovery":                                     "Erwartete Erholung",
+		"No recovery":                                           "Keine Erholung",
+		"in 7 days":                                             "in 7 Tagen",
+		"Hourly Weather Conditions":                             "Stündliche Wetterbedingungen",
+		"Time":                                                  "Zeit",
+		"Condition":                                             "Wetter",
+		"Production":                                            "Produktion",
+		"% Capacity":                                            "% Leistung",
+		"Air / Cell Temp":                                       "Luft- / Zelltemp.",
+		"Daily Energy Forecast":                                 "Tägliche Energieprognose",
+		"Daily energy alert threshold: %.1f kWh":                "Warnschwelle Tagesenergie: %.1f kWh",
+		"Recovery Forecast":                                     "Erholungsprognose",
+		"Conditions Expected to Improve":                        "Bedingungen werden sich voraussichtlich verbessern",
+		"Recovery Time:":                                        "Erholungszeit:",
+		"Low Period Duration:":                                  "Dauer der schwachen Phase:",
+		"%d hours (%s to %s)":                                   "%d Stunden (%s bis %s)",
+		"Time Until Recovery:":                                  "Zeit bis zur Erholung:",
+		"%d hours from low period start":                        "%d Stunden ab Beginn der schwachen Phase",
+		"What this means:":                                      "Was das bedeutet:",
+		"Solar production is expected to rise above %.1f kW at %s, approximately %d hours after the low production period begins. Plan your energy usage accordingly.": "Die Solarproduktion steigt voraussichtlich über %.1f kW um %s, etwa %d Stunden nach Beginn der schwachen Phase. Planen Sie Ihren Energieverbrauch entsprechend.",
+		"Extended Low Production Period": "Längere Phase niedriger Produktion",
+		"%d hours":                       "%d Stunden",
+		"Period:":                        "Zeitraum:",
+		"%s to %s":                       "%s bis %s",
+		"Recovery:":                      "Erholung:",
+		"Not expected within 48-hour forecast window": "Nicht innerhalb des 48-Stunden-Prognosezeitraums erwartet",
+		"Adverse weather conditions may persist beyond the forecast period. Consider alternative power arrangements and monitor for updated forecasts.": "Ungünstiges Wetter kann über den Prognosezeitraum hinaus anhalten. Planen Sie alternative Energiequellen ein und verfolgen Sie aktualisierte Prognosen.",
+		"Solar Forecast Warning System":                                      "Solarprognose-Warnsystem",
+		"Automated solar production monitoring • Real-time weather analysis": "Automatische Überwachung der Solarproduktion • Wetteranalyse in Echtzeit",
+		"Forecasts provided by":                                              "Prognosen von",
+		"Accuracy: ±15-20%":                                                  "Genauigkeit: ±15-20%",
+		"Generated at %s • This email was sent automatically":                "Erstellt um %s • Diese E-Mail wurde automatisch versendet",
+
+		// Alert email, plain text
+		"FORECAST CHANGED SINCE EARLIER ALERT":              "PROGNOSE SEIT DER LETZTEN WARNUNG GEÄNDERT",
+		"LOW SOLAR PRODUCTION FORECAST":                     "NIEDRIGE SOLARPRODUKTION ERWARTET",
+		"Forecast (%s)":                                     "Prognose (%s)",
+		"Production below %.1f kW: %d of %d daylight hours": "Produktion unter %.1f kW: %d von %d Tageslichtstunden",
+		"Energy below %.1f kWh/day: %d day(s)":              "Energie unter %.1f kWh/Tag: %d Tag(e)",
+		"Expected recovery:":                                "Erwartete Erholung:",
+		"none in the forecast":                              "keine in der Prognose",
+		"Daily energy forecast:":                            "Tägliche Energieprognose:",
+		"LOW":                                               "NIEDRIG",
+		"Upcoming daylight hours:":                          "Kommende Tageslichtstunden:",
+		"%3d%% cloud":                                       "%3d%% Wolken",
+		"low":                                               "niedrig",
+		"Solar Forecast Warning System - forecasts by Open-Meteo": "Solarprognose-Warnsystem - Prognosen von Open-Meteo",
+
+		// Recovery email
+		"Solar Production Alert Cleared": "Solarwarnung aufgehoben",
+		"Conditions Have Improved":       "Die Bedingungen haben sich verbessert",
+		"Solar production conditions have returned to normal and the alert has been cleared.": "Die Bedingungen für die Solarproduktion sind wieder normal und die Warnung wurde aufgehoben.",
+		"Status Update":                     "Statusmeldung",
+		"Alert Status:":                     "Warnstatus:",
+		"CLEARED":                           "AUFGEHOBEN",
+		"Alert Raised:":                     "Warnung ausgelöst:",
+		"Low Period Lasted:":                "Dauer der schwachen Phase:",
+		"%.0f hours":                        "%.0f Stunden",
+		"(first alert forecast %.0f hours)": "(erste Warnung prognostizierte %.0f Stunden)",
+		"Expected Energy:":                  "Erwartete Energie:",
+		"%.1f kWh for the rest of today":    "%.1f kWh für den Rest des Tages",
+		"Conditions:":                       "Bedingungen:",
+		"Solar irradiance, cloud cover, and production levels are now within normal parameters": "Sonneneinstrahlung, Bewölkung und Produktion liegen wieder im normalen Bereich",
+		"System Status:":             "Systemstatus:",
+		"Ready for next alert cycle": "Bereit für den nächsten Warnzyklus",
+		"What This Means":            "Was das bedeutet",
+		"The adverse weather conditions that triggered the alert have passed. Your solar production is expected to operate normally. The system is now armed and ready to send alerts if adverse conditions are forecasted again in the future.": "Das ungünstige Wetter, das die Warnung ausgelöst hat, ist vorüber. Ihre Solaranlage sollte wieder normal produzieren. Das System ist wieder scharf geschaltet und warnt, sobald erneut ungünstige Bedingungen vorhergesagt werden.",
+		"This is an automated notification from your Solar Production Monitoring System": "Dies ist eine automatische Benachrichtigung Ihres Überwachungssystems für die Solarproduktion",
+		"Generated at %s": "Erstellt um %s",
+
+		// Recovery email, plain text
+		"SOLAR PRODUCTION RECOVERED": "SOLARPRODUKTION ERHOLT",
+		"Forecast production is back above the alert thresholds and the alert has been cleared.": "Die prognostizierte Produktion liegt wieder über den Warnschwellen und die Warnung wurde aufgehoben.",
+		"Recovery time:":     "Erholungszeit:",
+		"Alert raised:":      "Warnung ausgelöst:",
+		"Low period lasted:": "Dauer der schwachen Phase:",
+		"Expected energy:":   "Erwartete Energie:",
+
+		// Charts
+		"Solar Production & Cloud Coverage Forecast (Next 48 Hours)": "Prognose Solarproduktion & Bewölkung (nächste 48 Stunden)",
+		"Solar Production & Cloud Coverage (Next 48h)":               "Solarproduktion & Bewölkung (nächste 48 h)",
+		"Solar production and cloud coverage forecast":               "Prognose Solarproduktion und Bewölkung",
+		"Production (kW)":                    "Produktion (kW)",
+		"Cloud Coverage (%)":                 "Bewölkung (%)",
+		"Rain Chance (%)":                    "Regenwahrscheinlichkeit (%)",
+		"Inverter limit %.1f kW (clipped ◯)": "Wechselrichtergrenze %.1f kW (gekappt ◯)",
+
+		// Chat cards
+		"Low window":        "Schwache Phase",
+		"Consecutive hours": "Aufeinanderfolgende Stunden",
+		"Minimum output":    "Minimale Leistung",
+		"Forecast basis":    "Prognosegrundlage",
+
+		// Command line
+		"Template error: %v":      "Fehler in den Vorlagen: %v",
+		"Check failed: %v":        "Prüfung fehlgeschlagen: %v",
+		"Configuration error: %v": "Konfigurationsfehler: %v",
+		"Outbox error: %v":        "Fehler im Postausgang: %v",
+		"Pending (%d)":            "Ausstehend (%d)",
+		"Dead letters (%d)":       "Aufgegeben (%d)",
+		"CREATED\tCHANNEL\tKIND\tEPISODE\tATTEMPTS\tNEXT ATTEMPT\tLAST ERROR": "ERSTELLT\tKANAL\tART\tEPISODE\tVERSUCHE\tNÄCHSTER VERSUCH\tLETZTER FEHLER",
+		"CREATED\tCHANNEL\tKIND\tEPISODE\tATTEMPTS\tLAST ERROR":               "ERSTELLT\tKANAL\tART\tEPISODE\tVERSUCHE\tLETZTER FEHLER",
+		"[TEST MODE] Using lowered thresholds: %.1f kW, %d hour":              "[TESTMODUS] Abgesenkte Schwellenwerte: %.1f kW, %d Stunde",
+
+		// Webhook recommended action
+		"Solar production forecast looks normal. No action required.": "Die Solarprognose ist normal. Keine Maßnahmen erforderlich.",
+		"⚠️ Solar production will drop below %.1f kW for %d consecutive daylight hours during %s. Expect severely limited power output during this period. Consider reducing consumption or activating backup power sources. Analysis uses automatic daylight detection based on solar irradiance.": "⚠️ Die Solarproduktion fällt unter %.1f kW, und zwar %d aufeinanderfolgende Tageslichtstunden lang (%s). Rechnen Sie in dieser Zeit mit stark eingeschränkter Leistung. Reduzieren Sie den Verbrauch oder aktivieren Sie Ersatzstromquellen. Die Analyse erkennt Tageslichtstunden automatisch anhand der Sonneneinstrahlung.",
+		"⚠️ %s. Consider reducing consumption or shifting it to other days.": "⚠️ %s. Reduzieren Sie den Verbrauch oder verlagern Sie ihn auf andere Tage.",
+	},
+}
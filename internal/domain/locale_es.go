@@ -0,0 +1,188 @@
+package domain
+
+// spanish is the Spanish (es) catalog
+var spanish = &Locale{
+	Tag:         "es",
+	Name:        "Español",
+	decimal:     ",",
+	days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+	shortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+	messages: map[string]string{
+		// Date layouts
+		"Jan 2":                         "2 Jan",
+		"Mon Jan 2":                     "Mon 2 Jan",
+		"Mon Jan 2, 15:04":              "Mon 2 Jan, 15:04",
+		"Mon 15:04":                     "Mon 15:04",
+		"Monday, January 2 15:04 MST":   "Monday, 2 de January 15:04 MST",
+		"Monday, January 2 • 15:04 MST": "Monday, 2 de January • 15:04 MST",
+		"Today 15:04":                   "Hoy 15:04",
+		"Tomorrow 15:04":                "Mañana 15:04",
+
+		// Severities and weather
+		"info":             "información",
+		"warning":          "aviso",
+		"critical":         "crítico",
+		"Night/Dark":       "Noche",
+		"Heavily Overcast": "Muy nublado",
+		"Mostly Cloudy":    "Mayormente nublado",
+		"Partly Cloudy":    "Parcialmente nublado",
+		"Clear Skies":      "Despejado",
+
+		// Rule messages and alert updates
+		"Low production: %d hours below %.1f kW\n%s-%s": "Producción baja: %d horas por debajo de %.1f kW\n%s-%s",
+		"Low energy %s: %.1f kWh (below %.1f kWh)":      "Energía baja el %s: %.1f kWh (por debajo de %.1f kWh)",
+		"Low peak %s: %.1f kW (below %.1f kW)":          "Pico bajo el %s: %.1f kW (por debajo de %.1f kW)",
+		"Cloud cover %d%%+ for %d hours\n%s-%s":         "Nubosidad del %d%% o más durante %d horas\n%s-%s",
+		"Severity %s → %s":                              "Gravedad %s → %s",
+		"Low hours %d → %d":                             "Horas bajas %d → %d",
+		"Window %s → %s":                                "Periodo %s → %s",
+		"Minimum output %.1f → %.1f kW":                 "Producción mínima %.1f → %.1f kW",
+		"New rule fired: %s":                            "Nueva regla activada: %s",
+		"none":                                          "ninguno",
+		"%s of %d ensemble members":                     "%s de %d miembros del conjunto",
+
+		// Push notifications
+		"⚠️ Solar Production Alert":                               "⚠️ Alerta de producción solar",
+		"ℹ️ Solar Production Notice":                              "ℹ️ Aviso de producción solar",
+		"🚨 Solar Production Critical":                             "🚨 Producción solar crítica",
+		"🔄 Solar Alert Updated":                                   "🔄 Alerta solar actualizada",
+		"✅ Solar Production Recovered":                            "✅ Producción solar recuperada",
+		"Based on %s":                                             "Basado en %s",
+		"Based on %s of %d ensemble members":                      "Basado en %s de %d miembros del conjunto",
+		"Recovery expected at %s (%d hours)":                      "Recuperación prevista a las %s (%d horas)",
+		"Forecast production is back above the alert thresholds.": "La producción prevista vuelve a estar por encima de los umbrales de alerta.",
+		"Low period lasted %.0f hours":                            "El periodo bajo duró %.0f horas",
+		"(forecast %.0f)":                                         "(previsto %.0f)",
+		"Expected: %.1f kWh rest of today":                        "Previsto: %.1f kWh el resto de hoy",
+		"%.1f kWh tomorrow":                                       "%.1f kWh mañana",
+
+		// Email subjects
+		"Updated:": "Actualizada:",
+		"Solar Production Notice - Weather Alert":               "Aviso de producción solar - Alerta meteorológica",
+		"Solar Production Critical - Weather Alert":             "Producción solar crítica - Alerta meteorológica",
+		"Solar Production Low - Weather Alert":                  "Producción solar baja - Alerta meteorológica",
+		"Solar Production Alert Cleared - Conditions Recovered": "Alerta de producción solar cancelada - Condiciones recuperadas",
+
+		// Alert email
+		"Solar Production Alert":                                "Alerta de producción solar",
+		"Forecast Changed Since Earlier Alert":                  "La previsión ha cambiado desde la alerta anterior",
+		"Low Solar Production Forecasted":                       "Se prevé baja producción solar",
+		"Changes:":                                              "Cambios:",
+		"Forecast (%s). Please review the forecast data below.": "Previsión (%s). Revise los datos de la previsión a continuación.",
+		"Forecast. Please review the forecast data below.":      "Previsión. Revise los datos de la previsión a continuación.",
+		"Production < %.1f kW":                                  "Producción < %.1f kW",
+		"%d/%d HOURS":                                           "%d/%d HORAS",
+		"Energy < %.1f kWh/day":                                 "Energía < %.1f kWh/día",
+		"%d DAY(S)":                                             "%d DÍA(S)",
+		"Expected Recovery":                                     "Recuperación prevista",
+		"No recovery":                                           "Sin recuperación",
+		"in 7 days":                                             "en 7 días",
+		"Hourly Weather Conditions":                             "Condiciones meteorológicas por hora",
+		"Time":                                                  "Hora",
+		"Condition":                                             "Estado",
+		"Production":                                            "Producción",
+		"% Capacity":                                            "% Capacidad",
+		"Air / Cell Temp":                                       "Temp. aire / célula",
+		"Daily Energy Forecast":                                 "Previsión de energía diaria",
+		"Daily energy alert threshold: %.1f kWh":                "Umbral de alerta de energía diaria: %.1f kWh",
+		"Recovery Forecast":                                     "Previsión de recuperación",
+		"Conditions Expected to Improve":                        "Se espera que las condiciones mejoren",
+		"Recovery Time:":                                        "Hora de recuperación:",
+		"Low Period Duration:":                                  "Duración del periodo bajo:",
+		"%d hours (%s to %s)":                                   "%d horas (de %s a %s)",
+		"Time Until Recovery:":                                  "Tiempo hasta la recuperación:",
+		"%d hours from low period start":                        "%d horas desde el inicio del periodo bajo",
+		"What this means:":                                      "Qué significa:",
+		"Solar production is expected to rise above %.1f kW at %s, approximately %d hours after the low production period begins. Plan your energy usage accordingly.": "Se espera que la producción solar supere los %.1f kW a las %s, unas %d horas después del inicio del periodo de baja producción. Planifique su consumo de energía en consecuencia.",
+		"Extended Low Production Period": "Periodo prolongado de baja producción",
+		"%d hours":                       "%d horas",
+		"Period:":                        "Periodo:",
+		"%s to %s":                       "de %s a %s",
+		"Recovery:":                      "Recuperación:",
+		"Not expected within 48-hour forecast window": "No prevista en la ventana de previsión de 48 horas",
+		"Adverse weather conditions may persist beyond the forecast period. Consider alternative power arrangements and monitor for updated forecasts.": "Las condiciones meteorológicas adversas pueden persistir más allá del periodo de previsión. Considere fuentes de energía alternativas y esté atento a las previsiones actualizadas.",
+		"Solar Forecast Warning System":                                      "Sistema de alerta de previsión solar",
+		"Automated solar production monitoring • Real-time weather analysis": "Supervisión automática de la producción solar • Análisis meteorológico en tiempo real",
+		"Forecasts provided by":                                              "Previsiones proporcionadas por",
+		"Accuracy: ±15-20%":                                                  "Precisión: ±15-20%",
+		"Generated at %s • This email was sent automatically":                "Generado a las %s • Este correo se ha enviado automáticamente",
+
+		// Alert email, plain text
+		"FORECAST CHANGED SINCE EARLIER ALERT":              "LA PREVISIÓN HA CAMBIADO DESDE LA ALERTA ANTERIOR",
+		"LOW SOLAR PRODUCTION FORECAST":                     "PREVISIÓN DE BAJA PRODUCCIÓN SOLAR",
+		"Forecast (%s)":                                     "Previsión (%s)",
+		"Production below %.1f kW: %d of %d daylight hours": "Producción por debajo de %.1f kW: %d de %d horas de luz",
+		"Energy below %.1f kWh/day: %d day(s)":              "Energía por debajo de %.1f kWh/día: %d día(s)",
+		"Expected recovery:":                                "Recuperación prevista:",
+		"none in the forecast":                              "ninguna en la previsión",
+		"Daily energy forecast:":                            "Previsión de energía diaria:",
+		"LOW":                                               "BAJA",
+		"Upcoming daylight hours:":                          "Próximas horas de luz:",
+		"%3d%% cloud":                                       "%3d%% nubes",
+		"low":                                               "baja",
+		"Solar Forecast Warning System - forecasts by Open-Meteo": "Sistema de alerta de previsión solar - previsiones de Open-Meteo",
+
+		// Recovery email
+		"Solar Production Alert Cleared": "Alerta de producción solar cancelada",
+		"Conditions Have Improved":       "Las condiciones han mejorado",
+		"Solar production conditions have returned to normal and the alert has been cleared.": "Las condiciones de producción solar han vuelto a la normalidad y la alerta se ha cancelado.",
+		"Status Update":                     "Actualización de estado",
+		"Alert Status:":                     "Estado de la alerta:",
+		"CLEARED":                           "CANCELADA",
+		"Alert Raised:":                     "Alerta emitida:",
+		"Low Period Lasted:":                "Duración del periodo bajo:",
+		"%.0f hours":                        "%.0f horas",
+		"(first alert forecast %.0f hours)": "(la primera alerta preveía %.0f horas)",
+		"Expected Energy:":                  "Energía prevista:",
+		"%.1f kWh for the rest of today":    "%.1f kWh el resto de hoy",
+		"Conditions:":                       "Condiciones:",
+		"Solar irradiance, cloud cover, and production levels are now within normal parameters": "La irradiancia solar, la nubosidad y la producción están dentro de los parámetros normales",
+		"System Status:":             "Estado del sistema:",
+		"Ready for next alert cycle": "Listo para el próximo ciclo de alertas",
+		"What This Means":            "Qué significa",
+		"The adverse weather conditions that triggered the alert have passed. Your solar production is expected to operate normally. The system is now armed and ready to send alerts if adverse conditions are forecasted again in the future.": "Las condiciones meteorológicas adversas que provocaron la alerta han pasado. Se espera que su producción solar funcione con normalidad. El sistema vuelve a estar activo y enviará alertas si se prevén de nuevo condiciones adversas.",
+		"This is an automated notification from your Solar Production Monitoring System": "Esta es una notificación automática de su sistema de supervisión de la producción solar",
+		"Generated at %s": "Generado a las %s",
+
+		// Recovery email, plain text
+		"SOLAR PRODUCTION RECOVERED": "PRODUCCIÓN SOLAR RECUPERADA",
+		"Forecast production is back above the alert thresholds and the alert has been cleared.": "La producción prevista vuelve a estar por encima de los umbrales de alerta y la alerta se ha cancelado.",
+		"Recovery time:":     "Hora de recuperación:",
+		"Alert raised:":      "Alerta emitida:",
+		"Low period lasted:": "Duración del periodo bajo:",
+		"Expected energy:":   "Energía prevista:",
+
+		// Charts
+		"Solar Production & Cloud Coverage Forecast (Next 48 Hours)": "Previsión de producción solar y nubosidad (próximas 48 horas)",
+		"Solar Production & Cloud Coverage (Next 48h)":               "Producción solar y nubosidad (próximas 48 h)",
+		"Solar production and cloud coverage forecast":               "Previsión de producción solar y nubosidad",
+		"Production (kW)":                    "Producción (kW)",
+		"Cloud Coverage (%)":                 "Nubosidad (%)",
+		"Rain Chance (%)":                    "Probabilidad de lluvia (%)",
+		"Inverter limit %.1f kW (clipped ◯)": "Límite del inversor %.1f kW (recortado ◯)",
+
+		// Chat cards
+		"Low window":        "Periodo bajo",
+		"Consecutive hours": "Horas consecutivas",
+		"Minimum output":    "Producción mínima",
+		"Forecast basis":    "Base de la previsión",
+
+		// Command line
+		"Template error: %v":      "Error en las plantillas: %v",
+		"Check failed: %v":        "La comprobación ha fallado: %v",
+		"Configuration error: %v": "Error de configuración: %v",
+		"Outbox error: %v":        "Error de la bandeja de salida: %v",
+		"Pending (%d)":            "Pendientes (%d)",
+		"Dead letters (%d)":       "Descartadas (%d)",
+		"CREATED\tCHANNEL\tKIND\tEPISODE\tATTEMPTS\tNEXT ATTEMPT\tLAST ERROR": "CREADA\tCANAL\tTIPO\tEPISODIO\tINTENTOS\tPRÓXIMO INTENTO\tÚLTIMO ERROR",
+		"CREATED\tCHANNEL\tKIND\tEPISODE\tATTEMPTS\tLAST ERROR":               "CREADA\tCANAL\tTIPO\tEPISODIO\tINTENTOS\tÚLTIMO ERROR",
+		"[TEST MODE] Using lowered thresholds: %.1f kW, %d hour":              "[MODO DE PRUEBA] Umbrales reducidos: %.1f kW, %d hora",
+
+		// Webhook recommended action
+		"Solar production forecast looks normal. No action required.": "La previsión de producción solar es normal. No se requiere ninguna acción.",
+		"⚠️ Solar production will drop below %.1f kW for %d consecutive daylight hours during %s. Expect severely limited power output during this period. Consider reducing consumption or activating backup power sources. Analysis uses automatic daylight detection based on solar irradiance.": "⚠️ La producción solar bajará de %.1f kW durante %d horas de luz consecutivas entre %s. Se espera una producción muy limitada durante este periodo. Considere reducir el consumo o activar fuentes de energía de respaldo. El análisis detecta automáticamente las horas de luz a partir de la irradiancia solar.",
+		"⚠️ %s. Consider reducing consumption or shifting it to other days.": "⚠️ %s. Considere reducir el consumo o trasladarlo a otros días.",
+	},
+}
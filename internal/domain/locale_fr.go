@@ -0,0 +1,188 @@
+package domain
+
+// french is the French (fr) catalog
+var french = &Locale{
+	Tag:         "fr",
+	Name:        "Français",
+	decimal:     ",",
+	days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	shortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+	messages: map[string]string{
+		// Date layouts
+		"Jan 2":                         "2 Jan",
+		"Mon Jan 2":                     "Mon 2 Jan",
+		"Mon Jan 2, 15:04":              "Mon 2 Jan, 15:04",
+		"Mon 15:04":                     "Mon 15:04",
+		"Monday, January 2 15:04 MST":   "Monday 2 January 15:04 MST",
+		"Monday, January 2 • 15:04 MST": "Monday 2 January • 15:04 MST",
+		"Today 15:04":                   "Aujourd'hui 15:04",
+		"Tomorrow 15:04":                "Demain 15:04",
+
+		// Severities and weather
+		"info":             "information",
+		"warning":          "avertissement",
+		"critical":         "critique",
+		"Night/Dark":       "Nuit",
+		"Heavily Overcast": "Très couvert",
+		"Mostly Cloudy":    "Plutôt nuageux",
+		"Partly Cloudy":    "Partiellement nuageux",
+		"Clear Skies":      "Ciel dégagé",
+
+		// Rule messages and alert updates
+		"Low production: %d hours below %.1f kW\n%s-%s": "Production faible : %d heures sous %.1f kW\n%s-%s",
+		"Low energy %s: %.1f kWh (below %.1f kWh)":      "Énergie faible %s : %.1f kWh (sous %.1f kWh)",
+		"Low peak %s: %.1f kW (below %.1f kW)":          "Pic faible %s : %.1f kW (sous %.1f kW)",
+		"Cloud cover %d%%+ for %d hours\n%s-%s":         "Couverture nuageuse %d%%+ pendant %d heures\n%s-%s",
+		"Severity %s → %s":                              "Gravité %s → %s",
+		"Low hours %d → %d":                             "Heures faibles %d → %d",
+		"Window %s → %s":                                "Période %s → %s",
+		"Minimum output %.1f → %.1f kW":                 "Production minimale %.1f → %.1f kW",
+		"New rule fired: %s":                            "Nouvelle règle déclenchée : %s",
+		"none":                                          "aucune",
+		"%s of %d ensemble members":                     "%s de %d membres de l'ensemble",
+
+		// Push notifications
+		"⚠️ Solar Production Alert":                               "⚠️ Alerte production solaire",
+		"ℹ️ Solar Production Notice":                              "ℹ️ Avis production solaire",
+		"🚨 Solar Production Critical":                             "🚨 Production solaire critique",
+		"🔄 Solar Alert Updated":                                   "🔄 Alerte solaire mise à jour",
+		"✅ Solar Production Recovered":                            "✅ Production solaire rétablie",
+		"Based on %s":                                             "Basé sur %s",
+		"Based on %s of %d ensemble members":                      "Basé sur %s de %d membres de l'ensemble",
+		"Recovery expected at %s (%d hours)":                      "Reprise prévue à %s (%d heures)",
+		"Forecast production is back above the alert thresholds.": "La production prévue est repassée au-dessus des seuils d'alerte.",
+		"Low period lasted %.0f hours":                            "La période faible a duré %.0f heures",
+		"(forecast %.0f)":                                         "(prévu %.0f)",
+		"Expected: %.1f kWh rest of today":                        "Prévu : %.1f kWh pour le reste de la journée",
+		"%.1f kWh tomorrow":                                       "%.1f kWh demain",
+
+		// Email subjects
+		"Updated:": "Mise à jour :",
+		"Solar Production Notice - Weather Alert":               "Avis production solaire - Alerte météo",
+		"Solar Production Critical - Weather Alert":             "Production solaire critique - Alerte météo",
+		"Solar Production Low - Weather Alert":                  "Production solaire faible - Alerte météo",
+		"Solar Production Alert Cleared - Conditions Recovered": "Alerte production solaire levée - Conditions rétablies",
+
+		// Alert email
+		"Solar Production Alert":                                "Alerte production solaire",
+		"Forecast Changed Since Earlier Alert":                  "Prévision modifiée depuis l'alerte précédente",
+		"Low Solar Production Forecasted":                       "Faible production solaire prévue",
+		"Changes:":                                              "Modifications :",
+		"Forecast (%s). Please review the forecast data below.": "Prévision (%s). Veuillez consulter les données de prévision ci-dessous.",
+		"Forecast. Please review the forecast data below.":      "Prévision. Veuillez consulter les données de prévision ci-dessous.",
+		"Production < %.1f kW":                                  "Production < %.1f kW",
+		"%d/%d HOURS":                                           "%d/%d HEURES",
+		"Energy < %.1f kWh/day":                                 "Énergie < %.1f kWh/jour",
+		"%d DAY(S)":                                             "%d JOUR(S)",
+		"Expected Recovery":                                     "Reprise prévue",
+		"No recovery":                                           "Pas de reprise",
+		"in 7 days":                                             "sous 7 jours",
+		"Hourly Weather Conditions":                             "Conditions météo heure par heure",
+		"Time":                                                  "Heure",
+		"Condition":                                             "Conditions",
+		"Production":                                            "Production",
+		"% Capacity":                                            "% Capacité",
+		"Air / Cell Temp":                                       "Temp. air / cellule",
+		"Daily Energy Forecast":                                 "Prévision d'énergie quotidienne",
+		"Daily energy alert threshold: %.1f kWh":                "Seuil d'alerte d'énergie quotidienne : %.1f kWh",
+		"Recovery Forecast":                                     "Prévision de reprise",
+		"Conditions Expected to Improve":                        "Amélioration des conditions attendue",
+		"Recovery Time:":                                        "Heure de reprise :",
+		"Low Period Duration:":                                  "Durée de la période faible :",
+		"%d hours (%s to %s)":                                   "%d heures (de %s à %s)",
+		"Time Until Recovery:":                                  "Délai avant la reprise :",
+		"%d hours from low period start":                        "%d heures après le début de la période faible",
+		"What this means:":                                      "Ce que cela signifie :",
+		"Solar production is expected to rise above %.1f kW at %s, approximately %d hours after the low production period begins. Plan your energy usage accordingly.": "La production solaire devrait dépasser %.1f kW à %s, environ %d heures après le début de la période de faible production. Planifiez votre consommation en conséquence.",
+		"Extended Low Production Period": "Période prolongée de faible production",
+		"%d hours":                       "%d heures",
+		"Period:":                        "Période :",
+		"%s to %s":                       "de %s à %s",
+		"Recovery:":                      "Reprise :",
+		"Not expected within 48-hour forecast window": "Non prévue dans la fenêtre de prévision de 48 heures",
+		"Adverse weather conditions may persist beyond the forecast period. Consider alternative power arrangements and monitor for updated forecasts.": "Les conditions météo défavorables peuvent persister au-delà de la période de prévision. Envisagez d'autres sources d'énergie et surveillez les prévisions mises à jour.",
+		"Solar Forecast Warning System":                                      "Système d'alerte de prévision solaire",
+		"Automated solar production monitoring • Real-time weather analysis": "Surveillance automatique de la production solaire • Analyse météo en temps réel",
+		"Forecasts provided by":                                              "Prévisions fournies par",
+		"Accuracy: ±15-20%":                                                  "Précision : ±15-20 %",
+		"Generated at %s • This email was sent automatically":                "Généré à %s • Cet e-mail a été envoyé automatiquement",
+
+		// Alert email, plain text
+		"FORECAST CHANGED SINCE EARLIER ALERT":              "PRÉVISION MODIFIÉE DEPUIS L'ALERTE PRÉCÉDENTE",
+		"LOW SOLAR PRODUCTION FORECAST":                     "FAIBLE PRODUCTION SOLAIRE PRÉVUE",
+		"Forecast (%s)":                                     "Prévision (%s)",
+		"Production below %.1f kW: %d of %d daylight hours": "Production sous %.1f kW : %d sur %d heures de jour",
+		"Energy below %.1f kWh/day: %d day(s)":              "Énergie sous %.1f kWh/jour : %d jour(s)",
+		"Expected recovery:":                                "Reprise prévue :",
+		"none in the forecast":                              "aucune dans la prévision",
+		"Daily energy forecast:":                            "Prévision d'énergie quotidienne :",
+		"LOW":                                               "FAIBLE",
+		"Upcoming daylight hours:":                          "Prochaines heures de jour :",
+		"%3d%% cloud":                                       "%3d %% nuages",
+		"low":                                               "faible",
+		"Solar Forecast Warning System - forecasts by Open-Meteo": "Système d'alerte de prévision solaire - prévisions Open-Meteo",
+
+		// Recovery email
+		"Solar Production Alert Cleared": "Alerte production solaire levée",
+		"Conditions Have Improved":       "Les conditions se sont améliorées",
+		"Solar production conditions have returned to normal and the alert has been cleared.": "Les conditions de production solaire sont revenues à la normale et l'alerte a été levée.",
+		"Status Update":                     "Mise à jour de l'état",
+		"Alert Status:":                     "État de l'alerte :",
+		"CLEARED":                           "LEVÉE",
+		"Alert Raised:":                     "Alerte émise :",
+		"Low Period Lasted:":                "Durée de la période faible :",
+		"%.0f hours":                        "%.0f heures",
+		"(first alert forecast %.0f hours)": "(la première alerte prévoyait %.0f heures)",
+		"Expected Energy:":                  "Énergie prévue :",
+		"%.1f kWh for the rest of today":    "%.1f kWh pour le reste de la journée",
+		"Conditions:":                       "Conditions :",
+		"Solar irradiance, cloud cover, and production levels are now within normal parameters": "L'ensoleillement, la couverture nuageuse et la production sont revenus à la normale",
+		"System Status:":             "État du système :",
+		"Ready for next alert cycle": "Prêt pour le prochain cycle d'alerte",
+		"What This Means":            "Ce que cela signifie",
+		"The adverse weather conditions that triggered the alert have passed. Your solar production is expected to operate normally. The system is now armed and ready to send alerts if adverse conditions are forecasted again in the future.": "Les conditions météo défavorables à l'origine de l'alerte sont passées. Votre production solaire devrait fonctionner normalement. Le système est de nouveau armé et enverra des alertes si des conditions défavorables sont à nouveau prévues.",
+		"This is an automated notification from your Solar Production Monitoring System": "Ceci est une notification automatique de votre système de surveillance de la production solaire",
+		"Generated at %s": "Généré à %s",
+
+		// Recovery email, plain text
+		"SOLAR PRODUCTION RECOVERED": "PRODUCTION SOLAIRE RÉTABLIE",
+		"Forecast production is back above the alert thresholds and the alert has been cleared.": "La production prévue est repassée au-dessus des seuils d'alerte et l'alerte a été levée.",
+		"Recovery time:":     "Heure de reprise :",
+		"Alert raised:":      "Alerte émise :",
+		"Low period lasted:": "Durée de la période faible :",
+		"Expected energy:":   "Énergie prévue :",
+
+		// Charts
+		"Solar Production & Cloud Coverage Forecast (Next 48 Hours)": "Prévision de production solaire et de couverture nuageuse (48 prochaines heures)",
+		"Solar Production & Cloud Coverage (Next 48h)":               "Production solaire et couverture nuageuse (48 h)",
+		"Solar production and cloud coverage forecast":               "Prévision de production solaire et de couverture nuageuse",
+		"Production (kW)":                    "Production (kW)",
+		"Cloud Coverage (%)":                 "Couverture nuageuse (%)",
+		"Rain Chance (%)":                    "Risque de pluie (%)",
+		"Inverter limit %.1f kW (clipped ◯)": "Limite de l'onduleur %.1f kW (écrêté ◯)",
+
+		// Chat cards
+		"Low window":        "Période faible",
+		"Consecutive hours": "Heures consécutives",
+		"Minimum output":    "Production minimale",
+		"Forecast basis":    "Base de la prévision",
+
+		// Command line
+		"Template error: %v":      "Erreur dans les modèles : %v",
+		"Check failed: %v":        "Échec de la vérification : %v",
+		"Configuration error: %v": "Erreur de configuration : %v",
+		"Outbox error: %v":        "Erreur de la file d'envoi : %v",
+		"Pending (%d)":            "En attente (%d)",
+		"Dead letters (%d)":       "Abandonnées (%d)",
+		"CREATED\tCHANNEL\tKIND\tEPISODE\tATTEMPTS\tNEXT ATTEMPT\tLAST ERROR": "CRÉÉE\tCANAL\tTYPE\tÉPISODE\tTENTATIVES\tPROCHAINE TENTATIVE\tDERNIÈRE ERREUR",
+		"CREATED\tCHANNEL\tKIND\tEPISODE\tATTEMPTS\tLAST ERROR":               "CRÉÉE\tCANAL\tTYPE\tÉPISODE\tTENTATIVES\tDERNIÈRE ERREUR",
+		"[TEST MODE] Using lowered thresholds: %.1f kW, %d hour":              "[MODE TEST] Seuils abaissés : %.1f kW, %d heure",
+
+		// Webhook recommended action
+		"Solar production forecast looks normal. No action required.": "La prévision de production solaire est normale. Aucune action requise.",
+		"⚠️ Solar production will drop below %.1f kW for %d consecutive daylight hours during %s. Expect severely limited power output during this period. Consider reducing consumption or activating backup power sources. Analysis uses automatic daylight detection based on solar irradiance.": "⚠️ La production solaire passera sous %.1f kW pendant %d heures de jour consécutives (%s). Attendez-vous à une production très limitée pendant cette période. Envisagez de réduire la consommation ou d'activer des sources d'énergie de secours. L'analyse détecte automatiquement les heures de jour à partir de l'irradiance solaire.",
+		"⚠️ %s. Consider reducing consumption or shifting it to other days.": "⚠️ %s. Envisagez de réduire la consommation ou de la reporter à d'autres jours.",
+	},
+}
@@ -0,0 +1,188 @@
+package domain
+
+// italian is the Italian (it) catalog
+var italian = &Locale{
+	Tag:         "it",
+	Name:        "Italiano",
+	decimal:     ",",
+	days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+	shortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+	shortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+	messages: map[string]string{
+		// Date layouts
+		"Jan 2":                         "2 Jan",
+		"Mon Jan 2":                     "Mon 2 Jan",
+		"Mon Jan 2, 15:04":              "Mon 2 Jan, 15:04",
+		"Mon 15:04":                     "Mon 15:04",
+		"Monday, January 2 15:04 MST":   "Monday 2 January 15:04 MST",
+		"Monday, January 2 • 15:04 MST": "Monday 2 January • 15:04 MST",
+		"Today 15:04":                   "Oggi 15:04",
+		"Tomorrow 15:04":                "Domani 15:04",
+
+		// Severities and weather
+		"info":             "informazione",
+		"warning":          "avviso",
+		"critical":         "critico",
+		"Night/Dark":       "Notte",
+		"Heavily Overcast": "Molto nuvoloso",
+		"Mostly Cloudy":    "Prevalentemente nuvoloso",
+		"Partly Cloudy":    "Parzialmente nuvoloso",
+		"Clear Skies":      "Sereno",
+
+		// Rule messages and alert updates
+		"Low production: %d hours below %.1f kW\n%s-%s": "Produzione bassa: %d ore sotto %.1f kW\n%s-%s",
+		"Low energy %s: %.1f kWh (below %.1f kWh)":      "Energia bassa %s: %.1f kWh (sotto %.1f kWh)",
+		"Low peak %s: %.1f kW (below %.1f kW)":          "Picco basso %s: %.1f kW (sotto %.1f kW)",
+		"Cloud cover %d%%+ for %d hours\n%s-%s":         "Nuvolosità %d%%+ per %d ore\n%s-%s",
+		"Severity %s → %s":                              "Gravità %s → %s",
+		"Low hours %d → %d":                             "Ore basse %d → %d",
+		"Window %s → %s":                                "Periodo %s → %s",
+		"Minimum output %.1f → %.1f kW":                 "Produzione minima %.1f → %.1f kW",
+		"New rule fired: %s":                            "Nuova regola attivata: %s",
+		"none":                                          "nessuno",
+		"%s of %d ensemble members":                     "%s di %d membri dell'ensemble",
+
+		// Push notifications
+		"⚠️ Solar Production Alert":                               "⚠️ Allerta produzione solare",
+		"ℹ️ Solar Production Notice":                              "ℹ️ Avviso produzione solare",
+		"🚨 Solar Production Critical":                             "🚨 Produzione solare critica",
+		"🔄 Solar Alert Updated":                                   "🔄 Allerta solare aggiornata",
+		"✅ Solar Production Recovered":                            "✅ Produzione solare ripristinata",
+		"Based on %s":                                             "Basato su %s",
+		"Based on %s of %d ensemble members":                      "Basato su %s di %d membri dell'ensemble",
+		"Recovery expected at %s (%d hours)":                      "Ripresa prevista alle %s (%d ore)",
+		"Forecast production is back above the alert thresholds.": "La produzione prevista è tornata sopra le soglie di allerta.",
+		"Low period lasted %.0f hours":                            "Il periodo basso è durato %.0f ore",
+		"(forecast %.0f)":                                         "(previste %.0f)",
+		"Expected: %.1f kWh rest of today":                        "Previsti: %.1f kWh per il resto di oggi",
+		"%.1f kWh tomorrow":                                       "%.1f kWh domani",
+
+		// Email subjects
+		"Updated:": "Aggiornamento:",
+		"Solar Production Notice - Weather Alert":               "Avviso produzione solare - Allerta meteo",
+		"Solar Production Critical - Weather Alert":             "Produzione solare critica - Allerta meteo",
+		"Solar Production Low - Weather Alert":                  "Produzione solare bassa - Allerta meteo",
+		"Solar Production Alert Cleared - Conditions Recovered": "Allerta produzione solare revocata - Condizioni ripristinate",
+
+		// Alert email
+		"Solar Production Alert":                                "Allerta produzione solare",
+		"Forecast Changed Since Earlier Alert":                  "Previsione cambiata rispetto all'allerta precedente",
+		"Low Solar Production Forecasted":                       "Prevista bassa produzione solare",
+		"Changes:":                                              "Modifiche:",
+		"Forecast (%s). Please review the forecast data below.": "Previsione (%s). Controlla i dati della previsione qui sotto.",
+		"Forecast. Please review the forecast data below.":      "Previsione. Controlla i dati della previsione qui sotto.",
+		"Production < %.1f kW":                                  "Produzione < %.1f kW",
+		"%d/%d HOURS":                                           "%d/%d ORE",
+		"Energy < %.1f kWh/day":                                 "Energia < %.1f kWh/giorno",
+		"%d DAY(S)":                                             "%d GIORNO/I",
+		"Expected Recovery":                                     "Ripresa prevista",
+		"No recovery":                                           "Nessuna ripresa",
+		"in 7 days":                                             "in 7 giorni",
+		"Hourly Weather Conditions":                             "Condizioni meteo orarie",
+		"Time":                                                  "Ora",
+		"Condition":                                             "Condizioni",
+		"Production":                                            "Produzione",
+		"% Capacity":                                            "% Capacità",
+		"Air / Cell Temp":                                       "Temp. aria / cella",
+		"Daily Energy Forecast":                                 "Previsione energia giornaliera",
+		"Daily energy alert threshold: %.1f kWh":                "Soglia di allerta energia giornaliera: %.1f kWh",
+		"Recovery Forecast":                                     "Previsione di ripresa",
+		"Conditions Expected to Improve":                        "Condizioni in miglioramento",
+		"Recovery Time:":                                        "Ora della ripresa:",
+		"Low Period Duration:":                                  "Durata del periodo basso:",
+		"%d hours (%s to %s)":                                   "%d ore (dalle %s alle %s)",
+		"Time Until Recovery:":                                  "Tempo fino alla ripresa:",
+		"%d hours from low period start":                        "%d ore dall'inizio del periodo basso",
+		"What this means:":                                      "Cosa significa:",
+		"Solar production is expected to rise above %.1f kW at %s, approximately %d hours after the low production period begins. Plan your energy usage accordingly.": "La produzione solare dovrebbe superare %.1f kW alle %s, circa %d ore dopo l'inizio del periodo di bassa produzione. Pianifica i consumi di conseguenza.",
+		"Extended Low Production Period": "Periodo prolungato di bassa produzione",
+		"%d hours":                       "%d ore",
+		"Period:":                        "Periodo:",
+		"%s to %s":                       "dalle %s alle %s",
+		"Recovery:":                      "Ripresa:",
+		"Not expected within 48-hour forecast window": "Non prevista entro la finestra di previsione di 48 ore",
+		"Adverse weather conditions may persist beyond the forecast period. Consider alternative power arrangements and monitor for updated forecasts.": "Le condizioni meteo avverse potrebbero persistere oltre il periodo di previsione. Valuta fonti di energia alternative e segui le previsioni aggiornate.",
+		"Solar Forecast Warning System":                                      "Sistema di allerta previsioni solari",
+		"Automated solar production monitoring • Real-time weather analysis": "Monitoraggio automatico della produzione solare • Analisi meteo in tempo reale",
+		"Forecasts provided by":                                              "Previsioni fornite da",
+		"Accuracy: ±15-20%":                                                  "Precisione: ±15-20%",
+		"Generated at %s • This email was sent automatically":                "Generato alle %s • Questa email è stata inviata automaticamente",
+
+		// Alert email, plain text
+		"FORECAST CHANGED SINCE EARLIER ALERT":              "PREVISIONE CAMBIATA RISPETTO ALL'ALLERTA PRECEDENTE",
+		"LOW SOLAR PRODUCTION FORECAST":                     "PREVISTA BASSA PRODUZIONE SOLARE",
+		"Forecast (%s)":                                     "Previsione (%s)",
+		"Production below %.1f kW: %d of %d daylight hours": "Produzione sotto %.1f kW: %d di %d ore di luce",
+		"Energy below %.1f kWh/day: %d day(s)":              "Energia sotto %.1f kWh/giorno: %d giorno/i",
+		"Expected recovery:":                                "Ripresa prevista:",
+		"none in the forecast":                              "nessuna nella previsione",
+		"Daily energy forecast:":                            "Previsione energia giornaliera:",
+		"LOW":                                               "BASSA",
+		"Upcoming daylight hours:":                          "Prossime ore di luce:",
+		"%3d%% cloud":                                       "%3d%% nuvole",
+		"low":                                               "bassa",
+		"Solar Forecast Warning System - forecasts by Open-Meteo": "Sistema di allerta previsioni solari - previsioni di Open-Meteo",
+
+		// Recovery email
+		"Solar Production Alert Cleared": "Allerta produzione solare revocata",
+		"Conditions Have Improved":       "Le condizioni sono migliorate",
+		"Solar production conditions have returned to normal and the alert has been cleared.": "Le condizioni di produzione solare sono tornate normali e l'allerta è stata revocata.",
+		"Status Update":                     "Aggiornamento di stato",
+		"Alert Status:":                     "Stato dell'allerta:",
+		"CLEARED":                           "REVOCATA",
+		"Alert Raised:":                     "Allerta emessa:",
+		"Low Period Lasted:":                "Durata del periodo basso:",
+		"%.0f hours":                        "%.0f ore",
+		"(first alert forecast %.0f hours)": "(la prima allerta ne prevedeva %.0f)",
+		"Expected Energy:":                  "Energia prevista:",
+		"%.1f kWh for the rest of today":    "%.1f kWh per il resto di oggi",
+		"Conditions:":                       "Condizioni:",
+		"Solar irradiance, cloud cover, and production levels are now within normal parameters": "Irraggiamento solare, nuvolosità e produzione sono ora nei parametri normali",
+		"System Status:":             "Stato del sistema:",
+		"Ready for next alert cycle": "Pronto per il prossimo ciclo di allerta",
+		"What This Means":            "Cosa significa",
+		"The adverse weather conditions that triggered the alert have passed. Your solar production is expected to operate normally. The system is now armed and ready to send alerts if adverse conditions are forecasted again in the future.": "Le condizioni meteo avverse che hanno causato l'allerta sono passate. La produzione solare dovrebbe tornare normale. Il sistema è di nuovo attivo e invierà allerte se verranno previste altre condizioni avverse.",
+		"This is an automated notification from your Solar Production Monitoring System": "Questa è una notifica automatica del sistema di monitoraggio della produzione solare",
+		"Generated at %s": "Generato alle %s",
+
+		// Recovery email, plain text
+		"SOLAR PRODUCTION RECOVERED": "PRODUZIONE SOLARE RIPRISTINATA",
+		"Forecast production is back above the alert thresholds and the alert has been cleared.": "La produzione prevista è tornata sopra le soglie di allerta e l'allerta è stata revocata.",
+		"Recovery time:":     "Ora della ripresa:",
+		"Alert raised:":      "Allerta emessa:",
+		"Low period lasted:": "Durata del periodo basso:",
+		"Expected energy:":   "Energia prevista:",
+
+		// Charts
+		"Solar Production & Cloud Coverage Forecast (Next 48 Hours)": "Previsione produzione solare e nuvolosità (prossime 48 ore)",
+		"Solar Production & Cloud Coverage (Next 48h)":               "Produzione solare e nuvolosità (prossime 48 h)",
+		"Solar production and cloud coverage forecast":               "Previsione produzione solare e nuvolosità",
+		"Production (kW)":                    "Produzione (kW)",
+		"Cloud Coverage (%)":                 "Nuvolosità (%)",
+		"Rain Chance (%)":                    "Probabilità di pioggia (%)",
+		"Inverter limit %.1f kW (clipped ◯)": "Limite inverter %.1f kW (limitato ◯)",
+
+		// Chat cards
+		"Low window":        "Periodo basso",
+		"Consecutive hours": "Ore consecutive",
+		"Minimum output":    "Produzione minima",
+		"Forecast basis":    "Base della previsione",
+
+		// Command line
+		"Template error: %v":      "Errore nei template: %v",
+		"Check failed: %v":        "Controllo non riuscito: %v",
+		"Configuration error: %v": "Errore di configurazione: %v",
+		"Outbox error: %v":        "Errore della coda di invio: %v",
+		"Pending (%d)":            "In attesa (%d)",
+		"Dead letters (%d)":       "Abbandonate (%d)",
+		"CREATED\tCHANNEL\tKIND\tEPISODE\tATTEMPTS\tNEXT ATTEMPT\tLAST ERROR": "CREATA\tCANALE\tTIPO\tEPISODIO\tTENTATIVI\tPROSSIMO TENTATIVO\tULTIMO ERRORE",
+		"CREATED\tCHANNEL\tKIND\tEPISODE\tATTEMPTS\tLAST ERROR":               "CREATA\tCANALE\tTIPO\tEPISODIO\tTENTATIVI\tULTIMO ERRORE",
+		"[TEST MODE] Using lowered thresholds: %.1f kW, %d hour":              "[MODALITÀ TEST] Soglie ridotte: %.1f kW, %d ora",
+
+		// Webhook recommended action
+		"Solar production forecast looks normal. No action required.": "La previsione di produzione solare è normale. Nessuna azione richiesta.",
+		"⚠️ Solar production will drop below %.1f kW for %d consecutive daylight hours during %s. Expect severely limited power output during this period. Consider reducing consumption or activating backup power sources. Analysis uses automatic daylight detection based on solar irradiance.": "⚠️ La produzione solare scenderà sotto %.1f kW per %d ore di luce consecutive tra le %s. Prevedi una produzione fortemente ridotta in questo periodo. Valuta di ridurre i consumi o di attivare fonti di energia di riserva. L'analisi rileva automaticamente le ore di luce in base all'irraggiamento solare.",
+		"⚠️ %s. Consider reducing consumption or shifting it to other days.": "⚠️ %s. Valuta di ridurre i consumi o di spostarli ad altri giorni.",
+	},
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"", "en"},
+		{"es", "es"},
+		{"DE", "de"},
+		{"fr_CA", "fr"},
+		{"it-CH", "it"},
+	}
+	for _, tt := range tests {
+		locale, err := LookupLocale(tt.tag)
+		if err != nil {
+			t.Errorf("LookupLocale(%q): %v", tt.tag, err)
+			continue
+		}
+		if locale.Tag != tt.want {
+			t.Errorf("LookupLocale(%q) = %s, want %s", tt.tag, locale.Tag, tt.want)
+		}
+	}
+	if _, err := LookupLocale("xx"); err == nil {
+		t.Error("expected an error for an unsupported locale")
+	}
+}
+
+func TestLocaleSprintf(t *testing.T) {
+	es, _ := LookupLocale("es")
+	if got := es.Sprintf("Low energy %s: %.1f kWh (below %.1f kWh)", "lun 2 mar", 3.25, 8.0); got != "Energía baja el lun 2 mar: 3,2 kWh (por debajo de 8,0 kWh)" {
+		t.Errorf("es Sprintf = %q", got)
+	}
+	de, _ := LookupLocale("de")
+	if got := de.Sprintf("%d hours", 4); got != "4 Stunden" {
+		t.Errorf("de Sprintf = %q", got)
+	}
+
+	var english *Locale
+	if got := english.Sprintf("Production < %.1f kW", 2.5); got != "Production < 2.5 kW" {
+		t.Errorf("nil Sprintf = %q", got)
+	}
+	if got := english.T("Clear Skies"); got != "Clear Skies" {
+		t.Errorf("nil T = %q", got)
+	}
+}
+
+func TestLocaleFormatTime(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		tag    string
+		layout string
+		want   string
+	}{
+		{"en", "Monday, January 2 15:04 MST", "Monday, March 2 14:30 UTC"},
+		{"es", "Monday, January 2 15:04 MST", "lunes, 2 de marzo 14:30 UTC"},
+		{"it", "Mon Jan 2", "lun 2 mar"},
+		{"de", "Mon Jan 2, 15:04", "Mo 2. Mär, 14:30"},
+		{"fr", "Tomorrow 15:04", "Demain 14:30"},
+		{"fr", "Jan 2", "2 mars"},
+	}
+	for _, tt := range tests {
+		locale, _ := LookupLocale(tt.tag)
+		if got := locale.FormatTime(monday, tt.layout); got != tt.want {
+			t.Errorf("%s FormatTime(%q) = %q, want %q", tt.tag, tt.layout, got, tt.want)
+		}
+	}
+}
+
+// Every catalog translates the same messages, so none silently falls back to English
+func TestLocaleCatalogsComplete(t *testing.T) {
+	for tag, locale := range locales {
+		if tag == DefaultLocale {
+			continue
+		}
+		for message := range spanish.messages {
+			if _, ok := locale.messages[message]; !ok {
+				t.Errorf("%s: missing translation for %q", tag, message)
+			}
+		}
+		if len(locale.messages) != len(spanish.messages) {
+			t.Errorf("%s: %d messages, want %d", tag, len(locale.messages), len(spanish.messages))
+		}
+	}
+}
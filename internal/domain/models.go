@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"math"
 	"time"
 )
 
@@ -30,6 +31,37 @@ const (
 
 	// DefaultNightCompressionFactor reduces spacing for nighttime hours in charts
 	DefaultNightCompressionFactor = 0.05
+
+	// DefaultMQTTClientID identifies the publisher to the MQTT broker
+	DefaultMQTTClientID = "solar-forecast"
+
+	// DefaultMQTTTopicPrefix is the topic the forecast state is published under
+	DefaultMQTTTopicPrefix = "solar_forecast"
+
+	// DefaultMQTTDiscoveryPrefix is Home Assistant's default discovery prefix
+	DefaultMQTTDiscoveryPrefix = "homeassistant"
+
+	// DefaultMQTTForecastHours is the default hours in the MQTT forecast attributes
+	DefaultMQTTForecastHours = 48
+
+	// DefaultWebhookMaxAttempts is how often a webhook delivery is tried within one run
+	DefaultWebhookMaxAttempts = 4
+
+	// DefaultWebhookRetryDelaySeconds is the wait before the first webhook retry, doubled after each attempt
+	DefaultWebhookRetryDelaySeconds = 2
+
+	// DefaultChannelTimeoutSeconds limits one delivery on a notification channel
+	DefaultChannelTimeoutSeconds = 20
+
+	// DefaultRunTimeoutSeconds limits a whole run: forecast fetches, every
+	// notification dispatch, outbox retries and publishing
+	DefaultRunTimeoutSeconds = 120
+
+	// DefaultOutboxMaxAttempts is how often a notification is tried before it is dead-lettered
+	DefaultOutboxMaxAttempts = 6
+
+	// DefaultOutboxRetryDelayMinutes is the wait before the first outbox retry, doubled after each attempt
+	DefaultOutboxRetryDelayMinutes = 15
 )
 
 // Logger defines the interface for logging
@@ -50,38 +82,81 @@ type WeatherForecastProvider interface {
 type EmailNotifier interface {
 	// SendAlert sends an HTML-formatted alert email with graphs
 	SendAlert(ctx context.Context, analysis *AlertAnalysis) error
-	// SendRecoveryEmail sends an email indicating conditions have improved
-	SendRecoveryEmail(ctx context.Context) error
+	// SendRecoveryEmail sends an email indicating conditions have improved,
+	// with the upcoming forecast and how the resolved episode compared with its forecast
+	SendRecoveryEmail(ctx context.Context, summary *RecoverySummary) error
 }
 
 // PushNotifier defines the interface for sending push notifications
 type PushNotifier interface {
-	// SendNotification sends a push notification with optional image
-	SendNotification(ctx context.Context, title, message string, imageData []byte) error
+	// SendNotification sends a push notification with optional image.
+	// Severity sets how intrusive the notification is (e.g. priority or sound).
+	SendNotification(ctx context.Context, title, message string, imageData []byte, severity Severity) error
+}
+
+// Notifier is one notification channel the service dispatches alerts to.
+// Email and push notifiers are adapted with NewEmailChannel and NewPushChannel.
+type Notifier interface {
+	// SendAlert sends an alert or alert update
+	SendAlert(ctx context.Context, analysis *AlertAnalysis) error
+	// SendRecovery sends the all-clear for a resolved episode
+	SendRecovery(ctx context.Context, summary *RecoverySummary) error
+}
+
+// AlertDetailNotifier is implemented by push notifiers that lay out the alert's
+// fields themselves, such as chat cards, rather than only the rendered text
+type AlertDetailNotifier interface {
+	// SendAlertNotification sends an alert or alert update with its analysis
+	SendAlertNotification(ctx context.Context, title, message string, analysis *AlertAnalysis, imageData []byte) error
+}
+
+// RecoveryDetailNotifier is implemented by push notifiers that carry the
+// resolved episode and its summary, such as structured webhooks, rather than
+// only the rendered text
+type RecoveryDetailNotifier interface {
+	// SendRecoveryNotification sends the all-clear for a resolved episode
+	SendRecoveryNotification(ctx context.Context, title, message string, summary *RecoverySummary, imageData []byte) error
+}
+
+// ForecastPublisher defines the interface for publishing the forecast after
+// each run, such as home automation sensors
+type ForecastPublisher interface {
+	// PublishForecast publishes the run's forecast and alert state
+	PublishForecast(ctx context.Context, snapshot *ForecastSnapshot) error
 }
 
-// AlertStateRepository defines the interface for persisting alert state
+// AlertStateRepository defines the interface for persisting alert episodes
 type AlertStateRepository interface {
-	// GetLastAlertDate retrieves the last date when alert was sent
-	GetLastAlertDate(ctx context.Context) (AlertState, error)
+	// ActiveEpisode returns the open alert episode, or nil when there is none
+	ActiveEpisode(ctx context.Context) (*AlertEpisode, error)
 
-	// SaveAlertDate persists the current alert sent date
-	SaveAlertDate(ctx context.Context, state AlertState) error
+	// OpenEpisode stores a new episode as the active one
+	OpenEpisode(ctx context.Context, episode AlertEpisode) error
 
-	// ResetIfNewDay resets alert state if it's a new calendar day (returns true if reset)
-	ResetIfNewDay(ctx context.Context) (bool, error)
+	// UpdateEpisode replaces the active episode with the same ID
+	UpdateEpisode(ctx context.Context, episode AlertEpisode) error
 
-	// MarkAlertSent marks that alert was sent today
-	MarkAlertSent(ctx context.Context) error
+	// CloseEpisode resolves the active episode and moves it to the history
+	CloseEpisode(ctx context.Context, id string, resolvedAt time.Time) error
+}
+
+// NotificationOutbox persists notifications a channel failed to deliver, so
+// later runs can retry them
+type NotificationOutbox interface {
+	// Pending returns the messages waiting for delivery, oldest first
+	Pending(ctx context.Context) ([]OutboxMessage, error)
+
+	// Enqueue stores a message, replacing a pending message with the same key
+	Enqueue(ctx context.Context, message OutboxMessage) error
 
-	// ShouldSendAlert checks if alert should be sent based on state
-	ShouldSendAlert(ctx context.Context) (bool, error)
+	// Remove deletes a delivered or superseded message
+	Remove(ctx context.Context, key string) error
 
-	// ShouldSendRecoveryEmail checks if recovery email should be sent
-	ShouldSendRecoveryEmail(ctx context.Context) (bool, error)
+	// DeadLetter moves a message that used up its attempts to the dead-letter list
+	DeadLetter(ctx context.Context, message OutboxMessage) error
 
-	// MarkRecoveryEmailSent marks that recovery email has been sent
-	MarkRecoveryEmailSent(ctx context.Context) error
+	// DeadLetters returns the messages that were given up on, oldest first
+	DeadLetters(ctx context.Context) ([]OutboxMessage, error)
 }
 
 // Config holds all application configuration
@@ -94,23 +169,96 @@ type Config struct {
 	ProductionAlertThresholdKW float64 // Alert if production drops below this (kW)
 	DurationThresholdHours     int     // Alert if threshold exceeded for this many consecutive hours
 
+	// Alert rules (alert_rule.<name>.*). When empty, rules are derived from the
+	// duration and daily energy thresholds.
+	AlertRules []AlertRuleConfig
+
+	// Severity tiers and per-severity notification channels
+	SeverityTiers  SeverityTiers
+	SeverityRoutes SeverityRoutes
+
+	// Alert updates: follow-up notifications when an alert sent today changes materially
+	AlertUpdateHourTolerance int     // Hours the low period may grow, shrink or shift without an update
+	AlertUpdateKWTolerance   float64 // kW the minimum output may change without an update
+	AlertUpdateMaxPerDay     int     // Updates allowed per day after the first alert (0 = disabled)
+
+	// Pushover emergency priority (critical alerts): retry interval and give-up time
+	PushoverEmergencyRetrySeconds  int
+	PushoverEmergencyExpireSeconds int
+
+	// Alert threshold (daily energy)
+	DailyEnergyAlertThresholdKWh float64 // Alert if a day's forecast energy is below this (0 = disabled)
+	DailyEnergyAlertDays         int     // Complete forecast days checked against the threshold (default: 2)
+
+	// Probabilistic alerting
+	EnsembleModels []string      // Open-Meteo ensemble models to fetch (empty = deterministic only)
+	AlertQuantile  AlertQuantile // Production estimate the criteria use ("deterministic", "p10", "p50", "p90")
+
 	// Daylight detection (replaces fixed analysis window)
 	DaylightGHIThreshold float64 // GHI threshold in W/m² to consider as daylight (typically 50-100)
 
 	// Panel configuration
-	RatedCapacityKW    float64 // Rated output at STC (already includes panel efficiency)
-	InverterEfficiency float64 // DC to AC conversion efficiency (0.95-0.98)
+	RatedCapacityKW    float64 // Total rated output at STC (already includes panel efficiency)
+	InverterEfficiency float64 // Flat DC to AC conversion efficiency (0.95-0.98), used when no curve is configured
 	TempCoefficient    float64 // Temperature coefficient in % per °C (typically -0.4 to -0.5)
 
+	// Inverter
+	InverterACLimitKW       float64           // Maximum AC output; DC beyond this is clipped (0 = no limit)
+	InverterEfficiencyCurve []EfficiencyPoint // Part-load efficiency curve, sorted by load (replaces InverterEfficiency)
+
+	// Panel orientation (plane-of-array irradiance)
+	PanelTiltDeg       float64            // Tilt from horizontal in degrees (0 = flat, 90 = vertical)
+	PanelAzimuthDeg    float64            // Compass direction the panels face (90 = east, 180 = south, 270 = west)
+	GroundAlbedo       float64            // Ground reflectance for reflected irradiance (typically 0.2)
+	TranspositionModel TranspositionModel // GHI to plane-of-array model ("isotropic" or "hay-davies")
+
+	// Cell temperature model used for the temperature derate
+	CellTemperature CellTemperatureConfig
+
+	// PV arrays sharing the inverter. When empty, a single array is derived from
+	// RatedCapacityKW, PanelTiltDeg, PanelAzimuthDeg and TempCoefficient.
+	Arrays []PVArray
+
 	// Email
-	GmailAppPassword string
-	GmailSender      string
-	RecipientEmail   string
+	EmailSender    string           // From address; also the SMTP username when none is set
+	RecipientEmail string           // Single To recipient; merged into Recipients by the loader
+	Recipients     []EmailRecipient // Everyone who can receive email, with their preferences
+	SMTP           SMTPConfig       // Mail server, resolved from smtp_* keys or a preset
+
+	// Notification language and templates
+	Locale      string // Language of emails, pushes and CLI messages ("en", "es", "it", "de", "fr")
+	TemplateDir string // Templates overriding the built-in email and push templates (empty = built-in only)
 
 	// Pushover push notifications
 	PushoverUserKey  string
 	PushoverAPIToken string
 
+	// Telegram bot notifications
+	TelegramBotToken   string
+	TelegramChatID     string // Chat, group or channel to post to (e.g. -1001234567890 or @channel)
+	TelegramAPIBaseURL string // Bot API server (default: https://api.telegram.org)
+
+	// Chat incoming webhooks (empty = disabled)
+	SlackWebhookURL   string
+	DiscordWebhookURL string
+	TeamsWebhookURL   string
+
+	// Self-hosted push services
+	Ntfy   NtfyConfig
+	Gotify GotifyConfig
+
+	// Signed JSON event webhook
+	Webhook WebhookConfig
+
+	// MQTT sensors for home automation
+	MQTT MQTTConfig
+
+	// Per-channel settings, keyed by channel name (see KnownChannels)
+	Channels map[string]ChannelConfig
+
+	// Retry of failed notifications across runs
+	Outbox OutboxConfig
+
 	// Analysis periods
 	ChartDisplayHours  int // Hours to display in graphs (default: 48)
 	AlertAnalysisHours int // Hours to analyze for alert conditions (default: 24)
@@ -123,10 +271,108 @@ type Config struct {
 	APIRetryDelaySeconds int
 	APITimeoutSeconds    int
 
+	// Limit for one whole run; each channel timeout must fit well inside it
+	RunTimeoutSeconds int // Default: 120
+
 	// Testing
 	TestMode bool // When true, bypasses daytime check for notifications
 }
 
+// PVArrays returns the configured arrays, or a single array built from the
+// top-level panel settings when no arrays are configured
+func (c *Config) PVArrays() []PVArray {
+	if len(c.Arrays) > 0 {
+		return c.Arrays
+	}
+	return []PVArray{{
+		Name:            "main",
+		CapacityKW:      c.RatedCapacityKW,
+		TiltDeg:         c.PanelTiltDeg,
+		AzimuthDeg:      c.PanelAzimuthDeg,
+		TempCoefficient: c.TempCoefficient,
+	}}
+}
+
+// NtfyConfig describes the ntfy topic push notifications are published to
+type NtfyConfig struct {
+	ServerURL   string // Default: https://ntfy.sh
+	Topic       string // Empty disables ntfy
+	AccessToken string // Access token for protected topics; takes precedence over username/password
+	Username    string
+	Password    string
+	ClickURL    string   // Opened when the notification is tapped
+	Tags        []string // Extra tags (emoji short codes) added to every message
+}
+
+// GotifyConfig describes the Gotify server push notifications are sent to
+type GotifyConfig struct {
+	ServerURL string // Empty disables Gotify
+	AppToken  string // Token of the Gotify application messages are sent as
+	ClickURL  string // Opened when the notification is tapped
+}
+
+// WebhookConfig describes the endpoint alert events are posted to as signed JSON
+type WebhookConfig struct {
+	URL               string // Empty disables the webhook
+	Secret            string // HMAC-SHA256 signing key (empty = unsigned)
+	MaxAttempts       int    // Deliveries tried before giving up (default: 4)
+	RetryDelaySeconds int    // Delay before the first retry, doubled after each attempt (default: 2)
+}
+
+// MQTTConfig describes the broker the forecast is published to after each run
+type MQTTConfig struct {
+	BrokerURL       string // tcp://host:1883 or ssl://host:8883; empty disables MQTT
+	ClientID        string // Default: solar-forecast
+	Username        string
+	Password        string
+	TopicPrefix     string // State topics are published under it (default: solar_forecast)
+	DiscoveryPrefix string // Home Assistant discovery prefix (default: homeassistant; empty disables discovery)
+	Retain          bool   // Retain state and discovery messages (default: true)
+	QoS             int    // 0 or 1 (default: 1)
+	ForecastHours   int    // Hours in the forecast attributes (default: 48)
+	CAFile          string // PEM bundle trusted instead of the system roots, for private brokers
+}
+
+// OutboxConfig describes how failed notifications are retried on later runs
+type OutboxConfig struct {
+	MaxAttempts       int // Attempts, including the first, before dead-lettering (default: 6)
+	RetryDelayMinutes int // Delay before the first retry, doubled after each attempt (default: 15)
+}
+
+// ChannelConfig holds the settings of one notification channel
+type ChannelConfig struct {
+	Enabled        bool // Default: true; a disabled channel is not registered
+	TimeoutSeconds int  // Limit for one delivery (default: 20)
+}
+
+// Channel returns the named channel's settings, with defaults for channels
+// that are not configured
+func (c *Config) Channel(name string) ChannelConfig {
+	channel, ok := c.Channels[name]
+	if !ok {
+		channel.Enabled = true
+	}
+	if channel.TimeoutSeconds == 0 {
+		channel.TimeoutSeconds = DefaultChannelTimeoutSeconds
+	}
+	return channel
+}
+
+// PVArray describes one string of panels with its own orientation and losses
+type PVArray struct {
+	Name            string
+	CapacityKW      float64 // Rated DC output at STC
+	TiltDeg         float64 // Tilt from horizontal in degrees
+	AzimuthDeg      float64 // Compass direction the panels face
+	LossPercent     float64 // Combined soiling, shading, mismatch and wiring losses (%)
+	TempCoefficient float64 // Temperature coefficient in % per °C
+}
+
+// Orientation returns the plane the array's panels lie in
+func (a PVArray) Orientation() PanelOrientation {
+	return PanelOrientation{TiltDeg: a.TiltDeg, AzimuthDeg: a.AzimuthDeg}
+}
+
 // ForecastHour represents one hour of forecast data
 type ForecastHour struct {
 	Hour                       time.Time
@@ -135,6 +381,25 @@ type ForecastHour struct {
 	GlobalHorizontalIrradiance float64 // W/m²
 	RelativeHumidity           int     // percentage 0-100
 	PrecipitationProbability   int     // percentage 0-100
+	WindSpeed                  float64 // m/s at 10 m
+
+	// Irradiance components, used instead of estimating them from GHI when available
+	DirectNormalIrradiance      float64           // W/m²
+	DiffuseHorizontalIrradiance float64           // W/m²
+	HasIrradianceComponents     bool              // True when both DNI and DHI were provided
+	GlobalTiltedIrradiance      float64           // W/m² on TiltedPlane
+	TiltedPlane                 *PanelOrientation // Plane GlobalTiltedIrradiance refers to (nil if unavailable)
+}
+
+// PanelOrientation identifies a panel plane
+type PanelOrientation struct {
+	TiltDeg    float64
+	AzimuthDeg float64 // Compass degrees, 180 = south
+}
+
+// Matches reports whether two orientations describe the same plane (within half a degree)
+func (o PanelOrientation) Matches(other PanelOrientation) bool {
+	return math.Abs(o.TiltDeg-other.TiltDeg) < 0.5 && math.Abs(o.AzimuthDeg-other.AzimuthDeg) < 0.5
 }
 
 // ForecastData holds 48-hour forecast
@@ -153,17 +418,45 @@ type SolarProduction struct {
 	Temperature              float64 // Celsius
 	GHI                      float64 // W/m² - for condition determination
 	PrecipitationProbability int     // percentage 0-100
+
+	// Irradiance on the panel plane
+	POA             float64 // Capacity-weighted plane-of-array irradiance in W/m² across all arrays
+	SunElevationDeg float64 // Sun elevation at the middle of the hour
+	CellTemperature float64 // Capacity-weighted PV cell temperature in Celsius
+	WindSpeed       float64 // m/s at 10 m
+
+	// Inverter
+	DCOutputKW float64 // Combined array DC output before the inverter
+	Clipped    bool    // True when the inverter AC limit capped this hour's output
+	ClippedKW  float64 // AC power lost to clipping
+
+	// Per-array contribution to EstimatedOutputKW (same order as the configured arrays)
+	Arrays []ArrayProduction
+
+	// Ensemble production spread (nil when no ensemble forecast covers this hour)
+	Quantiles *ProductionQuantiles
+}
+
+// ArrayProduction is one array's share of an hour's production
+type ArrayProduction struct {
+	Name            string
+	OutputKW        float64 // AC output attributed to this array
+	POA             float64 // Plane-of-array irradiance for this array's orientation (W/m²)
+	CellTemperature float64 // Estimated cell temperature for this array (Celsius)
 }
 
-// AlertCriteria represents which thresholds were triggered
+// AlertCriteria summarises which kinds of rule fired
 type AlertCriteria struct {
-	LowProductionDurationTriggered bool // Alert when production < threshold for 6+ consecutive hours
-	AnyTriggered                   bool
+	LowProductionDurationTriggered bool // A duration rule fired (production < threshold for N consecutive hours)
+	LowDailyEnergyTriggered        bool // A daily energy rule fired (a day's forecast energy < threshold)
+	AnyTriggered                   bool // Any rule fired
 }
 
 // AlertAnalysis holds detailed analysis of forecast period
 type AlertAnalysis struct {
 	CriteriaTriggered      AlertCriteria
+	FiredRules             []RuleResult      // Results of every rule that fired, in configuration order
+	Severity               Severity          // Highest severity among the fired rules
 	LowProductionHours     []SolarProduction // Hours with production < threshold
 	AllProductionHours     []SolarProduction // All forecast hours (for chart display)
 	ConsecutiveHourCount   int               // How many consecutive hours triggered
@@ -178,12 +471,20 @@ type AlertAnalysis struct {
 	RecoveryHour       time.Time // When production rises above threshold
 	HoursUntilRecovery int       // Total duration from start to recovery
 	HasRecovery        bool      // Whether recovery happens within 48h forecast
-}
 
-// AlertState tracks whether alert was sent today
-type AlertState struct {
-	LastAlertDate     time.Time
-	AlertSent         bool
-	AlertRecovered    bool // Track if conditions improved
-	RecoveryEmailSent bool // Flag to ensure recovery email only sent once
+	// Daily energy
+	DailyEnergy   []DailyEnergy // Per-calendar-day totals over AllProductionHours
+	LowEnergyDays []DailyEnergy // Checked days below the daily energy threshold
+
+	// Forecast uncertainty; per-hour bands are in each hour's Quantiles
+	AlertQuantile   AlertQuantile // Production estimate the criteria were evaluated against
+	EnsembleMembers int           // Ensemble members behind the quantiles (0 = deterministic only)
+
+	// Set when this analysis is sent as an update to the active episode's alert
+	Update *AlertUpdate
+
+	// Identifies the notification the analysis is sent as, and when it was first
+	// sent; retries keep both
+	NotificationID string
+	NotifiedAt     time.Time
 }
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Notification channels a severity can be routed to. ChannelPush is a group
+// covering every channel except email.
+const (
+	ChannelEmail    = "email"
+	ChannelPush     = "push"
+	ChannelPushover = "pushover"
+	ChannelTelegram = "telegram"
+	ChannelNtfy     = "ntfy"
+	ChannelGotify   = "gotify"
+	ChannelSlack    = "slack"
+	ChannelDiscord  = "discord"
+	ChannelTeams    = "teams"
+	ChannelWebhook  = "webhook"
+)
+
+// KnownChannels lists the notification channels in dispatch order
+var KnownChannels = []string{
+	ChannelEmail, ChannelPushover, ChannelTelegram, ChannelNtfy, ChannelGotify,
+	ChannelSlack, ChannelDiscord, ChannelTeams, ChannelWebhook,
+}
+
+// IsKnownChannel reports whether name is a notification channel
+func IsKnownChannel(name string) bool {
+	for _, channel := range KnownChannels {
+		if channel == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NotifierRegistry holds the named notification channels and dispatches to
+// them concurrently, each with its own timeout
+type NotifierRegistry struct {
+	channels []registeredChannel
+}
+
+// registeredChannel is a notifier with its name and delivery timeout
+type registeredChannel struct {
+	name     string
+	notifier Notifier
+	timeout  time.Duration
+}
+
+// DeliveryResult is the outcome of one delivery on one channel
+type DeliveryResult struct {
+	Channel  string
+	Err      error // nil when delivered
+	Duration time.Duration
+}
+
+// NewNotifierRegistry creates an empty registry
+func NewNotifierRegistry() *NotifierRegistry {
+	return &NotifierRegistry{}
+}
+
+// Register adds a channel under a unique name. A zero timeout uses
+// DefaultChannelTimeoutSeconds.
+func (r *NotifierRegistry) Register(name string, notifier Notifier, timeout time.Duration) error {
+	if name == "" {
+		return errors.New("notification channel name must not be empty")
+	}
+	for _, channel := range r.channels {
+		if channel.name == name {
+			return fmt.Errorf("notification channel %q registered twice", name)
+		}
+	}
+	if timeout <= 0 {
+		timeout = DefaultChannelTimeoutSeconds * time.Second
+	}
+	r.channels = append(r.channels, registeredChannel{name: name, notifier: notifier, timeout: timeout})
+	return nil
+}
+
+// Names returns the registered channel names in registration order
+func (r *NotifierRegistry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, len(r.channels))
+	for i, channel := range r.channels {
+		names[i] = channel.name
+	}
+	return names
+}
+
+// Dispatch calls send for each named channel concurrently, each under its own
+// timeout, and waits for all of them. A failing or slow channel does not hold
+// up the others. Results are in registration order; unknown names are ignored.
+func (r *NotifierRegistry) Dispatch(ctx context.Context, names []string, send func(ctx context.Context, name string, notifier Notifier) error) []DeliveryResult {
+	if r == nil {
+		return nil
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var results []DeliveryResult
+	for _, channel := range r.channels {
+		if wanted[channel.name] {
+			results = append(results, DeliveryResult{Channel: channel.name})
+		}
+	}
+
+	var wg sync.WaitGroup
+	i := 0
+	for _, channel := range r.channels {
+		if !wanted[channel.name] {
+			continue
+		}
+		result := &results[i]
+		i++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			channelCtx, cancel := context.WithTimeout(ctx, channel.timeout)
+			defer cancel()
+
+			start := time.Now()
+			// A send that returns nil delivered, even if the deadline passed just after
+			result.Err = sendRecovering(channelCtx, channel, send)
+			result.Duration = time.Since(start)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// sendRecovering calls send, turning a panic in the channel into an error so
+// it cannot take the other channels down
+func sendRecovering(ctx context.Context, channel registeredChannel, send func(context.Context, string, Notifier) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("notifier panicked: %v", r)
+		}
+	}()
+	return send(ctx, channel.name, channel.notifier)
+}
+
+// DeliveredChannels returns the channels whose delivery succeeded
+func DeliveredChannels(results []DeliveryResult) []string {
+	var delivered []string
+	for _, result := range results {
+		if result.Err == nil {
+			delivered = append(delivered, result.Channel)
+		}
+	}
+	return delivered
+}
+
+// DeliveryErrors joins the failed deliveries' errors, prefixed with their channel
+func DeliveryErrors(results []DeliveryResult) error {
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Channel, result.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testNotifiers registers the email notifier and, when set, the push notifier
+// as the pushover channel
+func testNotifiers(email EmailNotifier, push PushNotifier) *NotifierRegistry {
+	registry := NewNotifierRegistry()
+	if email != nil {
+		registry.Register(ChannelEmail, NewEmailChannel(email), 0)
+	}
+	if push != nil {
+		registry.Register(ChannelPushover, NewPushChannel(push, nil, &mockLogger{}), 0)
+	}
+	return registry
+}
+
+// funcNotifier is a channel whose deliveries run alertFunc
+type funcNotifier struct {
+	alertFunc func(ctx context.Context) error
+}
+
+func (n funcNotifier) SendAlert(ctx context.Context, analysis *AlertAnalysis) error {
+	return n.alertFunc(ctx)
+}
+
+func (n funcNotifier) SendRecovery(ctx context.Context, summary *RecoverySummary) error {
+	return n.alertFunc(ctx)
+}
+
+// failingEmail is an email notifier whose server is down
+type failingEmail struct{ recordingEmail }
+
+func (e *failingEmail) SendAlert(ctx context.Context, analysis *AlertAnalysis) error {
+	return errors.New("smtp: connection refused")
+}
+
+func TestNotifierRegistryDispatch(t *testing.T) {
+	// Both fast channels must be running at once for either to finish
+	var started sync.WaitGroup
+	started.Add(2)
+	together := func(ctx context.Context) error {
+		started.Done()
+		started.Wait()
+		return nil
+	}
+
+	registry := NewNotifierRegistry()
+	registry.Register("first", funcNotifier{together}, time.Second)
+	registry.Register("slow", funcNotifier{func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}, 50*time.Millisecond)
+	registry.Register("broken", funcNotifier{func(ctx context.Context) error {
+		panic("nil map")
+	}}, time.Second)
+	registry.Register("second", funcNotifier{together}, time.Second)
+	registry.Register("unused", funcNotifier{func(ctx context.Context) error {
+		t.Error("dispatched to a channel that was not named")
+		return nil
+	}}, time.Second)
+
+	results := registry.Dispatch(context.Background(), []string{"second", "slow", "broken", "first"},
+		func(ctx context.Context, name string, notifier Notifier) error {
+			return notifier.SendAlert(ctx, &AlertAnalysis{})
+		})
+
+	if len(results) != 4 || results[0].Channel != "first" || results[3].Channel != "second" {
+		t.Fatalf("results = %+v, want the named channels in registration order", results)
+	}
+	if !errors.Is(results[1].Err, context.DeadlineExceeded) {
+		t.Errorf("slow channel err = %v, want its timeout", results[1].Err)
+	}
+	if results[2].Err == nil {
+		t.Error("panicking channel reported as delivered")
+	}
+	if got := DeliveredChannels(results); len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Errorf("delivered = %v, want [first second]", got)
+	}
+	if err := DeliveryErrors(results); err == nil {
+		t.Error("DeliveryErrors = nil, want the slow and broken channels' errors")
+	}
+}
+
+func TestNotifierRegistryTrustsSuccessfulSend(t *testing.T) {
+	// The channel delivers, but only returns once its timeout has passed
+	registry := NewNotifierRegistry()
+	registry.Register("late", funcNotifier{func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}}, 10*time.Millisecond)
+
+	results := registry.Dispatch(context.Background(), []string{"late"},
+		func(ctx context.Context, name string, notifier Notifier) error {
+			return notifier.SendAlert(ctx, &AlertAnalysis{})
+		})
+	if len(results) != 1 || results[0].Err != nil {
+		t.Errorf("results = %+v, want the delivered channel reported as delivered", results)
+	}
+}
+
+func TestNotifierRegistryRejectsDuplicateNames(t *testing.T) {
+	registry := NewNotifierRegistry()
+	if err := registry.Register(ChannelEmail, funcNotifier{}, 0); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := registry.Register(ChannelEmail, funcNotifier{}, 0); err == nil {
+		t.Error("registered the same channel twice")
+	}
+	if got := registry.Names(); len(got) != 1 {
+		t.Errorf("Names = %v, want one channel", got)
+	}
+}
+
+func TestCheckAndAlertIsolatesFailingChannel(t *testing.T) {
+	forecast := func(ghi float64) *ForecastData {
+		base := time.Now().Truncate(time.Hour).Add(time.Hour)
+		data := &ForecastData{}
+		for h := 0; h < 6; h++ {
+			data.Hours = append(data.Hours, ForecastHour{
+				Hour:                       base.Add(time.Duration(h) * time.Hour),
+				GlobalHorizontalIrradiance: ghi,
+				Temperature:                25,
+			})
+		}
+		return data
+	}
+	config := &Config{
+		TestMode:                   true,
+		RatedCapacityKW:            5.0,
+		InverterEfficiency:         1.0,
+		ProductionAlertThresholdKW: 2.0,
+		DurationThresholdHours:     6,
+		DaylightGHIThreshold:       50.0,
+		SeverityRoutes:             SeverityRoutes{SeverityWarning: {ChannelEmail, ChannelPush}},
+	}
+
+	t.Run("email down, push delivers", func(t *testing.T) {
+		push := &recordingPush{}
+		state := &memoryState{}
+		service := NewSolarForecastService(config, &stubWeather{forecast: forecast(200)}, nil,
+			testNotifiers(&failingEmail{}, push), nil, state, nil, &mockLogger{})
+
+		if err := service.CheckAndAlert(context.Background()); err != nil {
+			t.Fatalf("CheckAndAlert: %v", err)
+		}
+		if len(push.severities) != 1 {
+			t.Errorf("pushes = %v, want the alert", push.severities)
+		}
+		if state.active == nil {
+			t.Fatal("no episode opened")
+		}
+		if channels := state.active.Notifications[0].Channels; len(channels) != 1 || channels[0] != ChannelPushover {
+			t.Errorf("delivered channels = %v, want [pushover]", channels)
+		}
+	})
+
+	t.Run("every channel down", func(t *testing.T) {
+		state := &memoryState{}
+		service := NewSolarForecastService(config, &stubWeather{forecast: forecast(200)}, nil,
+			testNotifiers(&failingEmail{}, nil), nil, state, nil, &mockLogger{})
+
+		if err := service.CheckAndAlert(context.Background()); err == nil {
+			t.Error("CheckAndAlert succeeded with no channel delivering")
+		}
+		if state.active != nil {
+			t.Error("episode opened although the alert was not delivered")
+		}
+	})
+}
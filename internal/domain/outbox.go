@@ -0,0 +1,239 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// maxOutboxRetryDelay caps the doubling delay between outbox retries
+const maxOutboxRetryDelay = 6 * time.Hour
+
+// errNotAttempted marks a queued message the channel did not get to before failing
+var errNotAttempted = errors.New("delivery not attempted")
+
+// OutboxMessage is a notification waiting to be delivered to one channel. It
+// carries the analysis it was created from, so a retry sends what the first
+// attempt would have sent.
+type OutboxMessage struct {
+	Key         string // Dedup key; a newer message with the same key replaces a pending one
+	Channel     string
+	Kind        NotificationKind
+	EpisodeID   string
+	Analysis    *AlertAnalysis   // Alerts and updates
+	Recovery    *RecoverySummary // Recoveries
+	Attempts    int              // Deliveries tried so far
+	CreatedAt   time.Time
+	NextAttempt time.Time // Not retried before this time
+	LastError   string
+}
+
+// OutboxKey returns the dedup key of a notification: one message per episode,
+// kind and channel, so a newer update supersedes one still waiting
+func OutboxKey(episodeID string, kind NotificationKind, channel string) string {
+	return episodeID + "/" + string(kind) + "/" + channel
+}
+
+// Send delivers the message on a channel
+func (m *OutboxMessage) Send(ctx context.Context, notifier Notifier) error {
+	if m.Kind == NotificationRecovery {
+		return notifier.SendRecovery(ctx, m.Recovery)
+	}
+	return notifier.SendAlert(ctx, m.Analysis)
+}
+
+// MaxAttemptsOrDefault returns the configured attempts, or the default when unset
+func (c OutboxConfig) MaxAttemptsOrDefault() int {
+	if c.MaxAttempts <= 0 {
+		return DefaultOutboxMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+// RetryDelay returns the wait after the given number of attempts: the
+// configured delay, doubled after each further attempt and capped at six hours
+func (c OutboxConfig) RetryDelay(attempts int) time.Duration {
+	delay := time.Duration(c.RetryDelayMinutes) * time.Minute
+	if c.RetryDelayMinutes <= 0 {
+		delay = DefaultOutboxRetryDelayMinutes * time.Minute
+	}
+	for i := 1; i < attempts && delay < maxOutboxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxOutboxRetryDelay)
+}
+
+// queueFailed stores the deliveries that failed in the outbox for a later run
+// and returns how many were queued
+func (s *SolarForecastService) queueFailed(ctx context.Context, results []DeliveryResult, message OutboxMessage, now time.Time) int {
+	if s.outbox == nil {
+		return 0
+	}
+
+	queued := 0
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		failed := message
+		failed.Key = OutboxKey(message.EpisodeID, message.Kind, result.Channel)
+		failed.Channel = result.Channel
+		failed.Attempts = 1
+		failed.CreatedAt = now
+		failed.LastError = result.Err.Error()
+		if s.retryOrDeadLetter(ctx, &failed, now) {
+			queued++
+		}
+	}
+	return queued
+}
+
+// drainOutbox retries the queued notifications that are due, before the run
+// sends its own. Alerts and updates for an episode that has since closed are
+// dropped, as the episode's recovery has superseded them. Failures are logged
+// and do not fail the run.
+func (s *SolarForecastService) drainOutbox(ctx context.Context, now time.Time) {
+	if s.outbox == nil {
+		return
+	}
+	pending, err := s.outbox.Pending(ctx)
+	if err != nil {
+		s.logger.Error("Failed to read notification outbox", "error", err.Error())
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+	episode, err := s.stateRepository.ActiveEpisode(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get alert state for the notification outbox", "error", err.Error())
+		return
+	}
+
+	registered := make(map[string]bool)
+	for _, name := range s.notifiers.Names() {
+		registered[name] = true
+	}
+
+	due := make(map[string][]*OutboxMessage)
+	var channels []string
+	for i := range pending {
+		message := &pending[i]
+		switch {
+		case message.Kind != NotificationRecovery && (episode == nil || episode.ID != message.EpisodeID):
+			s.logger.Info("Dropping queued notification for a closed alert episode", "key", message.Key)
+			if err := s.outbox.Remove(ctx, message.Key); err != nil {
+				s.logger.Error("Failed to remove notification from outbox", "key", message.Key, "error", err.Error())
+			}
+		case !registered[message.Channel]:
+			message.LastError = "notification channel no longer configured"
+			s.deadLetter(ctx, message)
+		case message.NextAttempt.After(now):
+			s.logger.Debug("Queued notification not due yet", "key", message.Key, "next_attempt", message.NextAttempt.Format(time.RFC3339))
+		default:
+			if len(due[message.Channel]) == 0 {
+				channels = append(channels, message.Channel)
+			}
+			due[message.Channel] = append(due[message.Channel], message)
+		}
+	}
+	if len(channels) == 0 {
+		return
+	}
+
+	// Each channel sends its messages in order; channels run concurrently
+	outcomes := make(map[string][]error, len(due))
+	for name, messages := range due {
+		outcomes[name] = make([]error, len(messages))
+		for i := range outcomes[name] {
+			outcomes[name][i] = errNotAttempted
+		}
+	}
+	// A channel that is still down may use only part of the run, so the current
+	// forecast and alert keep the rest
+	drainCtx, cancel := context.WithTimeout(ctx, s.drainTimeout(ctx))
+	defer cancel()
+	s.logger.Info("Retrying queued notifications", "channels", channels)
+	s.notifiers.Dispatch(drainCtx, channels, func(ctx context.Context, name string, notifier Notifier) error {
+		var errs []error
+		for i, message := range due[name] {
+			outcomes[name][i] = message.Send(ctx, notifier)
+			errs = append(errs, outcomes[name][i])
+		}
+		return errors.Join(errs...)
+	})
+
+	recorded := false
+	for _, name := range channels {
+		for i, message := range due[name] {
+			if err := outcomes[name][i]; err != nil {
+				s.logger.Warn("Queued notification failed again",
+					"key", message.Key,
+					"attempt", message.Attempts+1,
+					"error", err.Error())
+				message.Attempts++
+				message.LastError = err.Error()
+				s.retryOrDeadLetter(ctx, message, now)
+				continue
+			}
+
+			s.logger.Info("Queued notification delivered", "key", message.Key, "attempt", message.Attempts+1)
+			if err := s.outbox.Remove(ctx, message.Key); err != nil {
+				s.logger.Error("Failed to remove notification from outbox", "key", message.Key, "error", err.Error())
+			}
+			if message.Kind != NotificationRecovery {
+				episode.RecordNotification(message.Kind, []string{name}, now)
+				recorded = true
+			}
+		}
+	}
+	if recorded {
+		if err := s.stateRepository.UpdateEpisode(ctx, *episode); err != nil {
+			s.logger.Error("Failed to record queued notifications", "error", err.Error())
+		}
+	}
+}
+
+// drainTimeout returns how long the outbox retries may take: half of what is
+// left of the run
+func (s *SolarForecastService) drainTimeout(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline) / 2
+	}
+	runTimeout := s.config.RunTimeoutSeconds
+	if runTimeout <= 0 {
+		runTimeout = DefaultRunTimeoutSeconds
+	}
+	return time.Duration(runTimeout) * time.Second / 2
+}
+
+// retryOrDeadLetter schedules the message's next attempt, or dead-letters it
+// when it has used up its attempts. It reports whether the message was queued.
+func (s *SolarForecastService) retryOrDeadLetter(ctx context.Context, message *OutboxMessage, now time.Time) bool {
+	if message.Attempts >= s.config.Outbox.MaxAttemptsOrDefault() {
+		s.deadLetter(ctx, message)
+		return false
+	}
+
+	message.NextAttempt = now.Add(s.config.Outbox.RetryDelay(message.Attempts))
+	if err := s.outbox.Enqueue(ctx, *message); err != nil {
+		s.logger.Error("Failed to queue notification for retry", "key", message.Key, "error", err.Error())
+		return false
+	}
+	s.logger.Info("Notification queued for retry",
+		"key", message.Key,
+		"attempts", message.Attempts,
+		"next_attempt", message.NextAttempt.Format(time.RFC3339))
+	return true
+}
+
+// deadLetter gives up on a message, keeping it in the outbox's dead-letter list
+func (s *SolarForecastService) deadLetter(ctx context.Context, message *OutboxMessage) {
+	s.logger.Error("Giving up on notification",
+		"key", message.Key,
+		"attempts", message.Attempts,
+		"error", message.LastError)
+	if err := s.outbox.DeadLetter(ctx, *message); err != nil {
+		s.logger.Error("Failed to dead-letter notification", "key", message.Key, "error", err.Error())
+	}
+}
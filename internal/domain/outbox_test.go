@@ -0,0 +1,287 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// memoryOutbox keeps queued notifications in memory
+type memoryOutbox struct {
+	pending     []OutboxMessage
+	deadLetters []OutboxMessage
+}
+
+func (m *memoryOutbox) Pending(ctx context.Context) ([]OutboxMessage, error) {
+	return append([]OutboxMessage(nil), m.pending...), nil
+}
+
+func (m *memoryOutbox) Enqueue(ctx context.Context, message OutboxMessage) error {
+	m.Remove(ctx, message.Key)
+	m.pending = append(m.pending, message)
+	return nil
+}
+
+func (m *memoryOutbox) Remove(ctx context.Context, key string) error {
+	for i, message := range m.pending {
+		if message.Key == key {
+			m.pending = append(m.pending[:i], m.pending[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *memoryOutbox) DeadLetter(ctx context.Context, message OutboxMessage) error {
+	m.Remove(ctx, message.Key)
+	m.deadLetters = append(m.deadLetters, message)
+	return nil
+}
+
+func (m *memoryOutbox) DeadLetters(ctx context.Context) ([]OutboxMessage, error) {
+	return m.deadLetters, nil
+}
+
+// makeDue moves every pending message's next attempt into the past
+func (m *memoryOutbox) makeDue() {
+	for i := range m.pending {
+		m.pending[i].NextAttempt = time.Now().Add(-time.Minute)
+	}
+}
+
+// flakyPush is a push notifier that fails while its service is down
+type flakyPush struct {
+	down                bool
+	attempts, delivered int
+}
+
+func (p *flakyPush) SendNotification(ctx context.Context, title, message string, imageData []byte, severity Severity) error {
+	p.attempts++
+	if p.down {
+		return errors.New("503 Service Unavailable")
+	}
+	p.delivered++
+	return nil
+}
+
+// newOutboxTestService alerts by email and push, queueing failures in outbox
+func newOutboxTestService(weather *stubWeather, push PushNotifier, state *memoryState, outbox *memoryOutbox, maxAttempts int) *SolarForecastService {
+	return NewSolarForecastService(
+		&Config{
+			TestMode:                   true,
+			RatedCapacityKW:            5.0,
+			InverterEfficiency:         1.0,
+			ProductionAlertThresholdKW: 2.0,
+			DurationThresholdHours:     6,
+			DaylightGHIThreshold:       50.0,
+			SeverityRoutes:             SeverityRoutes{SeverityWarning: {ChannelEmail, ChannelPush}},
+			Outbox:                     OutboxConfig{MaxAttempts: maxAttempts, RetryDelayMinutes: 15},
+		},
+		weather, nil, testNotifiers(&recordingEmail{}, push), nil, state, outbox, &mockLogger{},
+	)
+}
+
+// outboxTestForecast returns six hours at the given irradiance
+func outboxTestForecast(ghi float64) *ForecastData {
+	base := time.Now().Truncate(time.Hour).Add(time.Hour)
+	data := &ForecastData{}
+	for h := 0; h < 6; h++ {
+		data.Hours = append(data.Hours, ForecastHour{
+			Hour:                       base.Add(time.Duration(h) * time.Hour),
+			GlobalHorizontalIrradiance: ghi,
+			Temperature:                25,
+		})
+	}
+	return data
+}
+
+func TestOutboxRetriesFailedChannelOnLaterRun(t *testing.T) {
+	ctx := context.Background()
+	weather := &stubWeather{forecast: outboxTestForecast(200)}
+	push := &flakyPush{down: true}
+	state := &memoryState{}
+	outbox := &memoryOutbox{}
+	service := newOutboxTestService(weather, push, state, outbox, 6)
+
+	if err := service.CheckAndAlert(ctx); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if len(outbox.pending) != 1 {
+		t.Fatalf("pending = %+v, want the failed push", outbox.pending)
+	}
+	queued := outbox.pending[0]
+	if queued.Channel != ChannelPushover || queued.Kind != NotificationAlert || queued.Attempts != 1 ||
+		queued.EpisodeID != state.active.ID || queued.Analysis == nil || !queued.NextAttempt.After(time.Now()) {
+		t.Errorf("queued message = %+v", queued)
+	}
+	if want := state.active.ID + "/alert"; queued.Analysis != nil && queued.Analysis.NotificationID != want {
+		t.Errorf("queued notification ID = %q, want %q kept for retries", queued.Analysis.NotificationID, want)
+	}
+
+	// Not due yet: the next run leaves it alone
+	if err := service.CheckAndAlert(ctx); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if push.attempts != 1 {
+		t.Errorf("push attempts = %d, want no retry before the next attempt time", push.attempts)
+	}
+
+	// Once due and the service is back, the stored alert is delivered and recorded
+	outbox.makeDue()
+	push.down = false
+	if err := service.CheckAndAlert(ctx); err != nil {
+		t.Fatalf("third run: %v", err)
+	}
+	if push.delivered != 1 || len(outbox.pending) != 0 {
+		t.Errorf("delivered %d, pending %+v; want the queued alert delivered once", push.delivered, outbox.pending)
+	}
+	if !state.active.DeliveredTo(ChannelPushover) {
+		t.Errorf("episode notifications = %+v, want the retried push recorded", state.active.Notifications)
+	}
+}
+
+func TestOutboxDeadLettersAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	push := &flakyPush{down: true}
+	outbox := &memoryOutbox{}
+	service := newOutboxTestService(&stubWeather{forecast: outboxTestForecast(200)}, push, &memoryState{}, outbox, 2)
+
+	if err := service.CheckAndAlert(ctx); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	outbox.makeDue()
+	if err := service.CheckAndAlert(ctx); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+
+	if len(outbox.pending) != 0 || len(outbox.deadLetters) != 1 {
+		t.Fatalf("pending %+v, dead letters %+v; want the push dead-lettered", outbox.pending, outbox.deadLetters)
+	}
+	if dead := outbox.deadLetters[0]; dead.Attempts != 2 || dead.LastError != "503 Service Unavailable" {
+		t.Errorf("dead letter = %+v", dead)
+	}
+}
+
+func TestOutboxDropsAlertForClosedEpisode(t *testing.T) {
+	ctx := context.Background()
+	weather := &stubWeather{forecast: outboxTestForecast(200)}
+	push := &flakyPush{down: true}
+	state := &memoryState{}
+	outbox := &memoryOutbox{}
+	service := newOutboxTestService(weather, push, state, outbox, 6)
+
+	if err := service.CheckAndAlert(ctx); err != nil {
+		t.Fatalf("alert run: %v", err)
+	}
+
+	// Production recovers; the push never reached pushover, so the all-clear goes by email only
+	weather.forecast = outboxTestForecast(800)
+	if err := service.CheckAndAlert(ctx); err != nil {
+		t.Fatalf("recovery run: %v", err)
+	}
+	if state.active != nil || len(state.closed) != 1 {
+		t.Fatal("episode not resolved")
+	}
+
+	outbox.makeDue()
+	push.down = false
+	if err := service.CheckAndAlert(ctx); err != nil {
+		t.Fatalf("run after recovery: %v", err)
+	}
+	if push.delivered != 0 || len(outbox.pending) != 0 || len(outbox.deadLetters) != 0 {
+		t.Errorf("delivered %d, pending %+v, dead %+v; want the stale alert dropped", push.delivered, outbox.pending, outbox.deadLetters)
+	}
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	config := OutboxConfig{RetryDelayMinutes: 15}
+	tests := map[int]time.Duration{
+		1:  15 * time.Minute,
+		2:  30 * time.Minute,
+		4:  2 * time.Hour,
+		10: 6 * time.Hour,
+	}
+	for attempts, want := range tests {
+		if got := config.RetryDelay(attempts); got != want {
+			t.Errorf("RetryDelay(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+	if got := (OutboxConfig{}).RetryDelay(1); got != DefaultOutboxRetryDelayMinutes*time.Minute {
+		t.Errorf("unset RetryDelay(1) = %v, want the default", got)
+	}
+}
+
+func TestOutboxRetryDoesNotHoldUpCurrentAlert(t *testing.T) {
+	// The run's budget is shorter than the hanging channel's own timeout; the
+	// retry must leave the current alert enough of it
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	email := &recordingEmail{}
+	registry := testNotifiers(email, nil)
+	registry.Register(ChannelSlack, funcNotifier{func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}, 10*time.Second)
+
+	state := &memoryState{}
+	outbox := &memoryOutbox{}
+	service := NewSolarForecastService(
+		&Config{
+			TestMode:                   true,
+			RatedCapacityKW:            5.0,
+			InverterEfficiency:         1.0,
+			ProductionAlertThresholdKW: 2.0,
+			DurationThresholdHours:     6,
+			DaylightGHIThreshold:       50.0,
+			SeverityRoutes:             SeverityRoutes{SeverityWarning: {ChannelEmail}},
+		},
+		&stubWeather{forecast: outboxTestForecast(200)}, nil, registry, nil, state, outbox, &mockLogger{},
+	)
+	outbox.pending = []OutboxMessage{{
+		Key:       OutboxKey("ep-old", NotificationRecovery, ChannelSlack),
+		Channel:   ChannelSlack,
+		Kind:      NotificationRecovery,
+		EpisodeID: "ep-old",
+		Recovery:  &RecoverySummary{},
+		Attempts:  1,
+	}}
+
+	if err := service.CheckAndAlert(ctx); err != nil {
+		t.Fatalf("CheckAndAlert: %v", err)
+	}
+	if email.alerts != 1 || state.active == nil || !state.active.DeliveredTo(ChannelEmail) {
+		t.Errorf("alerts = %d, episode = %+v; want the current alert emailed", email.alerts, state.active)
+	}
+	if len(outbox.pending) != 1 || outbox.pending[0].Attempts != 2 {
+		t.Errorf("pending = %+v, want the hanging channel's recovery queued again", outbox.pending)
+	}
+}
+
+func TestOutboxRetriedWhileForecastUnavailable(t *testing.T) {
+	ctx := context.Background()
+	weather := &stubWeather{forecast: outboxTestForecast(200)}
+	push := &flakyPush{down: true}
+	state := &memoryState{}
+	outbox := &memoryOutbox{}
+	service := newOutboxTestService(weather, push, state, outbox, 6)
+
+	if err := service.CheckAndAlert(ctx); err != nil {
+		t.Fatalf("alert run: %v", err)
+	}
+
+	// The forecast API is down, but the push service is back
+	weather.err = errors.New("open-meteo: 502 Bad Gateway")
+	outbox.makeDue()
+	push.down = false
+	if err := service.CheckAndAlert(ctx); err == nil {
+		t.Fatal("run without a forecast succeeded")
+	}
+	if push.delivered != 1 || len(outbox.pending) != 0 {
+		t.Errorf("delivered %d, pending %+v; want the queued alert sent despite the forecast error", push.delivered, outbox.pending)
+	}
+	if !state.active.DeliveredTo(ChannelPushover) {
+		t.Errorf("episode notifications = %+v, want the retried push recorded", state.active.Notifications)
+	}
+}
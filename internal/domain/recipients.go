@@ -0,0 +1,56 @@
+package domain
+
+// RecipientField is the header an email recipient is addressed in
+type RecipientField string
+
+const (
+	RecipientTo  RecipientField = "to"
+	RecipientCc  RecipientField = "cc"
+	RecipientBcc RecipientField = "bcc" // Envelope only, never written to the headers
+)
+
+// EmailKind identifies a type of email a recipient can opt into
+type EmailKind string
+
+const (
+	EmailAlert    EmailKind = "alert" // Alerts and alert updates
+	EmailRecovery EmailKind = "recovery"
+	EmailDigest   EmailKind = "digest" // Stored for a digest sender; no digest email is sent yet
+)
+
+// DefaultRecipientKinds are the emails a recipient gets when none are configured
+var DefaultRecipientKinds = []EmailKind{EmailAlert, EmailRecovery}
+
+// EmailRecipient is one address on the email recipient list
+type EmailRecipient struct {
+	Name        string         // Config section name, used in logs and errors
+	Address     string         // RFC 5322 address, optionally with a display name
+	Field       RecipientField // To, Cc or Bcc
+	Kinds       []EmailKind    // Emails the recipient opted into
+	MinSeverity Severity       // Least serious alert the recipient wants
+}
+
+// Wants reports whether the recipient opted into an email of the kind at the severity.
+// A recovery is matched against the highest severity of the episode it resolves.
+func (r EmailRecipient) Wants(kind EmailKind, severity Severity) bool {
+	if severity.Rank() < r.MinSeverity.Rank() {
+		return false
+	}
+	for _, k := range r.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// RecipientsFor returns the recipients that want an email of the kind at the severity
+func RecipientsFor(recipients []EmailRecipient, kind EmailKind, severity Severity) []EmailRecipient {
+	var matched []EmailRecipient
+	for _, r := range recipients {
+		if r.Wants(kind, severity) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
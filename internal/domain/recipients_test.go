@@ -0,0 +1,29 @@
+package domain
+
+import "testing"
+
+func TestEmailRecipientWants(t *testing.T) {
+	recipient := EmailRecipient{Kinds: []EmailKind{EmailAlert}, MinSeverity: SeverityWarning}
+
+	tests := []struct {
+		kind     EmailKind
+		severity Severity
+		want     bool
+	}{
+		{EmailAlert, SeverityInfo, false},
+		{EmailAlert, SeverityWarning, true},
+		{EmailAlert, SeverityCritical, true},
+		{EmailRecovery, SeverityCritical, false},
+		{EmailDigest, SeverityCritical, false},
+	}
+	for _, tt := range tests {
+		if got := recipient.Wants(tt.kind, tt.severity); got != tt.want {
+			t.Errorf("Wants(%s, %s) = %v, want %v", tt.kind, tt.severity, got, tt.want)
+		}
+	}
+
+	digest := EmailRecipient{Kinds: []EmailKind{EmailDigest}, MinSeverity: SeverityInfo}
+	if !digest.Wants(EmailDigest, SeverityInfo) || digest.Wants(EmailAlert, SeverityCritical) {
+		t.Error("a digest-only recipient should want the digest and nothing else")
+	}
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// RecoverySummary describes a resolved alert episode for the recovery notification
+type RecoverySummary struct {
+	Episode    AlertEpisode
+	Analysis   *AlertAnalysis // Current forecast, which no longer triggers any rule
+	ResolvedAt time.Time
+
+	// Expected energy on the alert's production basis
+	RestOfTodayKWh   float64 // From the current hour until midnight
+	TomorrowKWh      float64
+	TomorrowComplete bool // False when the forecast does not cover all of tomorrow
+
+	NotificationID string // Identifies the recovery notification; retries keep it
+}
+
+// NewRecoverySummary builds the summary for an episode resolved at now
+func NewRecoverySummary(episode AlertEpisode, analysis *AlertAnalysis, now time.Time) *RecoverySummary {
+	summary := &RecoverySummary{Episode: episode, Analysis: analysis, ResolvedAt: now}
+
+	currentHour := now.Truncate(time.Hour)
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	for _, prod := range analysis.AllProductionHours {
+		if !prod.Hour.Before(currentHour) && prod.Hour.Before(tomorrow) {
+			summary.RestOfTodayKWh += prod.OutputAt(analysis.AlertQuantile)
+		}
+	}
+	for _, day := range analysis.DailyEnergy {
+		if day.Date.Equal(tomorrow) {
+			summary.TomorrowKWh = day.EnergyKWh
+			summary.TomorrowComplete = day.Complete
+		}
+	}
+	return summary
+}
+
+// ForecastLowDuration returns how long the first alert forecast the low period to last
+func (r *RecoverySummary) ForecastLowDuration() time.Duration {
+	initial := r.Episode.Initial
+	if initial.FirstLowHour.IsZero() {
+		return 0
+	}
+	return initial.LastLowHour.Sub(initial.FirstLowHour) + time.Hour
+}
+
+// LowDuration returns how long the low period lasted: from its forecast start until
+// the last expected end seen before resolution, or the resolution time if that is earlier
+func (r *RecoverySummary) LowDuration() time.Duration {
+	start := r.Episode.Initial.FirstLowHour
+	if start.IsZero() || start.After(r.ResolvedAt) {
+		start = r.Episode.StartedAt
+	}
+
+	end := r.ResolvedAt
+	if expected := r.Episode.ExpectedEnd; !expected.IsZero() && expected.After(start) && expected.Before(end) {
+		end = expected
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
@@ -0,0 +1,255 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewRecoverySummary(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2025, 6, 21, 14, 30, 0, 0, loc)
+
+	// 2 kW every hour from today 00:00 to the end of tomorrow
+	var hours []SolarProduction
+	for h := 0; h < 48; h++ {
+		hours = append(hours, SolarProduction{
+			Hour:              time.Date(2025, 6, 21, 0, 0, 0, 0, loc).Add(time.Duration(h) * time.Hour),
+			EstimatedOutputKW: 2.0,
+		})
+	}
+	analysis := &AlertAnalysis{
+		AllProductionHours: hours,
+		DailyEnergy:        DailyEnergyTotals(hours, AlertOnDeterministic),
+		AlertQuantile:      AlertOnDeterministic,
+	}
+
+	// First alert forecast 09:00-14:00 (6 hours); the low period ended at 13:00
+	episode := AlertEpisode{
+		StartedAt:   time.Date(2025, 6, 21, 7, 0, 0, 0, loc),
+		ExpectedEnd: time.Date(2025, 6, 21, 13, 0, 0, 0, loc),
+		Initial: AlertFingerprint{
+			FirstLowHour: time.Date(2025, 6, 21, 9, 0, 0, 0, loc),
+			LastLowHour:  time.Date(2025, 6, 21, 14, 0, 0, 0, loc),
+		},
+	}
+
+	summary := NewRecoverySummary(episode, analysis, now)
+
+	// 14:00 through 23:00 is 10 hours at 2 kW
+	if summary.RestOfTodayKWh != 20 {
+		t.Errorf("RestOfTodayKWh = %.1f, want 20", summary.RestOfTodayKWh)
+	}
+	if summary.TomorrowKWh != 48 || !summary.TomorrowComplete {
+		t.Errorf("tomorrow = %.1f kWh (complete %v), want 48 complete", summary.TomorrowKWh, summary.TomorrowComplete)
+	}
+	if got := summary.ForecastLowDuration(); got != 6*time.Hour {
+		t.Errorf("ForecastLowDuration = %v, want 6h", got)
+	}
+	if got := summary.LowDuration(); got != 4*time.Hour {
+		t.Errorf("LowDuration = %v, want 4h", got)
+	}
+}
+
+func TestRecoveryPushFollowsEpisodeChannels(t *testing.T) {
+	forecast := func(ghi float64) *ForecastData {
+		base := time.Now().Truncate(time.Hour).Add(time.Hour)
+		data := &ForecastData{}
+		for h := 0; h < 6; h++ {
+			data.Hours = append(data.Hours, ForecastHour{
+				Hour:                       base.Add(time.Duration(h) * time.Hour),
+				GlobalHorizontalIrradiance: ghi,
+				Temperature:                25,
+			})
+		}
+		return data
+	}
+
+	tests := []struct {
+		name      string
+		routes    SeverityRoutes
+		wantPush  []Severity
+		wantNotes []string
+	}{
+		{
+			name:      "pushed alert gets a pushed all-clear",
+			routes:    SeverityRoutes{SeverityWarning: {ChannelEmail, ChannelPush}},
+			wantPush:  []Severity{SeverityWarning, SeverityInfo},
+			wantNotes: []string{ChannelEmail, ChannelPushover},
+		},
+		{
+			name:      "email-only alert recovers by email only",
+			routes:    SeverityRoutes{SeverityWarning: {ChannelEmail}},
+			wantNotes: []string{ChannelEmail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weather := &stubWeather{forecast: forecast(200)}
+			email := &recordingEmail{}
+			push := &recordingPush{}
+			state := &memoryState{}
+			service := NewSolarForecastService(
+				&Config{
+					TestMode:                   true,
+					RatedCapacityKW:            5.0,
+					InverterEfficiency:         1.0,
+					ProductionAlertThresholdKW: 2.0,
+					DurationThresholdHours:     6,
+					DaylightGHIThreshold:       50.0,
+					SeverityRoutes:             tt.routes,
+				},
+				weather, nil, testNotifiers(email, push), nil, state, nil, &mockLogger{},
+			)
+
+			for _, ghi := range []float64{200, 800, 800} {
+				weather.forecast = forecast(ghi)
+				if err := service.CheckAndAlert(context.Background()); err != nil {
+					t.Fatalf("CheckAndAlert: %v", err)
+				}
+			}
+
+			if email.recoveries != 1 {
+				t.Errorf("recovery emails = %d, want 1", email.recoveries)
+			}
+			if len(push.severities) != len(tt.wantPush) {
+				t.Fatalf("pushes = %v, want %v", push.severities, tt.wantPush)
+			}
+			for i, severity := range tt.wantPush {
+				if push.severities[i] != severity {
+					t.Errorf("push %d severity = %q, want %q", i, push.severities[i], severity)
+				}
+			}
+
+			notifications := state.closed[0].Notifications
+			recovery := notifications[len(notifications)-1]
+			if recovery.Kind != NotificationRecovery || len(recovery.Channels) != len(tt.wantNotes) {
+				t.Errorf("recovery notification = %+v, want channels %v", recovery, tt.wantNotes)
+			}
+		})
+	}
+}
+
+// recoveryDetailPush is a push notifier that carries the recovery summary
+type recoveryDetailPush struct {
+	recordingPush
+	summaries []*RecoverySummary
+}
+
+func (p *recoveryDetailPush) SendRecoveryNotification(ctx context.Context, title, message string, summary *RecoverySummary, imageData []byte) error {
+	p.summaries = append(p.summaries, summary)
+	return nil
+}
+
+func TestRecoveryPushPassesSummaryToDetailedPush(t *testing.T) {
+	base := time.Now().Truncate(time.Hour).Add(time.Hour)
+	forecast := func(ghi float64) *ForecastData {
+		data := &ForecastData{}
+		for h := 0; h < 6; h++ {
+			data.Hours = append(data.Hours, ForecastHour{
+				Hour:                       base.Add(time.Duration(h) * time.Hour),
+				GlobalHorizontalIrradiance: ghi,
+				Temperature:                25,
+			})
+		}
+		return data
+	}
+
+	weather := &stubWeather{forecast: forecast(200)}
+	push := &recoveryDetailPush{}
+	service := NewSolarForecastService(
+		&Config{
+			TestMode:                   true,
+			RatedCapacityKW:            5.0,
+			InverterEfficiency:         1.0,
+			ProductionAlertThresholdKW: 2.0,
+			DurationThresholdHours:     6,
+			DaylightGHIThreshold:       50.0,
+			SeverityRoutes:             SeverityRoutes{SeverityWarning: {ChannelEmail, ChannelPush}},
+		},
+		weather, nil, testNotifiers(&recordingEmail{}, push), nil, &memoryState{}, nil, &mockLogger{},
+	)
+
+	for _, ghi := range []float64{200, 800, 800} {
+		weather.forecast = forecast(ghi)
+		if err := service.CheckAndAlert(context.Background()); err != nil {
+			t.Fatalf("CheckAndAlert: %v", err)
+		}
+	}
+
+	if len(push.summaries) != 1 || len(push.severities) != 1 {
+		t.Fatalf("detailed recoveries = %d, plain pushes = %d, want the alert plain and the recovery with its summary", len(push.summaries), len(push.severities))
+	}
+	if got := push.summaries[0]; got.Episode.ID == "" || got.Analysis == nil {
+		t.Errorf("summary = %+v, want the resolved episode and current analysis", got)
+	}
+}
+
+func TestSupersededEpisodeRecoversBeforeNextAlert(t *testing.T) {
+	now := time.Now()
+	base := now.Truncate(time.Hour).Add(time.Hour)
+	forecast := &ForecastData{}
+	for h := 0; h < 6; h++ {
+		forecast.Hours = append(forecast.Hours, ForecastHour{
+			Hour:                       base.Add(time.Duration(h) * time.Hour),
+			GlobalHorizontalIrradiance: 200,
+			Temperature:                25,
+		})
+	}
+
+	// The first low period ended an hour ago; the forecast shows a second one later on
+	previous := AlertEpisode{
+		ID:          "ep-previous",
+		StartedAt:   now.Add(-8 * time.Hour),
+		ExpectedEnd: now.Add(-time.Hour),
+		Severity:    SeverityWarning,
+		Initial: AlertFingerprint{
+			FirstLowHour: now.Add(-7 * time.Hour),
+			LastLowHour:  now.Add(-2 * time.Hour),
+		},
+		Notifications: []EpisodeNotification{
+			{Kind: NotificationAlert, Channels: []string{ChannelEmail, ChannelPushover}, SentAt: now.Add(-8 * time.Hour)},
+		},
+	}
+	previous.Latest = previous.Initial
+
+	email := &recordingEmail{}
+	push := &recordingPush{}
+	state := &memoryState{active: &previous}
+	service := NewSolarForecastService(
+		&Config{
+			TestMode:                   true,
+			RatedCapacityKW:            5.0,
+			InverterEfficiency:         1.0,
+			ProductionAlertThresholdKW: 2.0,
+			DurationThresholdHours:     6,
+			DaylightGHIThreshold:       50.0,
+			SeverityRoutes:             SeverityRoutes{SeverityWarning: {ChannelEmail, ChannelPush}},
+		},
+		&stubWeather{forecast: forecast}, nil, testNotifiers(email, push), nil, state, nil, &mockLogger{},
+	)
+
+	if err := service.CheckAndAlert(context.Background()); err != nil {
+		t.Fatalf("CheckAndAlert: %v", err)
+	}
+
+	if email.recoveries != 1 || email.alerts != 1 {
+		t.Errorf("emails: %d recoveries, %d alerts, want 1 of each", email.recoveries, email.alerts)
+	}
+	wantPush := []Severity{SeverityInfo, SeverityWarning}
+	if len(push.severities) != len(wantPush) || push.severities[0] != wantPush[0] || push.severities[1] != wantPush[1] {
+		t.Errorf("pushes = %v, want the recovery then the new alert %v", push.severities, wantPush)
+	}
+
+	if len(state.closed) != 1 || state.closed[0].ID != previous.ID {
+		t.Fatalf("closed episodes = %+v, want only %s", state.closed, previous.ID)
+	}
+	notifications := state.closed[0].Notifications
+	if last := notifications[len(notifications)-1]; last.Kind != NotificationRecovery || len(last.Channels) != 2 {
+		t.Errorf("last notification of the previous episode = %+v, want a recovery on both channels", last)
+	}
+	if state.active == nil || state.active.ID == previous.ID {
+		t.Errorf("active episode = %+v, want a new episode for the second low period", state.active)
+	}
+}
@@ -0,0 +1,227 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Built-in alert rule types
+const (
+	RuleTypeDuration    = "duration"     // Production below a kW level for N consecutive daylight hours
+	RuleTypeDailyEnergy = "daily_energy" // A day's energy below a kWh level
+	RuleTypePeak        = "peak"         // A day's peak output below a kW level
+	RuleTypeCloudCover  = "cloud_cover"  // Cloud cover at or above a percentage for N consecutive daylight hours
+)
+
+// AlertRule evaluates one alert condition against the production forecast
+type AlertRule interface {
+	// Name returns the configured rule name
+	Name() string
+	// Type returns the registry type the rule was built from
+	Type() string
+	// Evaluate checks the forecast and reports whether the rule fired
+	Evaluate(input RuleInput) RuleResult
+}
+
+// RuleInput is the forecast data every rule is evaluated against
+type RuleInput struct {
+	DaylightHours        []SolarProduction // Hours with GHI at or above the daylight threshold
+	AllHours             []SolarProduction // Every forecast hour, including night
+	DailyEnergy          []DailyEnergy     // Per-calendar-day energy totals
+	Quantile             AlertQuantile     // Production estimate rules should compare against
+	DaylightGHIThreshold float64
+	Tiers                SeverityTiers // Tier boundaries for rules without a fixed severity
+	Locale               *Locale       // Language of the rule messages (nil = English)
+}
+
+// RuleResult is the outcome of evaluating one alert rule
+type RuleResult struct {
+	Name      string
+	Type      string
+	Fired     bool
+	Severity  Severity
+	Threshold float64           // Level the rule compares against: kW, kWh or percent cloud cover by type
+	Message   string            // Human-readable summary of why the rule fired
+	Evidence  []SolarProduction // Hours that caused the rule to fire
+	Days      []DailyEnergy     // Days that caused the rule to fire (day-based rules)
+
+	// When the condition is expected to clear (hour-based rules)
+	HasRecovery  bool
+	RecoveryHour time.Time
+}
+
+// AlertRuleConfig is one configured rule instance (alert_rule.<name>.<param>)
+type AlertRuleConfig struct {
+	Name     string
+	Type     string
+	Severity Severity // Fixed severity; empty to derive it from the severity tiers
+	Params   RuleParams
+}
+
+// RuleParams holds a rule's raw parameters
+type RuleParams map[string]string
+
+// Float returns a float parameter, or the fallback when it is not set
+func (p RuleParams) Float(key string, fallback float64) (float64, error) {
+	raw, ok := p[key]
+	if !ok {
+		return fallback, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number, got %q", key, raw)
+	}
+	return v, nil
+}
+
+// Int returns an integer parameter, or the fallback when it is not set
+func (p RuleParams) Int(key string, fallback int) (int, error) {
+	raw, ok := p[key]
+	if !ok {
+		return fallback, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer, got %q", key, raw)
+	}
+	return v, nil
+}
+
+// checkKnown returns an error for any parameter not in the allowed list
+func (p RuleParams) checkKnown(allowed ...string) error {
+	for key := range p {
+		known := false
+		for _, a := range allowed {
+			if key == a {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown parameter %q", key)
+		}
+	}
+	return nil
+}
+
+// AlertRuleFactory builds a rule from its configuration
+type AlertRuleFactory func(cfg AlertRuleConfig) (AlertRule, error)
+
+var alertRuleRegistry = map[string]AlertRuleFactory{}
+
+// RegisterAlertRule makes a rule type available to configuration
+func RegisterAlertRule(ruleType string, factory AlertRuleFactory) {
+	alertRuleRegistry[ruleType] = factory
+}
+
+// RegisteredAlertRuleTypes returns the available rule types in alphabetical order
+func RegisteredAlertRuleTypes() []string {
+	types := make([]string, 0, len(alertRuleRegistry))
+	for t := range alertRuleRegistry {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// NewAlertRule builds a rule from the registry
+func NewAlertRule(cfg AlertRuleConfig) (AlertRule, error) {
+	factory, ok := alertRuleRegistry[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("alert_rule.%s: unknown type %q (available: %v)", cfg.Name, cfg.Type, RegisteredAlertRuleTypes())
+	}
+	if cfg.Severity != "" {
+		if _, err := ParseSeverity(string(cfg.Severity)); err != nil {
+			return nil, fmt.Errorf("alert_rule.%s: %w", cfg.Name, err)
+		}
+	}
+	rule, err := factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("alert_rule.%s: %w", cfg.Name, err)
+	}
+	return rule, nil
+}
+
+// BuildAlertRules builds the configured rules. Without alert_rule.* entries the
+// rules come from the top-level thresholds: the duration rule, plus the daily
+// energy rule when daily_energy_alert_threshold_kwh is set.
+func BuildAlertRules(config *Config) ([]AlertRule, error) {
+	configs := config.AlertRules
+	if len(configs) == 0 {
+		configs = legacyAlertRules(config)
+	}
+
+	rules := make([]AlertRule, 0, len(configs))
+	for _, cfg := range configs {
+		rule, err := NewAlertRule(cfg)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// legacyAlertRules maps the top-level threshold settings onto rule configurations
+func legacyAlertRules(config *Config) []AlertRuleConfig {
+	configs := []AlertRuleConfig{{
+		Name: "low_production_duration",
+		Type: RuleTypeDuration,
+		Params: RuleParams{
+			"threshold_kw": strconv.FormatFloat(config.ProductionAlertThresholdKW, 'f', -1, 64),
+			"hours":        strconv.Itoa(config.DurationThresholdHours),
+		},
+	}}
+	if config.DailyEnergyAlertThresholdKWh > 0 {
+		days := config.DailyEnergyAlertDays
+		if days <= 0 {
+			days = DefaultDailyEnergyAlertDays
+		}
+		configs = append(configs, AlertRuleConfig{
+			Name: "low_daily_energy",
+			Type: RuleTypeDailyEnergy,
+			Params: RuleParams{
+				"threshold_kwh": strconv.FormatFloat(config.DailyEnergyAlertThresholdKWh, 'f', -1, 64),
+				"days":          strconv.Itoa(days),
+			},
+		})
+	}
+	return configs
+}
+
+// applyRuleResult records a fired rule and fills the analysis fields the
+// notifiers use for the duration and daily energy criteria
+func (s *SolarForecastService) applyRuleResult(result RuleResult, analysis *AlertAnalysis) {
+	if !result.Fired {
+		return
+	}
+	analysis.FiredRules = append(analysis.FiredRules, result)
+	analysis.CriteriaTriggered.AnyTriggered = true
+	if result.Severity.Rank() > analysis.Severity.Rank() {
+		analysis.Severity = result.Severity
+	}
+
+	switch result.Type {
+	case RuleTypeDuration:
+		// The first duration rule describes the low production period
+		if analysis.CriteriaTriggered.LowProductionDurationTriggered {
+			return
+		}
+		analysis.CriteriaTriggered.LowProductionDurationTriggered = true
+		analysis.ConsecutiveHourCount = len(result.Evidence)
+		analysis.FirstLowProductionHour = result.Evidence[0].Hour
+		analysis.LastLowProductionHour = result.Evidence[len(result.Evidence)-1].Hour
+		analysis.LowProductionHours = result.Evidence
+		analysis.HasRecovery = result.HasRecovery
+		if result.HasRecovery {
+			analysis.RecoveryHour = result.RecoveryHour
+			analysis.HoursUntilRecovery = int(result.RecoveryHour.Sub(analysis.FirstLowProductionHour).Hours())
+		}
+
+	case RuleTypeDailyEnergy:
+		analysis.CriteriaTriggered.LowDailyEnergyTriggered = true
+		analysis.LowEnergyDays = append(analysis.LowEnergyDays, result.Days...)
+	}
+}
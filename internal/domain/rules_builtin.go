@@ -0,0 +1,312 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterAlertRule(RuleTypeDuration, newDurationRule)
+	RegisterAlertRule(RuleTypeDailyEnergy, newDailyEnergyRule)
+	RegisterAlertRule(RuleTypePeak, newPeakRule)
+	RegisterAlertRule(RuleTypeCloudCover, newCloudCoverRule)
+}
+
+// ruleBase holds the fields shared by all built-in rules
+type ruleBase struct {
+	name     string
+	ruleType string
+	severity Severity
+}
+
+func (r ruleBase) Name() string { return r.name }
+func (r ruleBase) Type() string { return r.ruleType }
+
+// result starts a result for this rule
+func (r ruleBase) result() RuleResult {
+	return RuleResult{Name: r.name, Type: r.ruleType}
+}
+
+// severityFor returns the configured severity, or the tier for a shortfall
+// (0-1 of the threshold) lasting durationFactor times the rule's minimum
+func (r ruleBase) severityFor(input RuleInput, deficit, durationFactor float64) Severity {
+	if r.severity != "" {
+		return r.severity
+	}
+	return input.Tiers.Classify(deficit, durationFactor)
+}
+
+// durationRule fires when production stays below a kW level for N consecutive daylight hours
+type durationRule struct {
+	ruleBase
+	thresholdKW float64
+	hours       int
+}
+
+func newDurationRule(cfg AlertRuleConfig) (AlertRule, error) {
+	if err := cfg.Params.checkKnown("threshold_kw", "hours"); err != nil {
+		return nil, err
+	}
+	thresholdKW, err := cfg.Params.Float("threshold_kw", 2.0)
+	if err != nil {
+		return nil, err
+	}
+	hours, err := cfg.Params.Int("hours", 6)
+	if err != nil {
+		return nil, err
+	}
+	if thresholdKW <= 0 {
+		return nil, fmt.Errorf("threshold_kw must be positive, got %.2f", thresholdKW)
+	}
+	if hours < 1 {
+		return nil, fmt.Errorf("hours must be at least 1, got %d", hours)
+	}
+	return &durationRule{ruleBase{cfg.Name, cfg.Type, cfg.Severity}, thresholdKW, hours}, nil
+}
+
+// Evaluate finds the longest run of daylight hours below the threshold
+func (r *durationRule) Evaluate(input RuleInput) RuleResult {
+	result := r.result()
+	result.Threshold = r.thresholdKW
+
+	streak := longestStreak(input.DaylightHours, func(prod SolarProduction) bool {
+		return prod.OutputAt(input.Quantile) < r.thresholdKW
+	})
+	if len(streak) < r.hours {
+		return result
+	}
+
+	var deficit float64
+	for _, prod := range streak {
+		deficit += 1 - prod.OutputAt(input.Quantile)/r.thresholdKW
+	}
+	deficit /= float64(len(streak))
+
+	result.Fired = true
+	result.Evidence = streak
+	result.Severity = r.severityFor(input, deficit, float64(len(streak))/float64(r.hours))
+	first, last := streak[0].Hour, streak[len(streak)-1].Hour
+	result.Message = input.Locale.Sprintf("Low production: %d hours below %.1f kW\n%s-%s",
+		len(streak), r.thresholdKW, first.Format("15:04"), last.Format("15:04"))
+
+	// Recovery is the first daylight hour after the streak back at or above the threshold
+	for _, prod := range input.DaylightHours {
+		if prod.Hour.After(last) && prod.OutputAt(input.Quantile) >= r.thresholdKW && prod.GHI >= input.DaylightGHIThreshold {
+			result.HasRecovery = true
+			result.RecoveryHour = prod.Hour
+			break
+		}
+	}
+	return result
+}
+
+// longestStreak returns the longest run of consecutive hours matching the condition.
+// Ties keep the earliest run.
+func longestStreak(hours []SolarProduction, match func(SolarProduction) bool) []SolarProduction {
+	var longest, current []SolarProduction
+	for _, prod := range hours {
+		if !match(prod) {
+			current = nil
+			continue
+		}
+		current = append(current, prod)
+		if len(current) > len(longest) {
+			longest = append([]SolarProduction{}, current...)
+		}
+	}
+	return longest
+}
+
+// dailyEnergyRule fires when a complete day's energy is below a kWh level
+type dailyEnergyRule struct {
+	ruleBase
+	thresholdKWh float64
+	days         int
+}
+
+func newDailyEnergyRule(cfg AlertRuleConfig) (AlertRule, error) {
+	if err := cfg.Params.checkKnown("threshold_kwh", "days"); err != nil {
+		return nil, err
+	}
+	thresholdKWh, err := cfg.Params.Float("threshold_kwh", 0)
+	if err != nil {
+		return nil, err
+	}
+	days, err := cfg.Params.Int("days", DefaultDailyEnergyAlertDays)
+	if err != nil {
+		return nil, err
+	}
+	if thresholdKWh <= 0 {
+		return nil, fmt.Errorf("threshold_kwh must be positive, got %.2f", thresholdKWh)
+	}
+	if days < 1 {
+		return nil, fmt.Errorf("days must be at least 1, got %d", days)
+	}
+	return &dailyEnergyRule{ruleBase{cfg.Name, cfg.Type, cfg.Severity}, thresholdKWh, days}, nil
+}
+
+// Evaluate checks the first complete forecast days against the threshold
+func (r *dailyEnergyRule) Evaluate(input RuleInput) RuleResult {
+	result := r.result()
+	result.Threshold = r.thresholdKWh
+
+	var lines []string
+	var deficit float64
+	for _, day := range completeDays(input.DailyEnergy, r.days) {
+		if day.EnergyKWh >= r.thresholdKWh {
+			continue
+		}
+		deficit = math.Max(deficit, 1-day.EnergyKWh/r.thresholdKWh)
+		result.Days = append(result.Days, day)
+		result.Evidence = append(result.Evidence, hoursOnDay(input.DaylightHours, day.Date)...)
+		lines = append(lines, input.Locale.Sprintf("Low energy %s: %.1f kWh (below %.1f kWh)",
+			input.Locale.FormatTime(day.Date, "Mon Jan 2"), day.EnergyKWh, r.thresholdKWh))
+	}
+
+	result.Fired = len(result.Days) > 0
+	result.Message = strings.Join(lines, "\n")
+	if result.Fired {
+		result.Severity = r.severityFor(input, deficit, float64(len(result.Days)))
+	}
+	return result
+}
+
+// peakRule fires when a complete day's highest hourly output is below a kW level
+type peakRule struct {
+	ruleBase
+	thresholdKW float64
+	days        int
+}
+
+func newPeakRule(cfg AlertRuleConfig) (AlertRule, error) {
+	if err := cfg.Params.checkKnown("threshold_kw", "days"); err != nil {
+		return nil, err
+	}
+	thresholdKW, err := cfg.Params.Float("threshold_kw", 0)
+	if err != nil {
+		return nil, err
+	}
+	days, err := cfg.Params.Int("days", DefaultDailyEnergyAlertDays)
+	if err != nil {
+		return nil, err
+	}
+	if thresholdKW <= 0 {
+		return nil, fmt.Errorf("threshold_kw must be positive, got %.2f", thresholdKW)
+	}
+	if days < 1 {
+		return nil, fmt.Errorf("days must be at least 1, got %d", days)
+	}
+	return &peakRule{ruleBase{cfg.Name, cfg.Type, cfg.Severity}, thresholdKW, days}, nil
+}
+
+// Evaluate checks each day's peak hour against the threshold
+func (r *peakRule) Evaluate(input RuleInput) RuleResult {
+	result := r.result()
+	result.Threshold = r.thresholdKW
+
+	var lines []string
+	var deficit float64
+	for _, day := range completeDays(input.DailyEnergy, r.days) {
+		var peak SolarProduction
+		for _, prod := range hoursOnDay(input.AllHours, day.Date) {
+			if prod.OutputAt(input.Quantile) > peak.OutputAt(input.Quantile) {
+				peak = prod
+			}
+		}
+		peakKW := peak.OutputAt(input.Quantile)
+		if peakKW >= r.thresholdKW {
+			continue
+		}
+		deficit = math.Max(deficit, 1-peakKW/r.thresholdKW)
+		result.Days = append(result.Days, day)
+		if !peak.Hour.IsZero() {
+			result.Evidence = append(result.Evidence, peak)
+		}
+		lines = append(lines, input.Locale.Sprintf("Low peak %s: %.1f kW (below %.1f kW)",
+			input.Locale.FormatTime(day.Date, "Mon Jan 2"), peakKW, r.thresholdKW))
+	}
+
+	result.Fired = len(result.Days) > 0
+	result.Message = strings.Join(lines, "\n")
+	if result.Fired {
+		result.Severity = r.severityFor(input, deficit, float64(len(result.Days)))
+	}
+	return result
+}
+
+// cloudCoverRule fires when cloud cover stays at or above a percentage for N consecutive daylight hours
+type cloudCoverRule struct {
+	ruleBase
+	percent int
+	hours   int
+}
+
+func newCloudCoverRule(cfg AlertRuleConfig) (AlertRule, error) {
+	if err := cfg.Params.checkKnown("percent", "hours"); err != nil {
+		return nil, err
+	}
+	percent, err := cfg.Params.Int("percent", 80)
+	if err != nil {
+		return nil, err
+	}
+	hours, err := cfg.Params.Int("hours", 6)
+	if err != nil {
+		return nil, err
+	}
+	if percent < 1 || percent > 100 {
+		return nil, fmt.Errorf("percent must be between 1 and 100, got %d", percent)
+	}
+	if hours < 1 {
+		return nil, fmt.Errorf("hours must be at least 1, got %d", hours)
+	}
+	return &cloudCoverRule{ruleBase{cfg.Name, cfg.Type, cfg.Severity}, percent, hours}, nil
+}
+
+// Evaluate finds the longest run of overcast daylight hours
+func (r *cloudCoverRule) Evaluate(input RuleInput) RuleResult {
+	result := r.result()
+	result.Threshold = float64(r.percent)
+
+	streak := longestStreak(input.DaylightHours, func(prod SolarProduction) bool {
+		return prod.CloudCover >= r.percent
+	})
+	if len(streak) < r.hours {
+		return result
+	}
+
+	// Cloud cover says nothing about the production shortfall; only duration escalates
+	result.Fired = true
+	result.Evidence = streak
+	result.Severity = r.severityFor(input, 0, float64(len(streak))/float64(r.hours))
+	result.Message = input.Locale.Sprintf("Cloud cover %d%%+ for %d hours\n%s-%s",
+		r.percent, len(streak), streak[0].Hour.Format("15:04"), streak[len(streak)-1].Hour.Format("15:04"))
+	return result
+}
+
+// completeDays returns up to n days the forecast fully covers, in order
+func completeDays(days []DailyEnergy, n int) []DailyEnergy {
+	var complete []DailyEnergy
+	for _, day := range days {
+		if len(complete) >= n {
+			break
+		}
+		if day.Complete {
+			complete = append(complete, day)
+		}
+	}
+	return complete
+}
+
+// hoursOnDay returns the hours falling on the calendar day starting at date
+func hoursOnDay(hours []SolarProduction, date time.Time) []SolarProduction {
+	var matched []SolarProduction
+	for _, prod := range hours {
+		y, m, d := prod.Hour.In(date.Location()).Date()
+		if time.Date(y, m, d, 0, 0, 0, 0, date.Location()).Equal(date) {
+			matched = append(matched, prod)
+		}
+	}
+	return matched
+}
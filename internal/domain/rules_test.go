@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildAlertRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		wantTypes []string
+		wantErr   string
+	}{
+		{
+			name:      "legacy duration threshold",
+			config:    Config{ProductionAlertThresholdKW: 2, DurationThresholdHours: 6},
+			wantTypes: []string{RuleTypeDuration},
+		},
+		{
+			name:      "legacy duration and daily energy thresholds",
+			config:    Config{ProductionAlertThresholdKW: 2, DurationThresholdHours: 6, DailyEnergyAlertThresholdKWh: 10},
+			wantTypes: []string{RuleTypeDuration, RuleTypeDailyEnergy},
+		},
+		{
+			name: "configured rules replace legacy thresholds",
+			config: Config{
+				ProductionAlertThresholdKW: 2, DurationThresholdHours: 6,
+				AlertRules: []AlertRuleConfig{
+					{Name: "peak", Type: RuleTypePeak, Params: RuleParams{"threshold_kw": "3"}},
+					{Name: "clouds", Type: RuleTypeCloudCover, Severity: SeverityInfo},
+				},
+			},
+			wantTypes: []string{RuleTypePeak, RuleTypeCloudCover},
+		},
+		{
+			name:    "unknown type",
+			config:  Config{AlertRules: []AlertRuleConfig{{Name: "x", Type: "hail"}}},
+			wantErr: `unknown type "hail"`,
+		},
+		{
+			name:    "unknown parameter",
+			config:  Config{AlertRules: []AlertRuleConfig{{Name: "x", Type: RuleTypeDuration, Params: RuleParams{"treshold_kw": "2"}}}},
+			wantErr: `unknown parameter "treshold_kw"`,
+		},
+		{
+			name:    "invalid severity",
+			config:  Config{AlertRules: []AlertRuleConfig{{Name: "x", Type: RuleTypeDuration, Severity: "urgent"}}},
+			wantErr: "severity must be",
+		},
+		{
+			name:    "invalid parameter value",
+			config:  Config{AlertRules: []AlertRuleConfig{{Name: "x", Type: RuleTypeDailyEnergy, Params: RuleParams{"threshold_kwh": "lots"}}}},
+			wantErr: "threshold_kwh must be a number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := BuildAlertRules(&tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(rules) != len(tt.wantTypes) {
+				t.Fatalf("rules = %d, want %d", len(rules), len(tt.wantTypes))
+			}
+			for i, rule := range rules {
+				if rule.Type() != tt.wantTypes[i] {
+					t.Errorf("rule %d type = %q, want %q", i, rule.Type(), tt.wantTypes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPeakAndCloudCoverRules(t *testing.T) {
+	start := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	// Day 1 peaks at 4 kW, day 2 at 1.5 kW under 95% cloud from 08:00 to 15:00
+	var hours []SolarProduction
+	for h := 0; h < 48; h++ {
+		hour := start.Add(time.Duration(h) * time.Hour)
+		prod := SolarProduction{Hour: hour}
+		if local := hour.Hour(); local >= 8 && local <= 15 {
+			prod.GHI = 400
+			prod.EstimatedOutputKW = 4.0
+			prod.CloudCover = 20
+			if h >= 24 {
+				prod.EstimatedOutputKW = 1.5
+				prod.CloudCover = 95
+			}
+		}
+		hours = append(hours, prod)
+	}
+	var daylight []SolarProduction
+	for _, prod := range hours {
+		if prod.GHI >= 50 {
+			daylight = append(daylight, prod)
+		}
+	}
+
+	input := RuleInput{
+		DaylightHours:        daylight,
+		AllHours:             hours,
+		DailyEnergy:          DailyEnergyTotals(hours, AlertOnDeterministic),
+		DaylightGHIThreshold: 50,
+	}
+
+	peak, err := NewAlertRule(AlertRuleConfig{Name: "peak", Type: RuleTypePeak, Severity: SeverityCritical, Params: RuleParams{"threshold_kw": "2"}})
+	if err != nil {
+		t.Fatalf("peak rule: %v", err)
+	}
+	result := peak.Evaluate(input)
+	if !result.Fired || len(result.Days) != 1 || !result.Days[0].Date.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("peak result = %+v, want fired for day 2 only", result)
+	}
+	if result.Severity != SeverityCritical || result.Name != "peak" || result.Threshold != 2 {
+		t.Errorf("peak result name/severity/threshold = %q/%q/%.1f", result.Name, result.Severity, result.Threshold)
+	}
+
+	clouds, err := NewAlertRule(AlertRuleConfig{Name: "clouds", Type: RuleTypeCloudCover, Params: RuleParams{"percent": "90", "hours": "8"}})
+	if err != nil {
+		t.Fatalf("cloud rule: %v", err)
+	}
+	result = clouds.Evaluate(input)
+	if !result.Fired || len(result.Evidence) != 8 {
+		t.Errorf("cloud result fired=%v evidence=%d, want fired with 8 hours", result.Fired, len(result.Evidence))
+	}
+	// No fixed severity: 8 hours is exactly the minimum and cloud cover has no deficit
+	if result.Severity != SeverityInfo {
+		t.Errorf("computed severity = %q, want info", result.Severity)
+	}
+}
@@ -3,49 +3,67 @@ package domain
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 )
 
 // SolarForecastService orchestrates solar forecast checking and alerting
 type SolarForecastService struct {
-	config              *Config
-	weatherProvider     WeatherForecastProvider
-	emailNotifier       EmailNotifier
-	pushNotifier        PushNotifier
-	stateRepository     AlertStateRepository
-	logger              Logger
+	config            *Config
+	weatherProvider   WeatherForecastProvider
+	ensembleProvider  EnsembleForecastProvider
+	notifiers         *NotifierRegistry
+	forecastPublisher ForecastPublisher // Optional; nil when no publisher is configured
+	locale            *Locale           // Language of rule messages and alert update texts
+	stateRepository   AlertStateRepository
+	outbox            NotificationOutbox // Optional; nil when failed notifications are not retried
+	logger            Logger
 }
 
 // NewSolarForecastService creates a new service instance
 func NewSolarForecastService(
 	config *Config,
 	weatherProvider WeatherForecastProvider,
-	emailNotifier EmailNotifier,
-	pushNotifier PushNotifier,
+	ensembleProvider EnsembleForecastProvider,
+	notifiers *NotifierRegistry,
+	forecastPublisher ForecastPublisher,
 	stateRepository AlertStateRepository,
+	outbox NotificationOutbox,
 	logger Logger,
 ) *SolarForecastService {
+	// The loader validates the locale; an unknown one falls back to English
+	locale, err := LookupLocale(config.Locale)
+	if err != nil {
+		logger.Warn("Unsupported locale, using English", "error", err.Error())
+	}
 	return &SolarForecastService{
-		config:          config,
-		weatherProvider: weatherProvider,
-		emailNotifier:   emailNotifier,
-		pushNotifier:    pushNotifier,
-		stateRepository: stateRepository,
-		logger:          logger,
+		config:            config,
+		weatherProvider:   weatherProvider,
+		ensembleProvider:  ensembleProvider,
+		notifiers:         notifiers,
+		forecastPublisher: forecastPublisher,
+		stateRepository:   stateRepository,
+		outbox:            outbox,
+		logger:            logger,
+		locale:            locale,
 	}
 }
 
 // CheckAndAlert performs the complete check and alert workflow
 func (s *SolarForecastService) CheckAndAlert(ctx context.Context) error {
 	s.logger.Info("Starting solar forecast check")
+	now := time.Now()
 
-	// Reset alert state if new day
-	reset, err := s.stateRepository.ResetIfNewDay(ctx)
+	// Notifications earlier runs failed to deliver are retried first, so they go
+	// out even while the forecast is unavailable and reach each channel before
+	// anything newer this run sends
+	s.drainOutbox(ctx, now)
+
+	// Load the open alert episode, if any
+	episode, err := s.stateRepository.ActiveEpisode(ctx)
 	if err != nil {
-		s.logger.Error("Failed to check/reset daily state", "error", err.Error())
-	}
-	if reset {
-		s.logger.Info("Daily alert state reset")
+		s.logger.Error("Failed to load alert episode", "error", err.Error())
+		return fmt.Errorf("failed to load alert episode: %w", err)
 	}
 
 	// Fetch forecast data
@@ -57,167 +75,344 @@ func (s *SolarForecastService) CheckAndAlert(ctx context.Context) error {
 
 	s.logger.Info("Forecast fetched successfully", "hours", len(forecast.Hours))
 
+	// Fetch ensemble members for the uncertainty band (optional)
+	ensemble := s.fetchEnsemble(ctx)
+
 	// Analyze forecast for alert conditions
-	analysis := s.analyzeForecast(forecast)
+	analysis := s.analyzeForecast(forecast, ensemble)
 
 	// Log analysis results
 	s.logger.Info("Forecast analysis complete",
 		"low_production_duration_triggered", analysis.CriteriaTriggered.LowProductionDurationTriggered,
+		"low_daily_energy_triggered", analysis.CriteriaTriggered.LowDailyEnergyTriggered,
+		"low_energy_days", len(analysis.LowEnergyDays),
+		"alert_quantile", analysis.AlertQuantile,
+		"ensemble_members", analysis.EnsembleMembers,
+		"severity", analysis.Severity,
 		"consecutive_hours", analysis.ConsecutiveHourCount,
 		"first_low_hour", analysis.FirstLowProductionHour.Format("15:04"),
 		"last_low_hour", analysis.LastLowProductionHour.Format("15:04"),
 	)
 
+	alertErr := s.handleAlert(ctx, episode, analysis, now)
+
+	// Sensors are published whether or not alerting succeeded
+	s.publishForecast(ctx, analysis, now)
+	return alertErr
+}
+
+// handleAlert opens, updates or resolves the alert episode for the analysis
+func (s *SolarForecastService) handleAlert(ctx context.Context, episode *AlertEpisode, analysis *AlertAnalysis, now time.Time) error {
 	// Check if we should send alert
 	if !analysis.CriteriaTriggered.AnyTriggered {
 		s.logger.Info("No alert criteria triggered")
-		
-		// Check if we should send recovery email (conditions have improved)
-		shouldSendRecovery, err := s.stateRepository.ShouldSendRecoveryEmail(ctx)
-		if err != nil {
-			s.logger.Error("Failed to check recovery email status", "error", err.Error())
-			return err
-		}
-
-		if shouldSendRecovery {
-			s.logger.Info("Recovery conditions met - preparing to send recovery email",
-				"reason", "alert_previously_sent_and_conditions_improved")
 
-			if err := s.emailNotifier.SendRecoveryEmail(ctx); err != nil {
-				s.logger.Error("Failed to send recovery email", "error", err.Error())
-				return fmt.Errorf("failed to send recovery email: %w", err)
-			}
+		if episode == nil {
+			s.logger.Debug("Recovery not needed - no open alert episode")
+			return nil
+		}
+		return s.resolveEpisode(ctx, episode, analysis, now)
+	}
 
-			s.logger.Info("Recovery email sent successfully")
+	// A new low period after the episode's expected end starts a new episode; the
+	// old one recovered in between, so its channels get the recovery first
+	if episode != nil && episode.Superseded(analysis, now) {
+		s.logger.Info("Alert episode ended before a new low period, resolving it",
+			"episode", episode.ID,
+			"expected_end", episode.ExpectedEnd.Format("2006-01-02 15:04"))
+		if err := s.resolveEpisode(ctx, episode, analysis, now); err != nil {
+			return err
+		}
+		episode = nil
+	}
 
-			if err := s.stateRepository.MarkRecoveryEmailSent(ctx); err != nil {
-				s.logger.Error("Failed to mark recovery email as sent", "error", err.Error())
-				return fmt.Errorf("failed to mark recovery email sent: %w", err)
-			}
+	if episode == nil {
+		return s.openEpisode(ctx, analysis, now)
+	}
 
-			s.logger.Info("Recovery email marked as sent in state file")
-		} else {
-			s.logger.Debug("Recovery email not needed")
+	// The episode is already alerted; only a material change is worth another notification
+	episode.Observe(analysis)
+	var update *AlertUpdate
+	if episode.Latest.IsZero() {
+		// Without a baseline every forecast would look like a change; the current one becomes it
+		s.logger.Info("Alert episode has no forecast baseline, recording the current forecast", "episode", episode.ID)
+		episode.Latest = NewAlertFingerprint(analysis)
+		if episode.Initial.IsZero() {
+			episode.Initial = episode.Latest
+		}
+	} else {
+		update = s.alertUpdate(episode, analysis, now)
+	}
+	if update == nil {
+		s.logger.Info("Alert already sent for this episode, skipping", "episode", episode.ID)
+		if err := s.stateRepository.UpdateEpisode(ctx, *episode); err != nil {
+			s.logger.Error("Failed to update alert episode", "error", err.Error())
+			return fmt.Errorf("failed to update alert episode: %w", err)
 		}
-		
 		return nil
 	}
 
-	// Check if alert was already sent today
-	shouldSend, err := s.shouldSendAlert(ctx)
+	s.logger.Info("Alert changed since it was sent, sending update",
+		"episode", episode.ID,
+		"changes", strings.Join(update.Changes, "; "))
+	analysis.Update = update
+
+	channels, err := s.sendAlert(ctx, episode, NotificationUpdate, analysis, now)
 	if err != nil {
-		s.logger.Error("Failed to check if alert should be sent", "error", err.Error())
 		return err
 	}
 
-	if !shouldSend {
-		s.logger.Info("Alert already sent today, skipping")
+	episode.Latest = NewAlertFingerprint(analysis)
+	episode.RecordNotification(NotificationUpdate, channels, now)
+	if err := s.stateRepository.UpdateEpisode(ctx, *episode); err != nil {
+		s.logger.Error("Failed to mark alert update as sent", "error", err.Error())
+		return fmt.Errorf("failed to mark alert update sent: %w", err)
+	}
+
+	s.logger.Info("Alert update sent successfully", "episode", episode.ID, "severity", analysis.Severity)
+	return nil
+}
+
+// openEpisode sends the first alert for a low production period and stores the new episode
+func (s *SolarForecastService) openEpisode(ctx context.Context, analysis *AlertAnalysis, now time.Time) error {
+	if !s.withinAlertHours(now) {
+		s.logger.Info("Outside alert hours, alert deferred to the next run")
 		return nil
 	}
 
-	// Send alert email
-	if err := s.emailNotifier.SendAlert(ctx, analysis); err != nil {
-		s.logger.Error("Failed to send alert email", "error", err.Error())
-		return fmt.Errorf("failed to send alert: %w", err)
+	episode := NewAlertEpisode(analysis, now)
+	channels, err := s.sendAlert(ctx, &episode, NotificationAlert, analysis, now)
+	if err != nil {
+		return err
 	}
 
-	// Send push notification with chart if configured
-	if s.pushNotifier != nil {
-		title := "⚠️ Solar Production Alert"
-		message := fmt.Sprintf("Low production: %d hours below %.1f kW\n%s-%s",
-			analysis.ConsecutiveHourCount,
-			s.config.ProductionAlertThresholdKW,
-			analysis.FirstLowProductionHour.Format("15:04"),
-			analysis.LastLowProductionHour.Format("15:04"))
-
-		if analysis.HasRecovery {
-			message += fmt.Sprintf("\n\nRecovery expected at %s (%d hours)",
-				analysis.RecoveryHour.Format("15:04"),
-				analysis.HoursUntilRecovery)
+	episode.RecordNotification(NotificationAlert, channels, now)
+	if err := s.stateRepository.OpenEpisode(ctx, episode); err != nil {
+		s.logger.Error("Failed to mark alert as sent", "error", err.Error())
+		return fmt.Errorf("failed to mark alert sent: %w", err)
+	}
+
+	s.logger.Info("Alert sent successfully",
+		"episode", episode.ID,
+		"severity", analysis.Severity,
+		"expected_end", episode.ExpectedEnd.Format("2006-01-02 15:04"))
+	return nil
+}
+
+// resolveEpisode sends the episode's recovery to the channels its alerts reached
+// and closes it. Channels that fail are retried through the outbox; without an
+// outbox, if every channel fails the episode stays open, so the recovery is
+// retried on the next run.
+func (s *SolarForecastService) resolveEpisode(ctx context.Context, episode *AlertEpisode, analysis *AlertAnalysis, now time.Time) error {
+	summary := NewRecoverySummary(*episode, analysis, now)
+	summary.NotificationID = episode.NextNotificationID(NotificationRecovery)
+	s.logger.Info("Recovery conditions met - preparing to send recovery notifications",
+		"episode", episode.ID,
+		"started", episode.StartedAt.Format("2006-01-02 15:04"),
+		"low_duration", summary.LowDuration().String(),
+		"forecast_low_duration", summary.ForecastLowDuration().String())
+
+	// Episodes stored before named channels recorded the push group as one channel
+	var targets []string
+	for _, name := range s.notifiers.Names() {
+		if episode.DeliveredTo(name) || (name != ChannelEmail && episode.DeliveredTo(ChannelPush)) {
+			targets = append(targets, name)
 		}
+	}
 
-		// Generate chart image if adapter supports it
-		var chartImage []byte
-		if chartGenerator, ok := s.pushNotifier.(interface {
-			GenerateChartImage([]SolarProduction) ([]byte, error)
-		}); ok {
-			var err error
-			chartImage, err = chartGenerator.GenerateChartImage(analysis.AllProductionHours)
-			if err != nil {
-				s.logger.Warn("Failed to generate chart image for push notification", "error", err.Error())
-				// Continue without image
-			} else {
-				s.logger.Info("Generated chart image for push notification", "size_bytes", len(chartImage))
-			}
+	message := OutboxMessage{Kind: NotificationRecovery, EpisodeID: episode.ID, Recovery: summary}
+	channels, err := s.deliver(ctx, targets, message, now)
+	if err != nil {
+		return fmt.Errorf("failed to send recovery: %w", err)
+	}
+
+	episode.RecordNotification(NotificationRecovery, channels, now)
+	if err := s.stateRepository.UpdateEpisode(ctx, *episode); err != nil {
+		s.logger.Error("Failed to record recovery notification", "error", err.Error())
+		return fmt.Errorf("failed to record recovery notification: %w", err)
+	}
+	if err := s.stateRepository.CloseEpisode(ctx, episode.ID, now); err != nil {
+		s.logger.Error("Failed to close alert episode", "error", err.Error())
+		return fmt.Errorf("failed to close alert episode: %w", err)
+	}
+
+	s.logger.Info("Alert episode resolved", "episode", episode.ID, "duration", now.Sub(episode.StartedAt).Round(time.Minute).String())
+	return nil
+}
+
+// sendAlert routes an alert or update to the channels configured for its severity
+// and returns the channels it was delivered to. It fails only when every routed
+// channel failed and none could be queued for retry.
+func (s *SolarForecastService) sendAlert(ctx context.Context, episode *AlertEpisode, kind NotificationKind, analysis *AlertAnalysis, now time.Time) ([]string, error) {
+	routes := s.config.SeverityRoutes
+	if routes == nil {
+		routes = DefaultSeverityRoutes()
+	}
+
+	var targets []string
+	for _, name := range s.notifiers.Names() {
+		if routes.RoutesTo(analysis.Severity, name) {
+			targets = append(targets, name)
 		}
+	}
+	s.logger.Info("Routing alert", "severity", analysis.Severity, "channels", targets)
+
+	analysis.NotificationID = episode.NextNotificationID(kind)
+	analysis.NotifiedAt = now
+	message := OutboxMessage{Kind: kind, EpisodeID: episode.ID, Analysis: analysis}
+	channels, err := s.deliver(ctx, targets, message, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send alert: %w", err)
+	}
+	return channels, nil
+}
+
+// deliver dispatches a notification to the target channels concurrently, logs
+// each channel's outcome, queues the failed ones for retry and returns the
+// channels that delivered. A failing channel does not affect the others; an
+// error is returned only when there were targets and none of them delivered or
+// was queued.
+func (s *SolarForecastService) deliver(ctx context.Context, targets []string, message OutboxMessage, now time.Time) ([]string, error) {
+	kind := message.Kind
+	if len(targets) == 0 {
+		s.logger.Warn("No notification channel configured for this notification", "kind", kind)
+		return nil, nil
+	}
 
-		if err := s.pushNotifier.SendNotification(ctx, title, message, chartImage); err != nil {
-			s.logger.Warn("Failed to send push notification", "error", err.Error())
-			// Don't fail the whole operation if push fails
+	results := s.notifiers.Dispatch(ctx, targets, func(ctx context.Context, name string, notifier Notifier) error {
+		return message.Send(ctx, notifier)
+	})
+	for _, result := range results {
+		if result.Err != nil {
+			s.logger.Error("Notification channel failed",
+				"kind", kind,
+				"channel", result.Channel,
+				"duration", result.Duration.Round(time.Millisecond).String(),
+				"error", result.Err.Error())
+			continue
 		}
+		s.logger.Info("Notification delivered",
+			"kind", kind,
+			"channel", result.Channel,
+			"duration", result.Duration.Round(time.Millisecond).String())
 	}
 
-	// Mark alert as sent
-	if err := s.stateRepository.MarkAlertSent(ctx); err != nil {
-		s.logger.Error("Failed to mark alert as sent", "error", err.Error())
-		return fmt.Errorf("failed to mark alert sent: %w", err)
+	delivered := DeliveredChannels(results)
+	queued := s.queueFailed(ctx, results, message, now)
+	s.logger.Info("Notification dispatch finished",
+		"kind", kind,
+		"delivered", len(delivered),
+		"failed", len(results)-len(delivered),
+		"queued", queued)
+	if len(delivered) == 0 && queued == 0 {
+		return nil, DeliveryErrors(results)
 	}
+	return delivered, nil
+}
 
-	s.logger.Info("Alert email sent successfully")
-	return nil
+// fetchEnsemble retrieves the ensemble forecast when a provider is configured.
+// Failures are logged and the analysis falls back to the deterministic forecast.
+func (s *SolarForecastService) fetchEnsemble(ctx context.Context) *EnsembleForecast {
+	if s.ensembleProvider == nil {
+		return nil
+	}
+
+	ensemble, err := s.ensembleProvider.GetEnsembleForecast(ctx, s.config.Latitude, s.config.Longitude)
+	if err != nil {
+		s.logger.Warn("Failed to fetch ensemble forecast, using deterministic forecast only", "error", err.Error())
+		return nil
+	}
+
+	s.logger.Info("Ensemble forecast fetched successfully", "members", len(ensemble.Members))
+	return ensemble
+}
+
+// publishForecast publishes the run's forecast when a publisher is configured.
+// Failures are logged and do not fail the run.
+func (s *SolarForecastService) publishForecast(ctx context.Context, analysis *AlertAnalysis, now time.Time) {
+	if s.forecastPublisher == nil {
+		return
+	}
+
+	// The episode reflects this run's alerting, so it is loaded again
+	episode, err := s.stateRepository.ActiveEpisode(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load alert episode for the forecast snapshot", "error", err.Error())
+		episode = nil
+	}
+
+	snapshot := NewForecastSnapshot(analysis, episode, now)
+	if err := s.forecastPublisher.PublishForecast(ctx, snapshot); err != nil {
+		s.logger.Warn("Failed to publish forecast", "error", err.Error())
+		return
+	}
+	s.logger.Info("Forecast published",
+		"current_kw", snapshot.CurrentKW,
+		"today_kwh", snapshot.TodayKWh,
+		"tomorrow_kwh", snapshot.TomorrowKWh,
+		"alert_active", snapshot.AlertActive)
 }
 
-// analyzeForecast analyzes forecast data against alert criteria
-func (s *SolarForecastService) analyzeForecast(forecast *ForecastData) *AlertAnalysis {
+// analyzeForecast evaluates the configured alert rules against the forecast.
+// The ensemble is optional; when present each hour carries its production quantiles.
+func (s *SolarForecastService) analyzeForecast(forecast *ForecastData, ensemble *EnsembleForecast) *AlertAnalysis {
 	analysis := &AlertAnalysis{
 		LowProductionHours: []SolarProduction{},
+		AlertQuantile:      AlertOnDeterministic,
 	}
 
-	// Calculate production for ALL hours (for chart display - shows night hours too)
-	allProductionData := make([]SolarProduction, len(forecast.Hours))
-	for i, hour := range forecast.Hours {
-		allProductionData[i] = s.calculateSolarProduction(hour)
+	quantiles := s.productionQuantiles(ensemble)
+	if len(quantiles) > 0 {
+		for _, q := range quantiles {
+			if q.Members > analysis.EnsembleMembers {
+				analysis.EnsembleMembers = q.Members
+			}
+		}
+		if s.config.AlertQuantile != "" {
+			analysis.AlertQuantile = s.config.AlertQuantile
+		}
+	} else if s.config.AlertQuantile != "" && s.config.AlertQuantile != AlertOnDeterministic {
+		s.logger.Warn("No ensemble data available, alerting on deterministic forecast",
+			"alert_quantile", s.config.AlertQuantile)
 	}
-	analysis.AllProductionHours = allProductionData
+
+	// Calculate production for ALL hours (for chart display - shows night hours too)
+	analysis.AllProductionHours = s.calculateProductionHours(forecast.Hours, quantiles)
+	analysis.DailyEnergy = DailyEnergyTotals(analysis.AllProductionHours, analysis.AlertQuantile)
 
 	// Filter hours to daylight analysis window
 	windowHours := s.filterAnalysisWindow(forecast.Hours)
 	if len(windowHours) == 0 {
 		s.logger.Warn("No hours in analysis window")
-		analysis.RecommendedAction = "No data in analysis window. Check again during daytime hours."
-		return analysis
 	}
 
 	// Track total daylight hours in the analysis period
 	analysis.TotalDaylightHours = len(windowHours)
 
-	// Calculate solar production for daylight hours only (for alert analysis)
-	productionData := make([]SolarProduction, len(windowHours))
-	for i, hour := range windowHours {
-		productionData[i] = s.calculateSolarProduction(hour)
+	rules, err := BuildAlertRules(s.config)
+	if err != nil {
+		s.logger.Error("Invalid alert rule configuration", "error", err.Error())
 	}
 
-	// Evaluate low production duration criterion (using daylight hours only)
-	s.evaluateLowProductionDuration(productionData, analysis)
-
-	// Determine if any criterion triggered
-	analysis.CriteriaTriggered.AnyTriggered = analysis.CriteriaTriggered.LowProductionDurationTriggered
-
-	if !analysis.CriteriaTriggered.AnyTriggered {
-		analysis.RecommendedAction = "Solar production forecast looks normal. No action required."
-		return analysis
-	}
-
-	// If alert triggered but no recovery found in initial window,
-	// search for recovery in the full 7-day forecast
-	if !analysis.HasRecovery && analysis.CriteriaTriggered.LowProductionDurationTriggered {
-		// Get ALL daylight hours from the full 7-day forecast for recovery search
-		allDaylightHours := s.filterAnalysisWindow(forecast.Hours)
-		allDaylightProduction := make([]SolarProduction, len(allDaylightHours))
-		for i, hour := range allDaylightHours {
-			allDaylightProduction[i] = s.calculateSolarProduction(hour)
-		}
-		s.findRecoveryInExtendedForecast(allDaylightProduction, analysis)
+	input := RuleInput{
+		DaylightHours:        s.calculateProductionHours(windowHours, quantiles),
+		AllHours:             analysis.AllProductionHours,
+		DailyEnergy:          analysis.DailyEnergy,
+		Quantile:             analysis.AlertQuantile,
+		DaylightGHIThreshold: s.config.DaylightGHIThreshold,
+		Tiers:                s.config.SeverityTiers,
+		Locale:               s.locale,
+	}
+	for _, rule := range rules {
+		result := rule.Evaluate(input)
+		s.logger.Debug("Alert rule evaluated",
+			"rule", result.Name,
+			"type", result.Type,
+			"fired", result.Fired,
+			"severity", result.Severity,
+			"evidence_hours", len(result.Evidence),
+		)
+		s.applyRuleResult(result, analysis)
 	}
 
 	// Generate recommendation
@@ -226,6 +421,19 @@ func (s *SolarForecastService) analyzeForecast(forecast *ForecastData) *AlertAna
 	return analysis
 }
 
+// calculateProductionHours estimates production for each hour and attaches the
+// ensemble quantiles for that hour when available
+func (s *SolarForecastService) calculateProductionHours(hours []ForecastHour, quantiles map[int64]ProductionQuantiles) []SolarProduction {
+	production := make([]SolarProduction, len(hours))
+	for i, hour := range hours {
+		production[i] = s.calculateSolarProduction(hour)
+		if q, ok := quantiles[hour.Hour.Unix()]; ok {
+			production[i].Quantiles = &q
+		}
+	}
+	return production
+}
+
 // filterAnalysisWindow filters forecast hours to daylight hours only
 // Uses GHI (Global Horizontal Irradiance) to determine daylight - more accurate than fixed times
 // as it adapts to seasonal changes and actual solar conditions
@@ -247,109 +455,6 @@ func (s *SolarForecastService) filterAnalysisWindow(hours []ForecastHour) []Fore
 	return filtered
 }
 
-// evaluateLowProductionDuration checks if production drops below threshold for 6+ consecutive hours
-func (s *SolarForecastService) evaluateLowProductionDuration(production []SolarProduction, analysis *AlertAnalysis) {
-	var maxConsecutiveCount int
-	var maxConsecutiveStart, maxConsecutiveEnd time.Time
-	var maxConsecutiveHours []SolarProduction
-	var recoveryHour time.Time
-	var maxStreakRecovered bool
-
-	currentConsecutiveCount := 0
-	var currentConsecutiveStart time.Time
-	var currentConsecutiveHours []SolarProduction
-
-	for _, prod := range production {
-		s.logger.Debug("Production hour",
-			"hour", prod.Hour.Format("15:04"),
-			"production_kw", fmt.Sprintf("%.2f", prod.EstimatedOutputKW),
-			"below_threshold", prod.EstimatedOutputKW < s.config.ProductionAlertThresholdKW,
-		)
-
-		if prod.EstimatedOutputKW < s.config.ProductionAlertThresholdKW {
-			// Below threshold
-			if currentConsecutiveCount == 0 {
-				currentConsecutiveStart = prod.Hour
-			}
-			currentConsecutiveCount++
-			currentConsecutiveHours = append(currentConsecutiveHours, prod)
-
-			// Check if this is the max so far
-			if currentConsecutiveCount > maxConsecutiveCount {
-				maxConsecutiveCount = currentConsecutiveCount
-				maxConsecutiveStart = currentConsecutiveStart
-				maxConsecutiveEnd = prod.Hour
-				maxConsecutiveHours = append([]SolarProduction{}, currentConsecutiveHours...)
-				maxStreakRecovered = false // Reset recovery flag for new max
-			}
-		} else {
-			// Above threshold - RECOVERY DETECTED
-
-			// If we just ended the max consecutive streak, capture recovery
-			// Only count as recovery if GHI threshold is met (still daylight, not sunset)
-			if currentConsecutiveCount > 0 && currentConsecutiveCount == maxConsecutiveCount && prod.GHI >= s.config.DaylightGHIThreshold {
-				recoveryHour = prod.Hour
-				maxStreakRecovered = true
-				s.logger.Debug("Recovery detected",
-					"recovery_hour", recoveryHour.Format("15:04"),
-					"after_streak", maxConsecutiveCount,
-					"ghi", prod.GHI,
-				)
-			}
-
-			// Reset consecutive count
-			currentConsecutiveCount = 0
-			currentConsecutiveHours = nil
-		}
-	}
-
-	s.logger.Debug("Low production duration evaluation",
-		"threshold_kw", s.config.ProductionAlertThresholdKW,
-		"duration_threshold_hours", s.config.DurationThresholdHours,
-		"max_consecutive_hours", maxConsecutiveCount,
-		"triggered", maxConsecutiveCount >= s.config.DurationThresholdHours,
-		"has_recovery", maxStreakRecovered,
-	)
-
-	if maxConsecutiveCount >= s.config.DurationThresholdHours {
-		analysis.CriteriaTriggered.LowProductionDurationTriggered = true
-		analysis.ConsecutiveHourCount = maxConsecutiveCount
-		analysis.FirstLowProductionHour = maxConsecutiveStart
-		analysis.LastLowProductionHour = maxConsecutiveEnd
-		analysis.LowProductionHours = maxConsecutiveHours
-
-		// Set recovery fields
-		analysis.HasRecovery = maxStreakRecovered
-		if maxStreakRecovered {
-			analysis.RecoveryHour = recoveryHour
-			analysis.HoursUntilRecovery = int(recoveryHour.Sub(maxConsecutiveStart).Hours())
-		}
-	}
-}
-
-// findRecoveryInExtendedForecast searches for recovery in the full 7-day forecast
-func (s *SolarForecastService) findRecoveryInExtendedForecast(allDaylightProduction []SolarProduction, analysis *AlertAnalysis) {
-	// Look for the first daylight hour after LastLowProductionHour where production is above threshold
-	for _, prod := range allDaylightProduction {
-		// Only consider hours after the end of the low production period
-		if prod.Hour.After(analysis.LastLowProductionHour) {
-			// Check if production is above threshold and it's daylight
-			if prod.EstimatedOutputKW >= s.config.ProductionAlertThresholdKW && prod.GHI >= s.config.DaylightGHIThreshold {
-				analysis.HasRecovery = true
-				analysis.RecoveryHour = prod.Hour
-				analysis.HoursUntilRecovery = int(prod.Hour.Sub(analysis.FirstLowProductionHour).Hours())
-				s.logger.Debug("Recovery found in extended forecast",
-					"recovery_hour", analysis.RecoveryHour.Format("2006-01-02 15:04"),
-					"hours_until_recovery", analysis.HoursUntilRecovery,
-				)
-				return
-			}
-		}
-	}
-
-	s.logger.Debug("No recovery found in 7-day forecast")
-}
-
 // calculateSolarProduction estimates solar output for a given hour
 func (s *SolarForecastService) calculateSolarProduction(hour ForecastHour) SolarProduction {
 	prod := SolarProduction{
@@ -358,37 +463,82 @@ func (s *SolarForecastService) calculateSolarProduction(hour ForecastHour) Solar
 		Temperature:              hour.Temperature,
 		GHI:                      hour.GlobalHorizontalIrradiance,
 		PrecipitationProbability: hour.PrecipitationProbability,
+		WindSpeed:                hour.WindSpeed,
 	}
 
-	// Formula: P_out = P_rated × (GHI/1000) × η_inverter × temp_adjustment
+	// Formula (per array): P_dc = P_rated × (POA/1000) × (1 - losses) × temp_adjustment
+	// Inverter (all arrays): P_out = min(ΣP_dc × η_inverter(load), AC limit)
 	//
-	// Note: RatedCapacityKW (8.9 kW for 16×560W panels) is the manufacturer's rated
+	// Note: the rated capacity (8.9 kW for 16×560W panels) is the manufacturer's rated
 	// output at STC (Standard Test Conditions: 1000 W/m² irradiance, 25°C, AM1.5 spectrum).
 	// This rating ALREADY includes the panel's conversion efficiency (~20% silicon),
 	// so we only need to adjust for:
-	//   1. Actual irradiance vs reference (GHI/1000)
-	//   2. Inverter losses (DC to AC conversion)
-	//   3. Temperature effects
+	//   1. Actual irradiance on the panel plane vs reference (POA/1000)
+	//   2. Array losses (soiling, shading, mismatch, wiring)
+	//   3. Inverter losses (DC to AC conversion, part-load curve) and AC clipping
+	//   4. Temperature effects (on the cell, which runs hotter than the air in sunshine)
 	//
 	// GHI (Global Horizontal Irradiance) from Open-Meteo already accounts for
-	// cloud cover, atmospheric conditions, and solar angle.
+	// cloud cover and atmospheric conditions, but it is measured on a flat surface.
+	// Tilted panels see more (or less) depending on sun position, so GHI is first
+	// transposed onto each array's plane (POA = plane-of-array irradiance).
 	//
-	// Reference GHI is 1000 W/m² (STC)
+	// Reference irradiance is 1000 W/m² (STC)
+	position := s.solarPositionForHour(hour.Hour)
+	prod.SunElevationDeg = position.ElevationDeg
+	components := s.irradianceComponents(hour, position)
+
+	arrays := s.config.PVArrays()
+	prod.Arrays = make([]ArrayProduction, len(arrays))
+	var totalCapacity, weightedPOA, weightedCellTemp float64
+
+	for i, array := range arrays {
+		poa := s.planeOfArrayIrradiance(hour, components, position, array)
+		cellTemp := CellTemperature(s.config.CellTemperature, hour.Temperature, poa, hour.WindSpeed)
+
+		// Temperature adjustment (efficiency decreases with cell temperature above STC reference of 25°C)
+		// TempCoefficient is typically -0.4 to -0.5 (%/°C)
+		// At 45°C (20° above ref): 1.0 + (-0.4/100 * 20) = 0.92 (8% loss) ✓
+		// At 5°C (20° below ref): 1.0 + (-0.4/100 * -20) = 1.08 (8% gain) ✓
+		tempAdjustment := 1.0 + (array.TempCoefficient / 100.0 * (cellTemp - STCTemperature))
+
+		// Calculate DC output (panel_efficiency removed - already included in rated capacity)
+		dcKW := array.CapacityKW *
+			(poa / STCIrradiance) *
+			(1 - array.LossPercent/100.0) *
+			tempAdjustment
+		if dcKW < 0 {
+			dcKW = 0
+		}
 
-	// Normalize GHI to reference (STC = 1000 W/m²)
-	ghiFactor := hour.GlobalHorizontalIrradiance / STCIrradiance
+		prod.Arrays[i] = ArrayProduction{
+			Name:            array.Name,
+			OutputKW:        dcKW, // Converted to the array's AC share below
+			POA:             poa,
+			CellTemperature: cellTemp,
+		}
+		prod.DCOutputKW += dcKW
+		totalCapacity += array.CapacityKW
+		weightedPOA += poa * array.CapacityKW
+		weightedCellTemp += cellTemp * array.CapacityKW
+	}
 
-	// Temperature adjustment (efficiency decreases with temperature above STC reference of 25°C)
-	// TempCoefficient is typically -0.4 to -0.5 (%/°C)
-	// At 45°C (20° above ref): 1.0 + (-0.4/100 * 20) = 0.92 (8% loss) ✓
-	// At 5°C (20° below ref): 1.0 + (-0.4/100 * -20) = 1.08 (8% gain) ✓
-	tempAdjustment := 1.0 + (s.config.TempCoefficient / 100.0 * (hour.Temperature - STCTemperature))
+	if totalCapacity > 0 {
+		prod.POA = weightedPOA / totalCapacity
+		prod.CellTemperature = weightedCellTemp / totalCapacity
+	}
 
-	// Calculate output (panel_efficiency removed - already included in rated capacity)
-	prod.EstimatedOutputKW = s.config.RatedCapacityKW *
-		ghiFactor *
-		s.config.InverterEfficiency *
-		tempAdjustment
+	// All arrays share one inverter: convert the combined DC power, then attribute
+	// the AC output (after any clipping) back to each array in proportion to its DC
+	prod.EstimatedOutputKW, prod.ClippedKW = s.inverterOutput(prod.DCOutputKW, totalCapacity)
+	prod.Clipped = prod.ClippedKW > 0
+	for i := range prod.Arrays {
+		if prod.DCOutputKW > 0 {
+			prod.Arrays[i].OutputKW *= prod.EstimatedOutputKW / prod.DCOutputKW
+		} else {
+			prod.Arrays[i].OutputKW = 0
+		}
+	}
 
 	// Ensure non-negative
 	if prod.EstimatedOutputKW < 0 {
@@ -396,7 +546,7 @@ func (s *SolarForecastService) calculateSolarProduction(hour ForecastHour) Solar
 	}
 
 	// Calculate percentage of rated capacity
-	prod.OutputPercentage = (prod.EstimatedOutputKW / s.config.RatedCapacityKW) * 100.0
+	prod.OutputPercentage = (prod.EstimatedOutputKW / totalCapacity) * 100.0
 
 	// Clamp to 0-100%
 	if prod.OutputPercentage < 0 {
@@ -409,69 +559,126 @@ func (s *SolarForecastService) calculateSolarProduction(hour ForecastHour) Solar
 	return prod
 }
 
-// generateRecommendation generates actionable recommendation text
-// DEPRECATED: This field is no longer displayed in alert emails as of the template update.
-// Kept for backward compatibility and potential logging use.
+// solarPositionForHour returns the sun position representative of a forecast hour.
+// Open-Meteo radiation values are averages over the preceding hour, so the sun is
+// sampled at the middle of that interval rather than at the timestamp itself.
+func (s *SolarForecastService) solarPositionForHour(hour time.Time) SolarPosition {
+	return CalculateSolarPosition(hour.Add(-30*time.Minute), s.config.Latitude, s.config.Longitude)
+}
+
+// irradianceComponents returns the beam/diffuse split for an hour.
+// Provider-supplied DNI and DHI are preferred; otherwise they are estimated from GHI.
+func (s *SolarForecastService) irradianceComponents(hour ForecastHour, position SolarPosition) IrradianceComponents {
+	if hour.HasIrradianceComponents {
+		return IrradianceComponents{
+			GHI: hour.GlobalHorizontalIrradiance,
+			DNI: hour.DirectNormalIrradiance,
+			DHI: hour.DiffuseHorizontalIrradiance,
+		}
+	}
+	return DecomposeGHI(hour.GlobalHorizontalIrradiance, position, hour.Hour)
+}
+
+// planeOfArrayIrradiance returns the irradiance on an array's plane.
+// Uses the provider's tilted irradiance when it was computed for the same plane,
+// otherwise transposes the horizontal components. Flat panels (tilt 0) receive
+// exactly GHI, so the legacy behaviour is preserved.
+func (s *SolarForecastService) planeOfArrayIrradiance(hour ForecastHour, components IrradianceComponents, position SolarPosition, array PVArray) float64 {
+	if hour.TiltedPlane != nil && hour.TiltedPlane.Matches(array.Orientation()) {
+		return hour.GlobalTiltedIrradiance
+	}
+	return PlaneOfArrayIrradiance(
+		components,
+		position,
+		array.TiltDeg,
+		array.AzimuthDeg,
+		s.config.GroundAlbedo,
+		s.config.TranspositionModel,
+		hour.Hour,
+	)
+}
+
+// generateRecommendation generates actionable recommendation text in the
+// configured locale. Emails no longer show it; the webhook carries it as
+// recommended_action.
 func (s *SolarForecastService) generateRecommendation(analysis *AlertAnalysis) string {
 	if !analysis.CriteriaTriggered.AnyTriggered {
-		return "Solar production forecast looks normal. No action required."
+		return s.locale.T("Solar production forecast looks normal. No action required.")
 	}
 
 	if analysis.CriteriaTriggered.LowProductionDurationTriggered {
+		// The first duration rule describes the low production period
+		threshold := s.config.ProductionAlertThresholdKW
+		for _, result := range analysis.FiredRules {
+			if result.Type == RuleTypeDuration {
+				threshold = result.Threshold
+				break
+			}
+		}
 		timeWindow := analysis.FirstLowProductionHour.Format("15:04") + "-" + analysis.LastLowProductionHour.Format("15:04")
-		recommendation := fmt.Sprintf(
+		return s.locale.Sprintf(
 			"⚠️ Solar production will drop below %.1f kW for %d consecutive daylight hours during %s. "+
 				"Expect severely limited power output during this period. "+
 				"Consider reducing consumption or activating backup power sources. "+
 				"Analysis uses automatic daylight detection based on solar irradiance.",
-			s.config.ProductionAlertThresholdKW,
+			threshold,
 			analysis.ConsecutiveHourCount,
 			timeWindow,
 		)
-		return recommendation
 	}
 
-	return "Solar production forecast looks normal. No action required."
+	if len(analysis.FiredRules) > 0 {
+		return s.locale.Sprintf("⚠️ %s. Consider reducing consumption or shifting it to other days.",
+			strings.ReplaceAll(analysis.FiredRules[0].Message, "\n", " "))
+	}
+
+	return s.locale.T("Solar production forecast looks normal. No action required.")
 }
 
-// shouldSendAlert checks if alert should be sent based on current state
-func (s *SolarForecastService) shouldSendAlert(ctx context.Context) (bool, error) {
-	// Check if currently in daytime window (skip check in test mode)
-	now := time.Now()
-	if !s.config.TestMode {
-		// Calculate sunrise/sunset for today based on coordinates
-		sunrise, sunset := CalculateSunriseSunset(now, s.config.Latitude, s.config.Longitude)
-
-		if now.Before(sunrise) || now.After(sunset) {
-			s.logger.Debug("Outside daylight hours, skipping alert",
-				"current", now.Format("15:04"),
-				"sunrise", sunrise.Format("15:04"),
-				"sunset", sunset.Format("15:04"))
-			return false, nil
-		}
+// alertUpdate compares the analysis with the episode's last alert and returns the
+// material changes, or nil when there are none or today's update limit is reached
+func (s *SolarForecastService) alertUpdate(episode *AlertEpisode, analysis *AlertAnalysis, now time.Time) *AlertUpdate {
+	if s.config.AlertUpdateMaxPerDay <= 0 || !s.withinAlertHours(now) {
+		return nil
 	}
 
-	// Check if alert was already sent today
-	state, err := s.stateRepository.GetLastAlertDate(ctx)
-	if err != nil {
-		return false, err
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if sent := episode.NotificationCount(NotificationUpdate, today); sent >= s.config.AlertUpdateMaxPerDay {
+		s.logger.Debug("Alert update limit reached for today", "updates_sent", sent)
+		return nil
 	}
 
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tolerance := UpdateTolerance{Hours: s.config.AlertUpdateHourTolerance, KW: s.config.AlertUpdateKWTolerance}
+	if tolerance.Hours < 1 {
+		tolerance.Hours = DefaultAlertUpdateHourTolerance
+	}
+	if tolerance.KW <= 0 {
+		tolerance.KW = DefaultAlertUpdateKWTolerance
+	}
 
-	s.logger.Info("Alert deduplication check", "alert_sent", state.AlertSent, "last_alert_date", state.LastAlertDate.Format("2006-01-02"), "today", today.Format("2006-01-02"))
+	changes := NewAlertFingerprint(analysis).Changes(episode.Latest, tolerance, s.locale)
+	if len(changes) == 0 {
+		return nil
+	}
+	return &AlertUpdate{Previous: episode.Latest, Changes: changes}
+}
 
-	if state.AlertSent {
-		s.logger.Info("AlertSent is true, checking date", "is_zero", state.LastAlertDate.IsZero())
-		if !state.LastAlertDate.IsZero() {
-			lastAlertDate := time.Date(state.LastAlertDate.Year(), state.LastAlertDate.Month(), state.LastAlertDate.Day(), 0, 0, 0, 0, state.LastAlertDate.Location())
-			s.logger.Info("Comparing dates", "last_alert", lastAlertDate.Format("2006-01-02"), "today_compare", today.Format("2006-01-02"), "equal", lastAlertDate.Equal(today))
-			if lastAlertDate.Equal(today) {
-				s.logger.Info("Alert already sent today, skipping")
-				return false, nil
-			}
-		}
+// withinAlertHours reports whether notifications may be sent now: between sunrise
+// and sunset at the configured location, or always in test mode
+func (s *SolarForecastService) withinAlertHours(now time.Time) bool {
+	if s.config.TestMode {
+		return true
 	}
 
-	return true, nil
+	// Calculate sunrise/sunset for today based on coordinates
+	sunrise, sunset := CalculateSunriseSunset(now, s.config.Latitude, s.config.Longitude)
+
+	if now.Before(sunrise) || now.After(sunset) {
+		s.logger.Debug("Outside daylight hours, skipping alert",
+			"current", now.Format("15:04"),
+			"sunrise", sunrise.Format("15:04"),
+			"sunset", sunset.Format("15:04"))
+		return false
+	}
+	return true
 }
@@ -186,7 +186,7 @@ func TestTemperatureAdjustment(t *testing.T) {
 	}
 }
 
-func TestEvaluateLowProductionDuration(t *testing.T) {
+func TestDurationRule(t *testing.T) {
 	config := &Config{
 		ProductionAlertThresholdKW: 2.0,
 		DurationThresholdHours:     3, // Alert after 3 consecutive hours
@@ -198,6 +198,12 @@ func TestEvaluateLowProductionDuration(t *testing.T) {
 		logger: &mockLogger{},
 	}
 
+	rules, err := BuildAlertRules(config)
+	if err != nil || len(rules) != 1 {
+		t.Fatalf("BuildAlertRules = %v, %v; want one duration rule", rules, err)
+	}
+	rule := rules[0]
+
 	baseTime := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
 
 	tests := []struct {
@@ -273,7 +279,8 @@ func TestEvaluateLowProductionDuration(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			analysis := &AlertAnalysis{}
-			service.evaluateLowProductionDuration(tt.production, analysis)
+			result := rule.Evaluate(RuleInput{DaylightHours: tt.production, DaylightGHIThreshold: config.DaylightGHIThreshold})
+			service.applyRuleResult(result, analysis)
 
 			if analysis.CriteriaTriggered.LowProductionDurationTriggered != tt.wantTriggered {
 				t.Errorf("Triggered = %v, want %v",
@@ -329,3 +336,436 @@ func TestFilterAnalysisWindow(t *testing.T) {
 		}
 	}
 }
+
+func TestCalculateSolarProductionMultipleArrays(t *testing.T) {
+	config := &Config{
+		Latitude:           39.47,
+		Longitude:          -0.38,
+		InverterEfficiency: 1.0,
+		GroundAlbedo:       DefaultGroundAlbedo,
+		TranspositionModel: TranspositionHayDavies,
+		Arrays: []PVArray{
+			{Name: "east", CapacityKW: 4.0, TiltDeg: 30, AzimuthDeg: 90},
+			{Name: "west", CapacityKW: 4.0, TiltDeg: 30, AzimuthDeg: 270, LossPercent: 10},
+		},
+	}
+
+	service := &SolarForecastService{
+		config: config,
+		logger: &mockLogger{},
+	}
+
+	// 10:00 local (CEST) in June - the sun is in the south-east
+	morning := time.Date(2025, 6, 15, 10, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	result := service.calculateSolarProduction(ForecastHour{
+		Hour:                       morning,
+		GlobalHorizontalIrradiance: 600,
+		Temperature:                25,
+	})
+
+	if len(result.Arrays) != 2 {
+		t.Fatalf("len(Arrays) = %d, want 2", len(result.Arrays))
+	}
+
+	east, west := result.Arrays[0], result.Arrays[1]
+	if east.Name != "east" || west.Name != "west" {
+		t.Errorf("array names = %q, %q, want east, west", east.Name, west.Name)
+	}
+	if east.POA <= west.POA {
+		t.Errorf("east POA = %.1f, west POA = %.1f, want east > west in the morning", east.POA, west.POA)
+	}
+
+	sum := east.OutputKW + west.OutputKW
+	if diff := result.EstimatedOutputKW - sum; diff > 0.001 || diff < -0.001 {
+		t.Errorf("EstimatedOutputKW = %.3f, want sum of arrays %.3f", result.EstimatedOutputKW, sum)
+	}
+
+	// West array output must include its 10% loss factor
+	wantWest := 4.0 * west.POA / STCIrradiance * 0.9
+	if diff := west.OutputKW - wantWest; diff > 0.001 || diff < -0.001 {
+		t.Errorf("west OutputKW = %.3f, want %.3f", west.OutputKW, wantWest)
+	}
+}
+
+func TestCalculateSolarProductionProviderIrradiance(t *testing.T) {
+	config := &Config{
+		Latitude:           39.47,
+		Longitude:          -0.38,
+		InverterEfficiency: 1.0,
+		GroundAlbedo:       DefaultGroundAlbedo,
+		TranspositionModel: TranspositionHayDavies,
+		Arrays: []PVArray{
+			{Name: "south", CapacityKW: 5.0, TiltDeg: 30, AzimuthDeg: 180},
+			{Name: "east", CapacityKW: 5.0, TiltDeg: 30, AzimuthDeg: 90},
+		},
+	}
+
+	service := &SolarForecastService{
+		config: config,
+		logger: &mockLogger{},
+	}
+
+	noon := time.Date(2025, 3, 20, 13, 0, 0, 0, time.FixedZone("CET", 3600))
+	hour := ForecastHour{
+		Hour:                        noon,
+		GlobalHorizontalIrradiance:  500,
+		Temperature:                 25,
+		DirectNormalIrradiance:      0,
+		DiffuseHorizontalIrradiance: 500,
+		HasIrradianceComponents:     true,
+		GlobalTiltedIrradiance:      777,
+		TiltedPlane:                 &PanelOrientation{TiltDeg: 30, AzimuthDeg: 180},
+	}
+
+	result := service.calculateSolarProduction(hour)
+
+	// The south array matches the provider's tilted plane and uses GTI verbatim
+	if result.Arrays[0].POA != 777 {
+		t.Errorf("south POA = %.1f, want provider GTI 777", result.Arrays[0].POA)
+	}
+
+	// The east array is transposed from the provided (fully diffuse) components,
+	// so it can never exceed the horizontal diffuse plus ground reflection
+	if result.Arrays[1].POA <= 0 || result.Arrays[1].POA > 500 {
+		t.Errorf("east POA = %.1f, want diffuse-only value in (0, 500]", result.Arrays[1].POA)
+	}
+}
+
+func TestInverterOutput(t *testing.T) {
+	curve := []EfficiencyPoint{
+		{LoadFraction: 0.1, Efficiency: 0.90},
+		{LoadFraction: 0.5, Efficiency: 0.97},
+		{LoadFraction: 1.0, Efficiency: 0.96},
+	}
+	lowCurve := []EfficiencyPoint{
+		{LoadFraction: 0.1, Efficiency: 0.70},
+		{LoadFraction: 1.0, Efficiency: 0.80},
+	}
+
+	tests := []struct {
+		name        string
+		config      Config
+		dcKW        float64
+		wantAC      float64
+		wantClipped float64
+	}{
+		{
+			name:   "flat efficiency without limit",
+			config: Config{InverterEfficiency: 0.95},
+			dcKW:   6.0,
+			wantAC: 5.7,
+		},
+		{
+			name:        "flat efficiency clipped at AC limit",
+			config:      Config{InverterEfficiency: 1.0, InverterACLimitKW: 5.0},
+			dcKW:        6.0,
+			wantAC:      5.0,
+			wantClipped: 1.0,
+		},
+		{
+			name:   "curve interpolated between points",
+			config: Config{InverterEfficiency: 0.5, InverterACLimitKW: 10.0, InverterEfficiencyCurve: curve},
+			dcKW:   3.0 / 0.935, // 30% AC load: halfway between 0.90 and 0.97
+			wantAC: 3.0,
+		},
+		{
+			name:   "curve below first point uses first efficiency",
+			config: Config{InverterEfficiency: 0.5, InverterACLimitKW: 10.0, InverterEfficiencyCurve: curve},
+			dcKW:   0.5,
+			wantAC: 0.5 * 0.90,
+		},
+		{
+			// 5.70 kW DC on a 5 kW inverter is 114% by DC but only 90% by AC output
+			name:   "curve looked up at AC load when DC exceeds the AC rating",
+			config: Config{InverterEfficiency: 0.5, InverterACLimitKW: 5.0, InverterEfficiencyCurve: lowCurve},
+			dcKW:   4.5 / (0.70 + 0.1*0.8/0.9),
+			wantAC: 4.5,
+		},
+		{
+			name:        "curve at full load when clipping",
+			config:      Config{InverterEfficiency: 0.5, InverterACLimitKW: 5.0, InverterEfficiencyCurve: curve},
+			dcKW:        8.0,
+			wantAC:      5.0,
+			wantClipped: 8.0*0.96 - 5.0,
+		},
+		{
+			name:   "no power",
+			config: Config{InverterEfficiency: 0.97, InverterACLimitKW: 5.0},
+			dcKW:   0,
+			wantAC: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &SolarForecastService{config: &tt.config, logger: &mockLogger{}}
+			ac, clipped := service.inverterOutput(tt.dcKW, 8.0)
+			if diff := ac - tt.wantAC; diff > 0.001 || diff < -0.001 {
+				t.Errorf("AC = %.3f, want %.3f", ac, tt.wantAC)
+			}
+			if diff := clipped - tt.wantClipped; diff > 0.001 || diff < -0.001 {
+				t.Errorf("clipped = %.3f, want %.3f", clipped, tt.wantClipped)
+			}
+		})
+	}
+}
+
+func TestCalculateSolarProductionClipping(t *testing.T) {
+	config := &Config{
+		Latitude:           39.47,
+		Longitude:          -0.38,
+		InverterEfficiency: 1.0,
+		InverterACLimitKW:  3.0,
+		GroundAlbedo:       DefaultGroundAlbedo,
+		TranspositionModel: TranspositionHayDavies,
+		Arrays: []PVArray{
+			{Name: "east", CapacityKW: 4.0, TiltDeg: 30, AzimuthDeg: 90},
+			{Name: "west", CapacityKW: 4.0, TiltDeg: 30, AzimuthDeg: 270},
+		},
+	}
+	service := &SolarForecastService{config: config, logger: &mockLogger{}}
+
+	noon := time.Date(2025, 6, 21, 14, 0, 0, 0, time.FixedZone("CEST", 7200))
+	result := service.calculateSolarProduction(ForecastHour{
+		Hour:                       noon,
+		GlobalHorizontalIrradiance: 900,
+		Temperature:                25,
+	})
+
+	if !result.Clipped {
+		t.Fatalf("expected clipping, DC output %.2f kW", result.DCOutputKW)
+	}
+	if result.EstimatedOutputKW != 3.0 {
+		t.Errorf("EstimatedOutputKW = %.3f, want AC limit 3.0", result.EstimatedOutputKW)
+	}
+	if diff := result.DCOutputKW - result.EstimatedOutputKW - result.ClippedKW; diff > 0.001 || diff < -0.001 {
+		t.Errorf("DC %.3f - AC %.3f != clipped %.3f", result.DCOutputKW, result.EstimatedOutputKW, result.ClippedKW)
+	}
+
+	sum := result.Arrays[0].OutputKW + result.Arrays[1].OutputKW
+	if diff := sum - result.EstimatedOutputKW; diff > 0.001 || diff < -0.001 {
+		t.Errorf("array outputs sum to %.3f, want %.3f", sum, result.EstimatedOutputKW)
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	values := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		q    float64
+		want float64
+	}{
+		{0.1, 1.0},
+		{0.5, 5.0},
+		{0.9, 9.0},
+		{0.95, 9.5},
+		{1.0, 10.0},
+	}
+	for _, tt := range tests {
+		if got := quantile(values, tt.q); got != tt.want {
+			t.Errorf("quantile(%.2f) = %.2f, want %.2f", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeForecastEnsembleQuantile(t *testing.T) {
+	baseTime := time.Date(2025, 6, 21, 10, 0, 0, 0, time.UTC)
+
+	// Deterministic forecast is sunny; ensemble members disagree
+	forecast := &ForecastData{}
+	for h := 0; h < 4; h++ {
+		forecast.Hours = append(forecast.Hours, ForecastHour{
+			Hour:                       baseTime.Add(time.Duration(h) * time.Hour),
+			GlobalHorizontalIrradiance: 800,
+			Temperature:                25,
+		})
+	}
+
+	// 10 members: 8 overcast (GHI 100), 2 sunny (GHI 800)
+	ensemble := &EnsembleForecast{}
+	for m := 0; m < 10; m++ {
+		ghi := 100.0
+		if m >= 8 {
+			ghi = 800
+		}
+		member := EnsembleMember{Name: "test/member"}
+		for _, hour := range forecast.Hours {
+			member.Hours = append(member.Hours, ForecastHour{
+				Hour:                       hour.Hour,
+				GlobalHorizontalIrradiance: ghi,
+				Temperature:                25,
+			})
+		}
+		ensemble.Members = append(ensemble.Members, member)
+	}
+
+	tests := []struct {
+		name          string
+		quantile      AlertQuantile
+		wantTriggered bool
+	}{
+		{"deterministic forecast is sunny", AlertOnDeterministic, false},
+		{"median member is overcast", AlertOnP50, true},
+		{"sunny members keep P90 above threshold", AlertOnP90, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &SolarForecastService{
+				config: &Config{
+					RatedCapacityKW:            5.0,
+					InverterEfficiency:         1.0,
+					ProductionAlertThresholdKW: 2.0,
+					DurationThresholdHours:     3,
+					DaylightGHIThreshold:       50.0,
+					AlertQuantile:              tt.quantile,
+				},
+				logger: &mockLogger{},
+			}
+
+			analysis := service.analyzeForecast(forecast, ensemble)
+
+			if analysis.EnsembleMembers != 10 {
+				t.Errorf("EnsembleMembers = %d, want 10", analysis.EnsembleMembers)
+			}
+			if analysis.CriteriaTriggered.LowProductionDurationTriggered != tt.wantTriggered {
+				t.Errorf("triggered = %v, want %v", analysis.CriteriaTriggered.LowProductionDurationTriggered, tt.wantTriggered)
+			}
+
+			q := analysis.AllProductionHours[0].Quantiles
+			if q == nil {
+				t.Fatal("expected quantiles on production hours")
+			}
+			if q.P10 > q.P50 || q.P50 > q.P90 {
+				t.Errorf("quantiles not ordered: P10 %.2f, P50 %.2f, P90 %.2f", q.P10, q.P50, q.P90)
+			}
+		})
+	}
+}
+
+func TestAnalyzeForecastWithoutEnsemble(t *testing.T) {
+	service := &SolarForecastService{
+		config: &Config{
+			RatedCapacityKW:            5.0,
+			InverterEfficiency:         1.0,
+			ProductionAlertThresholdKW: 2.0,
+			DurationThresholdHours:     1,
+			DaylightGHIThreshold:       50.0,
+			AlertQuantile:              AlertOnP90,
+		},
+		logger: &mockLogger{},
+	}
+
+	forecast := &ForecastData{Hours: []ForecastHour{
+		{Hour: time.Date(2025, 6, 21, 10, 0, 0, 0, time.UTC), GlobalHorizontalIrradiance: 100, Temperature: 20},
+	}}
+
+	analysis := service.analyzeForecast(forecast, nil)
+
+	if analysis.AlertQuantile != AlertOnDeterministic {
+		t.Errorf("AlertQuantile = %q, want fallback to deterministic", analysis.AlertQuantile)
+	}
+	if !analysis.CriteriaTriggered.LowProductionDurationTriggered {
+		t.Error("expected deterministic low production to trigger")
+	}
+}
+
+func TestDailyEnergyTotals(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	start := time.Date(2025, 3, 20, 0, 0, 0, 0, loc)
+
+	// One complete day at 1 kW and the first 6 hours of the next day at 2 kW
+	var production []SolarProduction
+	for h := 0; h < 30; h++ {
+		kw := 1.0
+		if h >= 24 {
+			kw = 2.0
+		}
+		production = append(production, SolarProduction{Hour: start.Add(time.Duration(h) * time.Hour), EstimatedOutputKW: kw})
+	}
+
+	days := DailyEnergyTotals(production, AlertOnDeterministic)
+	if len(days) != 2 {
+		t.Fatalf("days = %d, want 2", len(days))
+	}
+	if days[0].EnergyKWh != 24 || !days[0].Complete {
+		t.Errorf("day 1 = %+v, want 24 kWh complete", days[0])
+	}
+	if days[1].EnergyKWh != 12 || days[1].Complete || days[1].Hours != 6 {
+		t.Errorf("day 2 = %+v, want 12 kWh over 6 incomplete hours", days[1])
+	}
+	if !days[1].Date.Equal(time.Date(2025, 3, 21, 0, 0, 0, 0, loc)) {
+		t.Errorf("day 2 date = %v", days[1].Date)
+	}
+}
+
+func TestDailyEnergyRule(t *testing.T) {
+	start := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+	day := func(offset int, kwh float64, complete bool) DailyEnergy {
+		return DailyEnergy{Date: start.AddDate(0, 0, offset), EnergyKWh: kwh, Hours: 24, Complete: complete}
+	}
+
+	tests := []struct {
+		name          string
+		threshold     float64
+		days          []DailyEnergy
+		wantTriggered bool
+		wantLowDays   int
+	}{
+		{
+			name:      "disabled",
+			threshold: 0,
+			days:      []DailyEnergy{day(0, 1, true)},
+		},
+		{
+			name:      "all days above threshold",
+			threshold: 10,
+			days:      []DailyEnergy{day(0, 20, true), day(1, 15, true)},
+		},
+		{
+			name:          "tomorrow below threshold",
+			threshold:     10,
+			days:          []DailyEnergy{day(0, 20, true), day(1, 5, true)},
+			wantTriggered: true,
+			wantLowDays:   1,
+		},
+		{
+			name:      "low day beyond checked days is ignored",
+			threshold: 10,
+			days:      []DailyEnergy{day(0, 20, true), day(1, 15, true), day(2, 5, true)},
+		},
+		{
+			name:          "incomplete days are skipped",
+			threshold:     10,
+			days:          []DailyEnergy{day(0, 2, false), day(1, 15, true), day(2, 5, true)},
+			wantTriggered: true,
+			wantLowDays:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				ProductionAlertThresholdKW:   2.0,
+				DurationThresholdHours:       6,
+				DailyEnergyAlertThresholdKWh: tt.threshold,
+				DailyEnergyAlertDays:         2,
+			}
+			service := &SolarForecastService{config: config, logger: &mockLogger{}}
+			rules, err := BuildAlertRules(config)
+			if err != nil {
+				t.Fatalf("BuildAlertRules: %v", err)
+			}
+			analysis := &AlertAnalysis{}
+			for _, rule := range rules {
+				service.applyRuleResult(rule.Evaluate(RuleInput{DailyEnergy: tt.days}), analysis)
+			}
+
+			if analysis.CriteriaTriggered.LowDailyEnergyTriggered != tt.wantTriggered {
+				t.Errorf("triggered = %v, want %v", analysis.CriteriaTriggered.LowDailyEnergyTriggered, tt.wantTriggered)
+			}
+			if len(analysis.LowEnergyDays) != tt.wantLowDays {
+				t.Errorf("low days = %d, want %d", len(analysis.LowEnergyDays), tt.wantLowDays)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package domain
+
+import "fmt"
+
+// Severity ranks how serious a fired alert rule is
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Rank orders severities from least (1) to most (3) serious; unknown values rank 0
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarning:
+		return 2
+	case SeverityCritical:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// ParseSeverity validates a configured severity name
+func ParseSeverity(value string) (Severity, error) {
+	s := Severity(value)
+	if s.Rank() == 0 {
+		return "", fmt.Errorf("severity must be info, warning or critical, got %q", value)
+	}
+	return s, nil
+}
+
+// Default severity tier boundaries
+const (
+	DefaultWarningDeficitPercent  = 40.0
+	DefaultCriticalDeficitPercent = 75.0
+	DefaultWarningDurationFactor  = 1.5
+	DefaultCriticalDurationFactor = 2.0
+)
+
+// SeverityTiers decides how loud a fired rule is from how far below its threshold
+// the forecast falls (deficit) and how long the condition lasts compared with the
+// rule's minimum duration (duration factor). Either measure can raise the tier.
+type SeverityTiers struct {
+	WarningDeficitPercent  float64 // Mean shortfall below threshold for warning (%)
+	CriticalDeficitPercent float64 // Mean shortfall below threshold for critical (%)
+	WarningDurationFactor  float64 // Duration as a multiple of the rule's minimum for warning
+	CriticalDurationFactor float64 // Duration as a multiple of the rule's minimum for critical
+}
+
+// DefaultSeverityTiers returns the default tier boundaries
+func DefaultSeverityTiers() SeverityTiers {
+	return SeverityTiers{
+		WarningDeficitPercent:  DefaultWarningDeficitPercent,
+		CriticalDeficitPercent: DefaultCriticalDeficitPercent,
+		WarningDurationFactor:  DefaultWarningDurationFactor,
+		CriticalDurationFactor: DefaultCriticalDurationFactor,
+	}
+}
+
+// Classify returns the tier for a shortfall (0-1 of the threshold) lasting
+// durationFactor times the rule's minimum duration. Unset tiers use the defaults.
+func (t SeverityTiers) Classify(deficit, durationFactor float64) Severity {
+	if t == (SeverityTiers{}) {
+		t = DefaultSeverityTiers()
+	}
+	deficitPercent := deficit * 100
+	switch {
+	case deficitPercent >= t.CriticalDeficitPercent || durationFactor >= t.CriticalDurationFactor:
+		return SeverityCritical
+	case deficitPercent >= t.WarningDeficitPercent || durationFactor >= t.WarningDurationFactor:
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+// Default Pushover emergency priority settings for critical alerts. Pushover
+// requires retry >= 30 seconds and expire <= 10800 seconds.
+const (
+	DefaultPushoverEmergencyRetrySeconds  = 60
+	DefaultPushoverEmergencyExpireSeconds = 3600
+)
+
+// SeverityRoutes lists the notification channels used for each severity
+type SeverityRoutes map[Severity][]string
+
+// DefaultSeverityRoutes sends info by email only and adds a push from warning up.
+// Critical pushes are sent at emergency priority by the push adapter.
+func DefaultSeverityRoutes() SeverityRoutes {
+	return SeverityRoutes{
+		SeverityInfo:     {ChannelEmail},
+		SeverityWarning:  {ChannelEmail, ChannelPush},
+		SeverityCritical: {ChannelEmail, ChannelPush},
+	}
+}
+
+// Includes reports whether alerts of the severity go to the channel
+func (r SeverityRoutes) Includes(severity Severity, channel string) bool {
+	for _, c := range r[severity] {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// RoutesTo reports whether alerts of the severity go to the named channel,
+// either listed by name or, for channels other than email, through the push group
+func (r SeverityRoutes) RoutesTo(severity Severity, channel string) bool {
+	return r.Includes(severity, channel) || (channel != ChannelEmail && r.Includes(severity, ChannelPush))
+}
@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSeverityTiersClassify(t *testing.T) {
+	tiers := DefaultSeverityTiers()
+
+	tests := []struct {
+		name           string
+		deficit        float64
+		durationFactor float64
+		want           Severity
+	}{
+		{"small short shortfall", 0.2, 1.0, SeverityInfo},
+		{"deep shortfall", 0.5, 1.0, SeverityWarning},
+		{"long shortfall", 0.2, 1.5, SeverityWarning},
+		{"near-total shortfall", 0.8, 1.0, SeverityCritical},
+		{"very long shortfall", 0.1, 2.0, SeverityCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tiers.Classify(tt.deficit, tt.durationFactor); got != tt.want {
+				t.Errorf("Classify(%.2f, %.2f) = %q, want %q", tt.deficit, tt.durationFactor, got, tt.want)
+			}
+		})
+	}
+
+	if got := (SeverityTiers{}).Classify(0.2, 1.0); got != SeverityInfo {
+		t.Errorf("zero tiers Classify = %q, want defaults (info)", got)
+	}
+}
+
+type stubWeather struct {
+	forecast *ForecastData
+	err      error
+}
+
+func (w *stubWeather) GetForecast(ctx context.Context, lat, lon float64) (*ForecastData, error) {
+	return w.forecast, w.err
+}
+
+type recordingEmail struct {
+	alerts, recoveries int
+	lastRecovery       *RecoverySummary
+}
+
+func (e *recordingEmail) SendAlert(ctx context.Context, analysis *AlertAnalysis) error {
+	e.alerts++
+	return nil
+}
+
+func (e *recordingEmail) SendRecoveryEmail(ctx context.Context, summary *RecoverySummary) error {
+	e.recoveries++
+	e.lastRecovery = summary
+	return nil
+}
+
+type recordingPush struct{ severities []Severity }
+
+func (p *recordingPush) SendNotification(ctx context.Context, title, message string, imageData []byte, severity Severity) error {
+	p.severities = append(p.severities, severity)
+	return nil
+}
+
+// detailedPush is a push notifier that lays out the alert fields itself
+type detailedPush struct {
+	recordingPush
+	analyses []*AlertAnalysis
+}
+
+func (p *detailedPush) SendAlertNotification(ctx context.Context, title, message string, analysis *AlertAnalysis, imageData []byte) error {
+	p.analyses = append(p.analyses, analysis)
+	return nil
+}
+
+// memoryState keeps alert episodes in memory
+type memoryState struct {
+	active *AlertEpisode
+	closed []AlertEpisode
+}
+
+func (m *memoryState) ActiveEpisode(ctx context.Context) (*AlertEpisode, error) {
+	if m.active == nil {
+		return nil, nil
+	}
+	episode := *m.active
+	return &episode, nil
+}
+
+func (m *memoryState) OpenEpisode(ctx context.Context, episode AlertEpisode) error {
+	m.active = &episode
+	return nil
+}
+
+func (m *memoryState) UpdateEpisode(ctx context.Context, episode AlertEpisode) error {
+	m.active = &episode
+	return nil
+}
+
+func (m *memoryState) CloseEpisode(ctx context.Context, id string, resolvedAt time.Time) error {
+	m.active.ResolvedAt = resolvedAt
+	m.closed = append(m.closed, *m.active)
+	m.active = nil
+	return nil
+}
+
+func TestCheckAndAlertRoutesBySeverity(t *testing.T) {
+	// Six daylight hours at a uniform GHI; production scales with it
+	forecastWithGHI := func(ghi float64) *ForecastData {
+		base := time.Date(2025, 6, 21, 9, 0, 0, 0, time.UTC)
+		forecast := &ForecastData{}
+		for h := 0; h < 6; h++ {
+			forecast.Hours = append(forecast.Hours, ForecastHour{
+				Hour:                       base.Add(time.Duration(h) * time.Hour),
+				GlobalHorizontalIrradiance: ghi,
+				Temperature:                25,
+			})
+		}
+		return forecast
+	}
+
+	tests := []struct {
+		name         string
+		ghi          float64
+		wantSeverity Severity
+		wantEmails   int
+		wantPushes   int
+	}{
+		// 5 kW × GHI/1000 against a 2 kW threshold
+		{"slight shortfall goes by email only", 300, SeverityInfo, 1, 0},
+		{"half shortfall adds a push", 200, SeverityWarning, 1, 1},
+		{"near-total shortfall pushes critical", 60, SeverityCritical, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := &recordingEmail{}
+			push := &recordingPush{}
+			service := NewSolarForecastService(
+				&Config{
+					TestMode:                   true,
+					RatedCapacityKW:            5.0,
+					InverterEfficiency:         1.0,
+					ProductionAlertThresholdKW: 2.0,
+					DurationThresholdHours:     6,
+					DaylightGHIThreshold:       50.0,
+				},
+				&stubWeather{forecast: forecastWithGHI(tt.ghi)},
+				nil, testNotifiers(email, push), nil, &memoryState{}, nil, &mockLogger{},
+			)
+
+			if err := service.CheckAndAlert(context.Background()); err != nil {
+				t.Fatalf("CheckAndAlert: %v", err)
+			}
+			if email.alerts != tt.wantEmails {
+				t.Errorf("emails = %d, want %d", email.alerts, tt.wantEmails)
+			}
+			if len(push.severities) != tt.wantPushes {
+				t.Fatalf("pushes = %d, want %d", len(push.severities), tt.wantPushes)
+			}
+			if tt.wantPushes > 0 && push.severities[0] != tt.wantSeverity {
+				t.Errorf("push severity = %q, want %q", push.severities[0], tt.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestCheckAndAlertPassesAnalysisToDetailedPush(t *testing.T) {
+	base := time.Date(2025, 6, 21, 9, 0, 0, 0, time.UTC)
+	forecast := &ForecastData{}
+	for h := 0; h < 6; h++ {
+		forecast.Hours = append(forecast.Hours, ForecastHour{
+			Hour:                       base.Add(time.Duration(h) * time.Hour),
+			GlobalHorizontalIrradiance: 60,
+			Temperature:                25,
+		})
+	}
+
+	push := &detailedPush{}
+	service := NewSolarForecastService(
+		&Config{
+			TestMode:                   true,
+			RatedCapacityKW:            5.0,
+			InverterEfficiency:         1.0,
+			ProductionAlertThresholdKW: 2.0,
+			DurationThresholdHours:     6,
+			DaylightGHIThreshold:       50.0,
+		},
+		&stubWeather{forecast: forecast},
+		nil, testNotifiers(&recordingEmail{}, push), nil, &memoryState{}, nil, &mockLogger{},
+	)
+
+	if err := service.CheckAndAlert(context.Background()); err != nil {
+		t.Fatalf("CheckAndAlert: %v", err)
+	}
+	if len(push.analyses) != 1 || len(push.severities) != 0 {
+		t.Fatalf("detailed alerts = %d, plain pushes = %d, want the alert with its analysis", len(push.analyses), len(push.severities))
+	}
+	if got := push.analyses[0]; got.Severity != SeverityCritical || got.ConsecutiveHourCount != 6 {
+		t.Errorf("analysis severity %q, %d hours, want critical for 6 hours", got.Severity, got.ConsecutiveHourCount)
+	}
+}
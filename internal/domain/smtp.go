@@ -0,0 +1,95 @@
+package domain
+
+// SMTPTLSMode selects how the connection to the mail server is secured
+type SMTPTLSMode string
+
+const (
+	// SMTPTLSNone sends everything in clear text; only for trusted local relays
+	SMTPTLSNone SMTPTLSMode = "none"
+
+	// SMTPTLSStartTLS upgrades a plain connection with STARTTLS (usually port 587)
+	SMTPTLSStartTLS SMTPTLSMode = "starttls"
+
+	// SMTPTLSImplicit uses TLS from the first byte (usually port 465)
+	SMTPTLSImplicit SMTPTLSMode = "tls"
+)
+
+// SMTPAuthMechanism selects how the client authenticates to the mail server
+type SMTPAuthMechanism string
+
+const (
+	SMTPAuthNone    SMTPAuthMechanism = "none"
+	SMTPAuthPlain   SMTPAuthMechanism = "plain"
+	SMTPAuthLogin   SMTPAuthMechanism = "login"
+	SMTPAuthCRAMMD5 SMTPAuthMechanism = "cram-md5"
+)
+
+// SMTPConfig describes the mail server used for email notifications
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	TLSMode  SMTPTLSMode
+	Auth     SMTPAuthMechanism
+	Username string
+	Password string
+	CAFile   string // PEM bundle trusted instead of the system roots, for private relays
+}
+
+// IsLocalhost reports whether a mail server name is the local host, the only
+// server PLAIN and LOGIN send credentials to without TLS, as net/smtp does
+func IsLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
+
+// DefaultSMTPPreset is used when no mail server is configured, as earlier
+// versions only supported Gmail
+const DefaultSMTPPreset = "gmail"
+
+// SMTPPresets holds server settings for common mail providers
+var SMTPPresets = map[string]SMTPConfig{
+	"gmail":    {Host: "smtp.gmail.com", Port: 587, TLSMode: SMTPTLSStartTLS, Auth: SMTPAuthPlain},
+	"fastmail": {Host: "smtp.fastmail.com", Port: 465, TLSMode: SMTPTLSImplicit, Auth: SMTPAuthPlain},
+}
+
+// WithPreset returns the config with unset server fields taken from a preset
+func (c SMTPConfig) WithPreset(preset SMTPConfig) SMTPConfig {
+	if c.Host == "" {
+		c.Host = preset.Host
+	}
+	if c.Port == 0 {
+		c.Port = preset.Port
+	}
+	if c.TLSMode == "" {
+		c.TLSMode = preset.TLSMode
+	}
+	if c.Auth == "" {
+		c.Auth = preset.Auth
+	}
+	return c
+}
+
+// WithDefaults returns the config with an unset TLS mode defaulting to STARTTLS,
+// an unset port to the TLS mode's standard port, and an unset auth mechanism
+// to PLAIN when credentials are given
+func (c SMTPConfig) WithDefaults() SMTPConfig {
+	if c.TLSMode == "" {
+		c.TLSMode = SMTPTLSStartTLS
+	}
+	if c.Port == 0 {
+		switch c.TLSMode {
+		case SMTPTLSNone:
+			c.Port = 25
+		case SMTPTLSImplicit:
+			c.Port = 465
+		default:
+			c.Port = 587
+		}
+	}
+	if c.Auth == "" {
+		c.Auth = SMTPAuthNone
+		if c.Username != "" || c.Password != "" {
+			c.Auth = SMTPAuthPlain
+		}
+	}
+	return c
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// ForecastSnapshot is the forecast and alert state at the end of a run.
+// Energy and power are on the alert's production basis.
+type ForecastSnapshot struct {
+	GeneratedAt time.Time
+
+	CurrentKW        float64 // Forecast output for the current hour (0 when the forecast does not cover it)
+	TodayKWh         float64 // Energy over the whole of today
+	TomorrowKWh      float64
+	TomorrowComplete bool // False when the forecast does not cover all of tomorrow
+
+	AlertActive   bool      // An alert episode is open
+	AlertSeverity Severity  // Severity of the open episode
+	NextRecovery  time.Time // When production is forecast to rise above the threshold (zero = none)
+
+	AlertQuantile AlertQuantile
+	Production    []SolarProduction // Forecast hours from the current hour on
+}
+
+// NewForecastSnapshot builds the snapshot of an analysis made at now, with the
+// episode open after the run's alerting (nil when none)
+func NewForecastSnapshot(analysis *AlertAnalysis, episode *AlertEpisode, now time.Time) *ForecastSnapshot {
+	snapshot := &ForecastSnapshot{
+		GeneratedAt:   now,
+		AlertQuantile: analysis.AlertQuantile,
+	}
+
+	// Days are counted in the forecast's time zone
+	if len(analysis.AllProductionHours) > 0 {
+		now = now.In(analysis.AllProductionHours[0].Hour.Location())
+	}
+	currentHour := now.Truncate(time.Hour)
+	for i, prod := range analysis.AllProductionHours {
+		if prod.Hour.Equal(currentHour) {
+			snapshot.CurrentKW = prod.OutputAt(analysis.AlertQuantile)
+		}
+		if !prod.Hour.Before(currentHour) && snapshot.Production == nil {
+			snapshot.Production = analysis.AllProductionHours[i:]
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tomorrow := today.AddDate(0, 0, 1)
+	for _, day := range analysis.DailyEnergy {
+		switch {
+		case day.Date.Equal(today):
+			snapshot.TodayKWh = day.EnergyKWh
+		case day.Date.Equal(tomorrow):
+			snapshot.TomorrowKWh = day.EnergyKWh
+			snapshot.TomorrowComplete = day.Complete
+		}
+	}
+
+	if episode != nil {
+		snapshot.AlertActive = true
+		snapshot.AlertSeverity = episode.Severity
+	}
+	if analysis.CriteriaTriggered.AnyTriggered && analysis.HasRecovery {
+		snapshot.NextRecovery = analysis.RecoveryHour
+	}
+	return snapshot
+}
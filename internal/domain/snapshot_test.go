@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewForecastSnapshot(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 20, 0, 0, time.UTC)
+	midnight := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	var production []SolarProduction
+	for h := 0; h < 48; h++ {
+		production = append(production, SolarProduction{Hour: midnight.Add(time.Duration(h) * time.Hour), EstimatedOutputKW: 0.5})
+	}
+	analysis := &AlertAnalysis{
+		CriteriaTriggered:  AlertCriteria{AnyTriggered: true},
+		AllProductionHours: production,
+		DailyEnergy:        DailyEnergyTotals(production, AlertOnDeterministic),
+		HasRecovery:        true,
+		RecoveryHour:       midnight.Add(14 * time.Hour),
+	}
+
+	snapshot := NewForecastSnapshot(analysis, &AlertEpisode{Severity: SeverityCritical}, now)
+	if snapshot.CurrentKW != 0.5 || snapshot.TodayKWh != 12 || snapshot.TomorrowKWh != 12 || !snapshot.TomorrowComplete {
+		t.Errorf("energy = %.1f kW, %.1f/%.1f kWh, complete %v", snapshot.CurrentKW, snapshot.TodayKWh, snapshot.TomorrowKWh, snapshot.TomorrowComplete)
+	}
+	if len(snapshot.Production) != 38 || !snapshot.Production[0].Hour.Equal(now.Truncate(time.Hour)) {
+		t.Errorf("production starts %v with %d hours, want the current hour and 38 hours", snapshot.Production[0].Hour, len(snapshot.Production))
+	}
+	if !snapshot.AlertActive || snapshot.AlertSeverity != SeverityCritical || !snapshot.NextRecovery.Equal(analysis.RecoveryHour) {
+		t.Errorf("alert = %v %s, recovery %v", snapshot.AlertActive, snapshot.AlertSeverity, snapshot.NextRecovery)
+	}
+
+	analysis.CriteriaTriggered.AnyTriggered = false
+	snapshot = NewForecastSnapshot(analysis, nil, now)
+	if snapshot.AlertActive || !snapshot.NextRecovery.IsZero() {
+		t.Errorf("alert = %v, recovery %v, want no alert", snapshot.AlertActive, snapshot.NextRecovery)
+	}
+}
+
+// recordingPublisher records the published snapshots
+type recordingPublisher struct {
+	snapshots []*ForecastSnapshot
+}
+
+func (p *recordingPublisher) PublishForecast(ctx context.Context, snapshot *ForecastSnapshot) error {
+	p.snapshots = append(p.snapshots, snapshot)
+	return nil
+}
+
+func TestCheckAndAlertPublishesForecast(t *testing.T) {
+	base := time.Now().Truncate(time.Hour).Add(time.Hour)
+	forecast := &ForecastData{}
+	for h := 0; h < 6; h++ {
+		forecast.Hours = append(forecast.Hours, ForecastHour{
+			Hour:                       base.Add(time.Duration(h) * time.Hour),
+			GlobalHorizontalIrradiance: 200,
+			Temperature:                25,
+		})
+	}
+
+	publisher := &recordingPublisher{}
+	service := NewSolarForecastService(
+		&Config{
+			TestMode:                   true,
+			RatedCapacityKW:            5.0,
+			InverterEfficiency:         1.0,
+			ProductionAlertThresholdKW: 2.0,
+			DurationThresholdHours:     6,
+			DaylightGHIThreshold:       50.0,
+		},
+		&stubWeather{forecast: forecast},
+		nil, testNotifiers(&recordingEmail{}, nil), publisher, &memoryState{}, nil, &mockLogger{},
+	)
+
+	if err := service.CheckAndAlert(context.Background()); err != nil {
+		t.Fatalf("CheckAndAlert: %v", err)
+	}
+	if len(publisher.snapshots) != 1 {
+		t.Fatalf("snapshots = %d, want 1", len(publisher.snapshots))
+	}
+	if got := publisher.snapshots[0]; !got.AlertActive || len(got.Production) != 6 {
+		t.Errorf("snapshot alert %v with %d hours, want the alert opened by the run and 6 hours", got.AlertActive, len(got.Production))
+	}
+}
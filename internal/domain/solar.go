@@ -43,6 +43,48 @@ func julianDay(year, month, day int) float64 {
 
 // calculateSunTimes calculates sunrise and sunset Julian day values
 func calculateSunTimes(jd, latitude, longitude float64) (sunriseJD, sunsetJD float64) {
+	sunDeclin, eqTime := solarDeclinationAndEquationOfTime(jd)
+
+	// Hour angle at sunrise/sunset
+	// Uses standard refraction of 0.833 degrees (50 arcminutes)
+	latRad := latitude * math.Pi / 180
+	zenith := 90.833 * math.Pi / 180 // Standard refraction
+
+	cosHA := (math.Cos(zenith) / (math.Cos(latRad) * math.Cos(sunDeclin))) -
+		math.Tan(latRad)*math.Tan(sunDeclin)
+
+	// Check for polar day/night
+	if cosHA > 1 {
+		// Sun never rises (polar night)
+		// Return noon as both sunrise and sunset
+		noon := jd + (720-longitude*4-eqTime)/1440
+		return noon, noon
+	}
+	if cosHA < -1 {
+		// Sun never sets (polar day)
+		// Return midnight and next midnight
+		return jd - 0.5, jd + 0.5
+	}
+
+	ha := math.Acos(cosHA) * 180 / math.Pi
+
+	// Solar noon (in minutes from midnight UTC)
+	solarNoon := 720 - longitude*4 - eqTime
+
+	// Sunrise and sunset times (in minutes from midnight UTC)
+	sunriseMinutes := solarNoon - ha*4
+	sunsetMinutes := solarNoon + ha*4
+
+	// Convert to Julian day
+	sunriseJD = jd + sunriseMinutes/1440
+	sunsetJD = jd + sunsetMinutes/1440
+
+	return sunriseJD, sunsetJD
+}
+
+// solarDeclinationAndEquationOfTime returns the sun's declination (radians) and the
+// equation of time (minutes) for a given Julian day, following the NOAA spreadsheet
+func solarDeclinationAndEquationOfTime(jd float64) (sunDeclin, eqTime float64) {
 	// Julian century
 	t := (jd - 2451545.0) / 36525.0
 
@@ -75,53 +117,19 @@ func calculateSunTimes(jd, latitude, longitude float64) (sunriseJD, sunsetJD flo
 	obliqCorr := obliq + 0.00256*math.Cos(omega*math.Pi/180)
 
 	// Sun's declination
-	sunDeclin := math.Asin(math.Sin(obliqCorr*math.Pi/180) * math.Sin(sunAppLon*math.Pi/180))
+	sunDeclin = math.Asin(math.Sin(obliqCorr*math.Pi/180) * math.Sin(sunAppLon*math.Pi/180))
 
 	// Equation of time (minutes)
 	y := math.Tan(obliqCorr * math.Pi / 360)
 	y = y * y
 	l0Rad := l0 * math.Pi / 180
-	eqTime := 4 * (y*math.Sin(2*l0Rad) -
+	eqTime = 4 * (y*math.Sin(2*l0Rad) -
 		2*e*math.Sin(mRad) +
 		4*e*y*math.Sin(mRad)*math.Cos(2*l0Rad) -
 		0.5*y*y*math.Sin(4*l0Rad) -
 		1.25*e*e*math.Sin(2*mRad)) * 180 / math.Pi
 
-	// Hour angle at sunrise/sunset
-	// Uses standard refraction of 0.833 degrees (50 arcminutes)
-	latRad := latitude * math.Pi / 180
-	zenith := 90.833 * math.Pi / 180 // Standard refraction
-
-	cosHA := (math.Cos(zenith) / (math.Cos(latRad) * math.Cos(sunDeclin))) -
-		math.Tan(latRad)*math.Tan(sunDeclin)
-
-	// Check for polar day/night
-	if cosHA > 1 {
-		// Sun never rises (polar night)
-		// Return noon as both sunrise and sunset
-		noon := jd + (720-longitude*4-eqTime)/1440
-		return noon, noon
-	}
-	if cosHA < -1 {
-		// Sun never sets (polar day)
-		// Return midnight and next midnight
-		return jd - 0.5, jd + 0.5
-	}
-
-	ha := math.Acos(cosHA) * 180 / math.Pi
-
-	// Solar noon (in minutes from midnight UTC)
-	solarNoon := 720 - longitude*4 - eqTime
-
-	// Sunrise and sunset times (in minutes from midnight UTC)
-	sunriseMinutes := solarNoon - ha*4
-	sunsetMinutes := solarNoon + ha*4
-
-	// Convert to Julian day
-	sunriseJD = jd + sunriseMinutes/1440
-	sunsetJD = jd + sunsetMinutes/1440
-
-	return sunriseJD, sunsetJD
+	return sunDeclin, eqTime
 }
 
 // julianDayToTime converts a Julian day to a time.Time in the given location
@@ -139,3 +147,49 @@ func IsDaylight(t time.Time, latitude, longitude float64) bool {
 	sunrise, sunset := CalculateSunriseSunset(t, latitude, longitude)
 	return t.After(sunrise) && t.Before(sunset)
 }
+
+// SolarPosition describes where the sun is in the sky at a given instant
+type SolarPosition struct {
+	ElevationDeg float64 // Angle above the horizon (negative at night)
+	AzimuthDeg   float64 // Compass bearing, 0 = north, 90 = east, 180 = south, 270 = west
+}
+
+// ZenithDeg returns the angle between the sun and the vertical
+func (p SolarPosition) ZenithDeg() float64 {
+	return 90 - p.ElevationDeg
+}
+
+// CalculateSolarPosition calculates the sun's elevation and azimuth for an instant and location.
+// Uses the same NOAA algorithm as CalculateSunriseSunset (atmospheric refraction is ignored,
+// which is well below the resolution of hourly irradiance data).
+func CalculateSolarPosition(t time.Time, latitude, longitude float64) SolarPosition {
+	utc := t.UTC()
+	jd := float64(utc.UnixNano())/1e9/86400 + 2440587.5
+
+	sunDeclin, eqTime := solarDeclinationAndEquationOfTime(jd)
+
+	// True solar time (minutes) and hour angle (degrees, negative in the morning)
+	minutesUTC := float64(utc.Hour()*60+utc.Minute()) + float64(utc.Second())/60
+	trueSolarTime := math.Mod(minutesUTC+eqTime+4*longitude, 1440)
+	if trueSolarTime < 0 {
+		trueSolarTime += 1440
+	}
+	hourAngle := trueSolarTime/4 - 180
+
+	latRad := latitude * math.Pi / 180
+	haRad := hourAngle * math.Pi / 180
+
+	cosZenith := math.Sin(latRad)*math.Sin(sunDeclin) +
+		math.Cos(latRad)*math.Cos(sunDeclin)*math.Cos(haRad)
+	cosZenith = math.Max(-1, math.Min(1, cosZenith))
+	zenith := math.Acos(cosZenith) * 180 / math.Pi
+
+	azimuth := math.Atan2(math.Sin(haRad),
+		math.Cos(haRad)*math.Sin(latRad)-math.Tan(sunDeclin)*math.Cos(latRad))*180/math.Pi + 180
+	azimuth = math.Mod(azimuth, 360)
+
+	return SolarPosition{
+		ElevationDeg: 90 - zenith,
+		AzimuthDeg:   azimuth,
+	}
+}
@@ -0,0 +1,82 @@
+package domain
+
+import "math"
+
+// CellTemperatureModel selects how PV cell temperature is estimated from weather data
+type CellTemperatureModel string
+
+const (
+	// CellTempModelAir uses the 2 m air temperature directly (legacy behaviour, underestimates heating)
+	CellTempModelAir CellTemperatureModel = "air"
+
+	// CellTempModelNOCT scales heating from the module's Nominal Operating Cell Temperature,
+	// adjusted for wind speed
+	CellTempModelNOCT CellTemperatureModel = "noct"
+
+	// CellTempModelFaiman uses the Faiman heat loss model (IEC 61853-2)
+	CellTempModelFaiman CellTemperatureModel = "faiman"
+
+	// CellTempModelSandia uses the Sandia (King et al. 2004) module/cell temperature model
+	CellTempModelSandia CellTemperatureModel = "sandia"
+)
+
+// Default cell temperature model coefficients
+const (
+	// DefaultWindSpeed is used when the provider reports no wind (m/s, the NOCT reference wind)
+	DefaultWindSpeed = 1.0
+
+	// DefaultNOCT is a typical Nominal Operating Cell Temperature for crystalline modules (°C)
+	DefaultNOCT = 45.0
+
+	// DefaultFaimanU0 and DefaultFaimanU1 are the PVsyst/IEC defaults for free-standing modules
+	DefaultFaimanU0 = 25.0 // W/(m²·K)
+	DefaultFaimanU1 = 6.84 // W·s/(m³·K)
+
+	// DefaultSandiaA, DefaultSandiaB and DefaultSandiaDeltaT are for open-rack glass/cell/polymer modules
+	DefaultSandiaA      = -3.56
+	DefaultSandiaB      = -0.075
+	DefaultSandiaDeltaT = 3.0 // °C difference between cell and back surface at 1000 W/m²
+)
+
+// CellTemperatureConfig holds the selected model and its coefficients
+type CellTemperatureConfig struct {
+	Model CellTemperatureModel
+
+	NOCT float64 // NOCT model: nominal operating cell temperature (°C)
+
+	FaimanU0 float64 // Faiman model: constant heat loss factor
+	FaimanU1 float64 // Faiman model: wind-dependent heat loss factor
+
+	SandiaA      float64 // Sandia model: upper limit of module temperature at low wind
+	SandiaB      float64 // Sandia model: rate at which temperature drops with wind
+	SandiaDeltaT float64 // Sandia model: cell to module back temperature difference
+}
+
+// CellTemperature estimates the PV cell temperature (°C) from air temperature (°C),
+// plane-of-array irradiance (W/m²) and wind speed at 10 m (m/s)
+func CellTemperature(cfg CellTemperatureConfig, airTemp, poa, windSpeed float64) float64 {
+	if poa <= 0 {
+		return airTemp
+	}
+	windSpeed = math.Max(windSpeed, 0)
+
+	switch cfg.Model {
+	case CellTempModelNOCT:
+		// Tc = Ta + (NOCT − 20)/800 × G, with the heat transfer scaled from the
+		// NOCT reference wind of 1 m/s: 9.5 / (5.7 + 3.8·v)
+		windFactor := 9.5 / (5.7 + 3.8*windSpeed)
+		return airTemp + (cfg.NOCT-20)/800*poa*windFactor
+
+	case CellTempModelFaiman:
+		// Tc = Ta + G / (U0 + U1·v)
+		return airTemp + poa/(cfg.FaimanU0+cfg.FaimanU1*windSpeed)
+
+	case CellTempModelSandia:
+		// Tm = G·exp(a + b·v) + Ta ; Tc = Tm + G/1000 × ΔT
+		moduleTemp := poa*math.Exp(cfg.SandiaA+cfg.SandiaB*windSpeed) + airTemp
+		return moduleTemp + poa/STCIrradiance*cfg.SandiaDeltaT
+
+	default: // Air temperature (legacy)
+		return airTemp
+	}
+}